@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/patrikhson/unicode-idn-diff/pkg/idndiff"
+)
+
+// Runs the compare command, which compares two versions and writes the
+// report. It is also what runs without a command.
+func compareMain(args []string) {
+	flags := flag.NewFlagSet("compare", flag.ExitOnError)
+	var opts idndiff.Options
+	var renderOpts idndiff.RenderOptions
+	dataDir := compareFlags(flags, &opts)
+	renderFlags(flags, &renderOpts)
+	flags.Func("glossary", "file with lines like \"PVALID ; PROTOCOL VALID\" naming how identifiers are written in the prose of the summary, optionally followed by \"; text, html\" for only some formats; tables and appendices keep the identifiers", func(path string) error {
+		glossary, err := idndiff.ReadGlossaryFile(path)
+		renderOpts.Glossary = glossary
+		return err
+	})
+	flags.IntVar(&renderOpts.LineWidth, "width", 0, "fold lines of the text report longer than this, such as 72 for Internet-Drafts, with continuation lines starting with white space (0 for no limit)")
+	flags.IntVar(&renderOpts.MaxEntries, "max-entries", 0, "list at most this many entries of each appendix in the text report, and write all of them to an overflow file the report points to (0 for no limit)")
+	formatList := flags.String("format", "text", "comma separated output formats: "+strings.Join(slices.Sorted(maps.Keys(idndiff.Formats)), ", "))
+	output := flags.String("o", "", "write the report to this name plus the extension of each format, instead of to standard output")
+	workdir := flags.String("workdir", "", "write the report in every format, the changes as CSV, a log and a manifest with the checksums of the input files to a new directory under this one, named by the versions and the time")
+	splitOutput := flags.String("split-output", "", "write the summary and each appendix of the text report to its own file in this directory: summary.txt, A.txt, B.txt and so on")
+	tablesDir := flags.String("appendix-tables", "", "also write each of the appendices A-F as a table with a header line to this directory, as A.csv, B.csv and so on, for spreadsheets")
+	tablesFormat := flags.String("appendix-tables-format", "csv", "format of the -appendix-tables files: csv or tsv")
+	zipped := flags.Bool("zip", false, "write the -workdir directory as a zip archive")
+	flags.BoolVar(&porcelainMode, "porcelain", false, "print the summary as stable tab separated lines for scripts, as the format porcelain, instead of the text report; reports are still written with -o, -workdir or -split-output")
+	profile := flags.String("profile", "", "named set of flags for an audience: "+strings.Join(slices.Sorted(maps.Keys(profiles)), ", ")+"; flags given explicitly take precedence")
+	args = parseArgs(flags, args)
+	if *profile != "" {
+		if err := applyProfile(flags, *profile); err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+	}
+
+	// Check if exactly two arguments are provided
+	if len(args) != 2 {
+		printUsage()
+		flags.PrintDefaults()
+		return
+	}
+
+	version1 := args[0]
+	version2 := args[1]
+
+	// Check if the versions are valid
+	if !validVersions(version1, version2) {
+		return
+	}
+
+	names, err := parseFormats(*formatList)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return
+	}
+	if *tablesFormat != "csv" && *tablesFormat != "tsv" {
+		fmt.Printf("Error: unknown appendix table format %q, expected csv or tsv\n", *tablesFormat)
+		return
+	}
+
+	// Compare once, and render each of the formats from the result
+	started := time.Now().UTC()
+	loader := newLoader(*dataDir)
+	report, err := idndiff.Compare(loader, version1, version2, opts)
+	if err != nil {
+		fmt.Printf("Error %s\n", err)
+		os.Exit(1)
+	}
+	if *workdir != "" {
+		path, err := writeWorkdir(*workdir, *zipped, report, renderOpts, loader, os.Args, started)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			os.Exit(1)
+		}
+		reportWritten(path)
+	} else if *splitOutput != "" {
+		renderOpts.OverflowFile = func(letter string) string { return letter + "-full.txt" }
+		if err := writeSplitOutput(*splitOutput, report, renderOpts); err != nil {
+			fmt.Printf("Error: %s\n", err)
+			os.Exit(1)
+		}
+		if err := writeOverflow(*splitOutput, report, renderOpts, true); err != nil {
+			fmt.Printf("Error: %s\n", err)
+			os.Exit(1)
+		}
+	} else if *output != "" || !porcelainMode {
+		// The overflow files are next to the report
+		if *output != "" {
+			renderOpts.OverflowFile = func(letter string) string { return filepath.Base(*output) + "-" + letter + ".txt" }
+		}
+		if err := writeReports(report, renderOpts, names, *output); err != nil {
+			fmt.Printf("Error: %s\n", err)
+		}
+		if err := writeOverflow(filepath.Dir(*output), report, renderOpts, *output != ""); err != nil {
+			fmt.Printf("Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
+	if *tablesDir != "" {
+		if err := writeAppendixTables(*tablesDir, report, *tablesFormat); err != nil {
+			fmt.Printf("Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
+	if porcelainMode {
+		idndiff.RenderPorcelain(os.Stdout, report)
+	}
+}
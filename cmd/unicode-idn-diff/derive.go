@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/patrikhson/unicode-idn-diff/pkg/idndiff"
+)
+
+// Runs the generate command, formerly derive, which writes allcodepoints.txt
+// for a version, or nfk.txt with -nfk
+func deriveMain(args []string) {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	dataDir := flags.String("data", ".", "directory or http(s) URL with one subdirectory per version")
+	output := flags.String("o", "", "write to this file instead of to standard output")
+	nfk := flags.Bool("nfk", false, "write nfk.txt, the NFKC normalization of every code point, instead of allcodepoints.txt")
+	tableFlags(flags)
+	errataFlags(flags)
+	duplicateFlags(flags)
+	args = parseArgs(flags, args)
+
+	if len(args) != 1 {
+		fmt.Println("Usage: unicode-idn-diff generate [flags] <version>")
+		flags.PrintDefaults()
+		return
+	}
+	version := args[0]
+	if !unicodeVersionRegex.MatchString(version) {
+		fmt.Println("Invalid version format. Please use the format 12.0.0")
+		return
+	}
+
+	w := io.Writer(os.Stdout)
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Printf("Error %s\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		w = file
+	}
+	loader := newLoader(*dataDir)
+	write := idndiff.WriteDerivedTable
+	if *nfk {
+		write = idndiff.WriteNFKTable
+	}
+	if err := write(w, loader, version); err != nil {
+		fmt.Printf("Error %s\n", err)
+		os.Exit(1)
+	}
+	for _, warning := range loader.DuplicateWarnings(version) {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Downloads files from unicode.org or its mirrors, trying the mirrors in
+// order and retrying failures with exponential backoff. The mirrors are
+// assumed to have the same files, so a partial download from one is resumed
+// from another.
+type downloader struct {
+	client   *http.Client
+	baseURLs []string      // The Public directory of unicode.org and its mirrors
+	retries  int           // Retries after the first attempt at each mirror
+	backoff  time.Duration // Delay before the first retry, doubled for each one after it
+	sleep    func(time.Duration)
+}
+
+// Downloads the file at the path under the Public directory to localPath.
+// The file is written to localPath.part first, which is kept on failures so
+// that the next attempt, or the next run, resumes it rather than starting
+// over. It returns an error wrapping errNotFound if no mirror has the file.
+func (d *downloader) fetch(filePath, localPath string) error {
+	delay := d.backoff
+	var errs []error
+	for attempt := 0; attempt <= d.retries; attempt++ {
+		if attempt > 0 {
+			d.sleep(delay)
+			delay *= 2
+		}
+		errs = errs[:0]
+		notFound := 0
+		for _, baseURL := range d.baseURLs {
+			url := strings.TrimSuffix(baseURL, "/") + "/" + filePath
+			err := d.downloadTo(url, localPath)
+			if err == nil {
+				return nil
+			}
+			if errors.Is(err, errNotFound) {
+				notFound++
+			}
+			errs = append(errs, err)
+		}
+		// A file that no mirror has is not there to retry for
+		if notFound == len(d.baseURLs) {
+			return errs[0]
+		}
+	}
+	return fmt.Errorf("after %d attempts: %w", d.retries+1, errors.Join(errs...))
+}
+
+// Downloads a URL to localPath through localPath.part, asking only for the
+// rest of the file if part of it is already there
+func (d *downloader) downloadTo(url, localPath string) error {
+	partPath := localPath + ".part"
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_WRONLY | os.O_CREATE
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		flags |= os.O_APPEND
+	case http.StatusOK:
+		// The server sends the whole file, ignoring the range
+		flags |= os.O_TRUNC
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The partial file is as long as the file or longer, so it is not
+		// a prefix of it that can be trusted
+		os.Remove(partPath)
+		return fmt.Errorf("%s: partial download does not match, starting over", url)
+	case http.StatusNotFound:
+		return fmt.Errorf("%s: %w", url, errNotFound)
+	default:
+		return fmt.Errorf("%s: unexpected HTTP status %s", url, resp.Status)
+	}
+
+	file, err := os.OpenFile(partPath, flags, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		file.Close()
+		return fmt.Errorf("%s: %w", url, err)
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(partPath, localPath)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// A file is resumed from the partial download when the server sends the rest
+// of it, started over when it sends all of it or the partial download does
+// not fit, and fetched from the next mirror when one fails
+func TestDownloader(t *testing.T) {
+	content := strings.Repeat("0041;LATIN CAPITAL LETTER A;Lu;0;L;;;;;N;;;;0061;\n", 100)
+	serveContent := func(w http.ResponseWriter, req *http.Request) {
+		http.ServeContent(w, req, "UnicodeData.txt", time.Time{}, strings.NewReader(content))
+	}
+	ignoreRange := func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(content))
+	}
+	failing := func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}
+	for _, test := range []struct {
+		name     string
+		mirrors  []http.HandlerFunc
+		part     string // The partial download before the fetch
+		statuses []int  // The responses of the mirrors, in order
+		retries  int
+		notFound bool
+	}{
+		{"whole file", []http.HandlerFunc{serveContent}, "", []int{http.StatusOK}, 0, false},
+		{"resumed", []http.HandlerFunc{serveContent}, content[:1000], []int{http.StatusPartialContent}, 0, false},
+		{"range ignored", []http.HandlerFunc{ignoreRange}, content[:1000], []int{http.StatusOK}, 0, false},
+		// The partial download is longer than the file, so the first attempt
+		// removes it, and the retry starts over
+		{"range not satisfiable", []http.HandlerFunc{serveContent}, content + "more", []int{http.StatusRequestedRangeNotSatisfiable, http.StatusOK}, 1, false},
+		{"next mirror", []http.HandlerFunc{failing, serveContent}, "", []int{http.StatusServiceUnavailable, http.StatusOK}, 0, false},
+		// Not retried, as no mirror has the file
+		{"not found", []http.HandlerFunc{http.NotFound, http.NotFound}, "", []int{http.StatusNotFound, http.StatusNotFound}, 0, true},
+	} {
+		var mu sync.Mutex
+		var statuses []int
+		var sleeps []time.Duration
+		d := &downloader{client: http.DefaultClient, retries: 2, backoff: time.Second, sleep: func(delay time.Duration) { sleeps = append(sleeps, delay) }}
+		for _, mirror := range test.mirrors {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				recorder := httptest.NewRecorder()
+				mirror(recorder, req)
+				mu.Lock()
+				statuses = append(statuses, recorder.Code)
+				mu.Unlock()
+				for key, values := range recorder.Header() {
+					w.Header()[key] = values
+				}
+				w.WriteHeader(recorder.Code)
+				w.Write(recorder.Body.Bytes())
+			}))
+			defer server.Close()
+			d.baseURLs = append(d.baseURLs, server.URL+"/Public/")
+		}
+
+		localPath := filepath.Join(t.TempDir(), "UnicodeData.txt")
+		if test.part != "" {
+			if err := os.WriteFile(localPath+".part", []byte(test.part), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		err := d.fetch("17.0.0/ucd/UnicodeData.txt", localPath)
+		if test.notFound {
+			if !errors.Is(err, errNotFound) {
+				t.Errorf("%s: got %v, want an error wrapping errNotFound", test.name, err)
+			}
+		} else if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		} else if data, err := os.ReadFile(localPath); err != nil || !bytes.Equal(data, []byte(content)) {
+			t.Errorf("%s: downloaded %d bytes (%v), want %d", test.name, len(data), err, len(content))
+		}
+		if !slices.Equal(statuses, test.statuses) {
+			t.Errorf("%s: the mirrors answered %v, want %v", test.name, statuses, test.statuses)
+		}
+		if len(sleeps) != test.retries {
+			t.Errorf("%s: retried after %v, want %d retries", test.name, sleeps, test.retries)
+		}
+		if _, err := os.Stat(localPath + ".part"); err == nil && !test.notFound {
+			t.Errorf("%s: the partial download is left behind", test.name)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/patrikhson/unicode-idn-diff/pkg/idndiff"
+)
+
+// Runs the exceptions command, which writes only the proposed additions to
+// Exceptions (F)
+func exceptionsMain(args []string) {
+	flags := flag.NewFlagSet("exceptions", flag.ExitOnError)
+	var opts idndiff.Options
+	dataDir := compareFlags(flags, &opts)
+	output := flags.String("o", "", "write to this file instead of to standard output")
+	args = parseArgs(flags, args)
+
+	if len(args) != 2 {
+		fmt.Println("Usage: unicode-idn-diff exceptions [flags] <version1> <version2>")
+		flags.PrintDefaults()
+		return
+	}
+	if !validVersions(args[0], args[1]) {
+		return
+	}
+
+	report, err := idndiff.Compare(newLoader(*dataDir), args[0], args[1], opts)
+	if err != nil {
+		fmt.Printf("Error %s\n", err)
+		os.Exit(1)
+	}
+
+	w := io.Writer(os.Stdout)
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Printf("Error %s\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		w = file
+	}
+	if err := idndiff.WriteExceptionAdditions(w, report); err != nil {
+		fmt.Printf("Error %s\n", err)
+		os.Exit(1)
+	}
+}
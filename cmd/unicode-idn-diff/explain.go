@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/patrikhson/unicode-idn-diff/pkg/idndiff"
+)
+
+// Runs the explain command, which writes how the derived property value of
+// a code point is derived in a version, rule by rule
+func explainMain(args []string) {
+	flags := flag.NewFlagSet("explain", flag.ExitOnError)
+	dataDir := flags.String("data", ".", "directory or http(s) URL with one subdirectory per version")
+	format := flags.String("format", "text", "output format: text or json")
+	tableFlags(flags)
+	errataFlags(flags)
+	duplicateFlags(flags)
+	args = parseArgs(flags, args)
+
+	if len(args) != 2 {
+		fmt.Println("Usage: unicode-idn-diff explain [flags] <code point> <version>")
+		flags.PrintDefaults()
+		return
+	}
+	codepoint, err := strconv.ParseInt(strings.TrimPrefix(strings.ToUpper(args[0]), "U+"), 16, 32)
+	if err != nil || codepoint < 0 || codepoint > 0x10FFFF {
+		fmt.Printf("Invalid code point %q, use hexadecimal digits, as in 0B55 or U+0B55\n", args[0])
+		return
+	}
+	version := args[1]
+	if !unicodeVersionRegex.MatchString(version) {
+		fmt.Println("Invalid version format. Please use the format 12.0.0")
+		return
+	}
+	write := idndiff.WriteExplanationText
+	switch *format {
+	case "text":
+	case "json":
+		write = idndiff.WriteExplanationJSON
+	default:
+		fmt.Printf("Error: unknown output format %q\n", *format)
+		return
+	}
+
+	explanation, err := idndiff.Explain(newLoader(*dataDir), version, int(codepoint))
+	if err != nil {
+		fmt.Printf("Error %s\n", err)
+		os.Exit(1)
+	}
+	if err := write(os.Stdout, explanation); err != nil {
+		fmt.Printf("Error %s\n", err)
+		os.Exit(1)
+	}
+}
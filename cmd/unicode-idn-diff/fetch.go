@@ -0,0 +1,171 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/patrikhson/unicode-idn-diff/pkg/idndiff"
+)
+
+// The files that fetch downloads, by their path under the Public directory
+// of unicode.org with %s for the version. The required ones are needed to
+// generate allcodepoints.txt and for every comparison; the others are only
+// needed by some sections, and are skipped if they are not published for the
+// version.
+var fetchedFiles = []struct {
+	path     string
+	required bool
+}{
+	{"%s/ucd/UnicodeData.txt", true},
+	{"%s/ucd/extracted/DerivedGeneralCategory.txt", true},
+	{"%s/ucd/extracted/DerivedBidiClass.txt", false},
+	{"%s/ucd/extracted/DerivedJoiningType.txt", false},
+	{"%s/ucd/extracted/DerivedCombiningClass.txt", false},
+	{"%s/ucd/DerivedNormalizationProps.txt", true},
+	{"%s/ucd/CaseFolding.txt", false},
+	{"%s/ucd/DerivedCoreProperties.txt", true},
+	{"%s/ucd/PropList.txt", true},
+	{"%s/ucd/Blocks.txt", true},
+	{"%s/ucd/HangulSyllableType.txt", true},
+	{"%s/ucd/Scripts.txt", false},
+	{"%s/ucd/ScriptExtensions.txt", false},
+	{"%s/ucd/PropertyValueAliases.txt", false},
+	{"%s/ucd/DerivedAge.txt", false},
+	{"%s/ucd/NameAliases.txt", false},
+	{"%s/ucd/IndicSyllabicCategory.txt", false},
+	{"%s/ucd/IndicPositionalCategory.txt", false},
+	{"%s/ucd/emoji/emoji-data.txt", false},
+	{"idna/%s/IdnaMappingTable.txt", false},
+	{"security/%s/confusables.txt", false},
+}
+
+// Runs the fetch command, which downloads the UCD files of a version from
+// unicode.org into the directory of the version, and generates
+// allcodepoints.txt and nfk.txt from them
+func fetchMain(args []string) {
+	flags := flag.NewFlagSet("fetch", flag.ExitOnError)
+	dataDir := flags.String("data", ".", "directory to create the directory of the version in")
+	baseURL := flags.String("url", "https://www.unicode.org/Public/", "URL of the Public directory of unicode.org, or of a mirror of it")
+	mirrors := flags.String("mirrors", "", "comma separated URLs of mirrors of the Public directory, tried in order when a download from -url fails")
+	parallel := flags.Int("parallel", 4, "number of files to download at the same time")
+	retries := flags.Int("retries", 3, "number of times to retry a failed download, each time trying -url and the mirrors in order")
+	backoff := flags.Duration("backoff", 2*time.Second, "delay before the first retry of a download, doubled for each retry after it")
+	force := flags.Bool("force", false, "download the files again even if they are already there")
+	generate := flags.Bool("generate", true, "write allcodepoints.txt and nfk.txt from the downloaded files, as the generate command does")
+	tableFlags(flags)
+	errataFlags(flags)
+	args = parseArgs(flags, args)
+
+	if len(args) != 1 {
+		fmt.Println("Usage: unicode-idn-diff fetch [flags] <version>")
+		flags.PrintDefaults()
+		return
+	}
+	version := args[0]
+	if !unicodeVersionRegex.MatchString(version) {
+		fmt.Println("Invalid version format. Please use the format 12.0.0")
+		return
+	}
+	dir := filepath.Join(*dataDir, version)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		fmt.Printf("Error %s\n", err)
+		os.Exit(1)
+	}
+
+	d := &downloader{
+		client:   &http.Client{Timeout: 5 * time.Minute},
+		baseURLs: []string{*baseURL},
+		retries:  *retries,
+		backoff:  *backoff,
+		sleep:    time.Sleep,
+	}
+	for _, mirror := range strings.Split(*mirrors, ",") {
+		if mirror = strings.TrimSpace(mirror); mirror != "" {
+			d.baseURLs = append(d.baseURLs, mirror)
+		}
+	}
+
+	// Download the files concurrently, printing the outcome for each file as
+	// it is done, and fail once they all are if any of them failed
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, max(*parallel, 1))
+	failed := false
+	for _, file := range fetchedFiles {
+		localPath := filepath.Join(dir, path.Base(file.path))
+		if _, err := os.Stat(localPath); err == nil && !*force {
+			fmt.Printf("Already there: %s\n", localPath)
+			continue
+		}
+		if *force {
+			os.Remove(localPath + ".part")
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			err := d.fetch(fmt.Sprintf(file.path, version), localPath)
+			<-slots
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case errors.Is(err, errNotFound) && !file.required:
+				fmt.Printf("Not published for %s: %s\n", version, path.Base(file.path))
+			case err != nil:
+				fmt.Printf("Error %s\n", err)
+				failed = true
+			default:
+				reportWritten(localPath)
+			}
+		}()
+	}
+	wg.Wait()
+	if failed {
+		os.Exit(1)
+	}
+
+	if *generate {
+		loader := newLoader(*dataDir)
+		generateFile(filepath.Join(dir, "allcodepoints.txt"), func(w io.Writer) error {
+			return idndiff.WriteDerivedTable(w, loader, version)
+		})
+		// nfk.txt may have been put there from elsewhere, so it is kept
+		nfkFile := filepath.Join(dir, "nfk.txt")
+		if _, err := os.Stat(nfkFile); err == nil && !*force {
+			fmt.Printf("Already there: %s\n", nfkFile)
+		} else {
+			generateFile(nfkFile, func(w io.Writer) error {
+				return idndiff.WriteNFKTable(w, loader, version)
+			})
+		}
+	}
+}
+
+// Writes a generated file, exiting on errors
+func generateFile(fileName string, write func(io.Writer) error) {
+	file, err := os.Create(fileName)
+	if err != nil {
+		fmt.Printf("Error %s\n", err)
+		os.Exit(1)
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		fmt.Printf("Error generating %s: %s\n", fileName, err)
+		os.Exit(1)
+	}
+	if err := file.Close(); err != nil {
+		fmt.Printf("Error %s\n", err)
+		os.Exit(1)
+	}
+	reportWritten(fileName)
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/patrikhson/unicode-idn-diff/pkg/idndiff"
+)
+
+// Regular expression to match Unicode versions (12.0.0 and up)
+var unicodeVersionRegex = regexp.MustCompile(`^1[2-9](\.\d+)*$`)
+
+// Defines the flags that select what and how to compare
+func compareFlags(flags *flag.FlagSet, opts *idndiff.Options) *string {
+	tableFlags(flags)
+	errataFlags(flags)
+	duplicateFlags(flags)
+	flags.BoolVar(&allowReverse, "allow-reverse", false, "allow the first version to be newer than the second, comparing backwards")
+	flags.IntVar(&maxLineSize, "max-line-size", idndiff.DefaultMaxLineSize, "longest line allowed in the data files, in bytes")
+	flags.BoolVar(&opts.Exceptions, "exceptions", false, "compare Exceptions (F) with the table published in RFC 5892")
+	flags.StringVar(&opts.ExcludeFile, "exclude", "", "file with ranges and scripts excluded from review")
+	flags.StringVar(&opts.Overrides, "overrides", "", "file with the outcomes of the review of code points, which are moved from Appendix E to the already resolved ones")
+	flags.StringVar(&opts.NameAliases, "name-aliases", "", "comma separated alias types from NameAliases.txt (correction, control, alternate, figment, abbreviation) to replace names like <control> with, in order of precedence")
+	flags.BoolVar(&opts.Frequencies, "frequencies", false, "count the code points per derived property value in both versions")
+	flags.BoolVar(&opts.Bidi, "bidi", false, "report code points that became valid and are right-to-left letters or digits, for the Bidi Rule of RFC 5893 (needs UnicodeData.txt and Scripts.txt)")
+	flags.BoolVar(&opts.Homoglyphs, "homoglyphs", false, "list the code points that became PVALID and are confusable, scored by risk (needs confusables.txt of UTS #39 and Scripts.txt)")
+	flags.BoolVar(&opts.Informational, "informational", false, "list the code points whose General Category or NFK normalization changed while their derived property value did not, with the reason it held")
+	flags.StringVar(&opts.Registry, "iana-registry", "", "file or http(s) URL of the IANA registry of derived property values (XML or CSV) to mark each candidate in Appendix E as new, already listed or conflicting with")
+	flags.StringVar(&opts.CrossCheck, "cross-check", "", "published review document (text or xml2rfc) for the same versions to compare the appendices A-E with")
+	flags.BoolVar(&opts.UTS46, "uts46", false, "compare with the UTS #46 IDNA Mapping Table used by ICU (needs IdnaMappingTable.txt)")
+	flags.StringVar(&opts.Only, "only", "", "compare only the General Category (gc) or only the NFK normalization (nfk), for versions without allcodepoints.txt; the appendices that need the derived property values are skipped")
+	flags.StringVar(&opts.ScriptPolicy, "restricted-scripts", "", "file of scripts that a policy, such as the exclusions of the ICANN MSR, restricts, with lines like \"Cuneiform ; excluded from the MSR\", to list the code points that became PVALID in them (needs Scripts.txt)")
+	flags.BoolVar(&opts.Narrative, "narrative", false, "write a paragraph describing the changes, made from the counts of the report, as a starting point for the introduction of a review")
+	flags.StringVar(&opts.NarrativeFile, "narrative-template", "", "text/template file to write the narrative with instead of the default one (implies -narrative)")
+	flags.BoolVar(&opts.FailFast, "fail-fast", false, "fail if a requested section, such as -bidi or -overrides, cannot be computed, rather than leaving it out of the report")
+	flags.BoolVar(&opts.Strict, "strict", false, "fail if the data violates the Unicode stability policies (needs UnicodeData.txt)")
+	flags.BoolVar(&opts.Categories, "categories", false, "annotate Appendix F with the RFC 5892 categories (A-J) of each range (needs the UCD property files)")
+	flags.BoolVar(&opts.CasePairs, "case-pairs", false, "check the derived property values of newly assigned case pairs (needs UnicodeData.txt)")
+	flags.StringVar(&opts.Detectors, "detectors", "", "comma separated additional change detectors to run: "+strings.Join(idndiff.DetectorNames(), ", "))
+	flags.BoolVar(&opts.Snapshots, "snapshots", false, "include the properties of each code point in the appendices A-E in the JSON report (needs UnicodeData.txt, Scripts.txt, DerivedAge.txt and Blocks.txt)")
+	flags.BoolVar(&opts.NFKCCaseFold, "nfkc-casefold", false, "report assigned code points whose NFKC_Casefold mapping, which Unstable (B) is defined by, changed (needs DerivedNormalizationProps.txt)")
+	flags.BoolVar(&opts.Age, "age", false, "add a column to the appendices A-E with the version of Unicode each code point was first assigned in (needs DerivedAge.txt)")
+	flags.BoolVar(&opts.GCConsistency, "gc-consistency", false, "list the code points with a General_Category but no derived property value, and the other way around (needs DerivedGeneralCategory.txt)")
+	flags.BoolVar(&opts.FillMissing, "fill-missing", false, "compare the code points with a General_Category but no derived property value as UNASSIGNED instead of leaving them out (needs DerivedGeneralCategory.txt)")
+	flags.BoolVar(&opts.CaseFolding, "case-folding", false, "report assigned code points whose full case folding changed, which NFKC_Casefold is derived from (needs CaseFolding.txt)")
+	flags.BoolVar(&opts.Decomposition, "decomposition-types", false, "report assigned code points whose decomposition type changed between canonical and compatibility, or to another compatibility tag, which changes NFKC (needs UnicodeData.txt)")
+	flags.BoolVar(&opts.DecompMapping, "decomposition-mappings", false, "report assigned code points whose full canonical or compatibility decomposition changed, from which NFC and NFKC are computed (needs UnicodeData.txt)")
+	flags.BoolVar(&opts.GroupByScript, "group-by-script", false, "group Appendix C by the script of the code points, with the number of code points per script (needs Scripts.txt)")
+	flags.BoolVar(&opts.MarkRendering, "mark-rendering", false, "annotate the code points of Appendix C with how they are likely rendered, spacing, nonspacing or invisible, from their Indic categories and combining class (needs UnicodeData.txt, IndicSyllabicCategory.txt and IndicPositionalCategory.txt)")
+	flags.BoolVar(&opts.Derive, "derive", false, "compute the derived property values from the UCD files by the rules of RFC 5892, as the generate command does, instead of reading allcodepoints.txt, and warn if allcodepoints.txt is there and differs")
+	flags.BoolVar(&opts.NewAssignment, "include-new-assignments", false, "list all newly assigned code points with their derived property values, which Appendix A leaves out")
+	flags.BoolVar(&opts.RootCause, "root-cause", false, "explain each change in Appendix A by the rule of RFC 5892 that decided the value in each version, and the UCD properties behind it that changed (needs the files that generate needs, for both versions)")
+	flags.BoolVar(&opts.BidiClass, "bidi-class", false, "report assigned code points whose Bidi_Class changed, which matters for the Bidi Rule of RFC 5893 (needs DerivedBidiClass.txt)")
+	flags.BoolVar(&opts.JoiningType, "joining-type", false, "report assigned code points whose Joining_Type changed, which matters for the CONTEXTJ rule of U+200C ZERO WIDTH NON-JOINER (needs DerivedJoiningType.txt)")
+	flags.BoolVar(&opts.ScriptChange, "script-changes", false, "report assigned code points whose Script changed, which matters for the label generation rules of registries and their policies on mixing scripts (needs Scripts.txt)")
+	flags.BoolVar(&opts.CCC, "combining-class", false, "report assigned code points whose Canonical_Combining_Class changed, flagging changes to or from Virama (9), which the CONTEXTJ rules of ZWNJ and ZWJ test for (needs DerivedCombiningClass.txt)")
+	flags.StringVar(&opts.FStyle, "f-style", "compact", "style of Appendix F: compact, or iana for the ranges, names and CSV format of the IANA registry, to compare with it byte for byte")
+	flags.BoolVar(&opts.NFKHazards, "nfk-hazards", false, "report PVALID code points with an NFK normalization that now includes other derived property values")
+	return flags.String("data", ".", "directory or http(s) URL with one subdirectory per version")
+}
+
+// Defines the flags of how reports are rendered that all commands writing
+// them have
+func renderFlags(flags *flag.FlagSet, render *idndiff.RenderOptions) {
+	flags.BoolVar(&render.ShowGlyphs, "glyphs", false, "show the character itself next to each code point in the appendices")
+}
+
+// Defines the flags that replace the embedded tables with files
+func tableFlags(flags *flag.FlagSet) {
+	flags.Func("exceptions-file", "file replacing the embedded Exceptions (F) of RFC 5892, lines like \"00DF ; PVALID\"", func(path string) (err error) {
+		tables.Exceptions, err = idndiff.ReadValueTableFile(path)
+		return err
+	})
+	flags.Func("backward-compatible-file", "file replacing the embedded, empty, BackwardCompatible (G) of RFC 5892", func(path string) (err error) {
+		tables.BackwardCompatible, err = idndiff.ReadValueTableFile(path)
+		return err
+	})
+	flags.Func("ignorable-blocks-file", "file replacing the embedded block names in IgnorableBlocks (D) of RFC 5892, one per line", func(path string) (err error) {
+		tables.IgnorableBlocks, err = idndiff.ReadBlockNamesFile(path)
+		return err
+	})
+}
+
+// The interpretation of RFC 5892, its tables, the handling of duplicates and
+// the longest line of the data files selected by the flags, which each Loader
+// is created with
+var (
+	literalUnstable bool
+	tables          idndiff.RFC5892Tables
+	duplicatePolicy = "last"
+	maxLineSize     = idndiff.DefaultMaxLineSize
+)
+
+// Creates a Loader reading from dataDir with the settings of the flags
+func newLoader(dataDir string) *idndiff.Loader {
+	loader := idndiff.NewLoader(dataDir)
+	loader.LiteralUnstable = literalUnstable
+	loader.Tables = tables
+	loader.DuplicatePolicy = duplicatePolicy
+	loader.MaxLineSize = maxLineSize
+	return loader
+}
+
+// Defines the flags that select other interpretations of RFC 5892
+func errataFlags(flags *flag.FlagSet) {
+	flags.BoolVar(&literalUnstable, "literal-unstable", false, "compute Unstable (B) literally as toNFKC(toCaseFold(toNFKC(cp))) != cp, without the default ignorable code points that Changes_When_NFKC_Casefolded also has")
+}
+
+// Defines the flag that selects the DuplicatePolicy of the Loader
+func duplicateFlags(flags *flag.FlagSet) {
+	flags.Func("duplicates", "what to do with code points listed more than once in an input file: last (use the last entry, the default), first or error", func(policy string) error {
+		switch policy {
+		case "last", "first", "error":
+			duplicatePolicy = policy
+			return nil
+		}
+		return fmt.Errorf("unknown policy %q, expected last, first or error", policy)
+	})
+}
+
+// Parses the flags, also those after the arguments as in "derive 17.0.0 -o
+// allcodepoints.txt", and returns the arguments
+func parseArgs(flags *flag.FlagSet, args []string) []string {
+	var arguments []string
+	for {
+		flags.Parse(args)
+		if flags.NArg() == 0 {
+			environmentFlags(flags)
+			return arguments
+		}
+		arguments = append(arguments, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+}
+
+// The prefix of the environment variables that set flags, as in
+// UNICODE_IDN_DIFF_DATA for -data and UNICODE_IDN_DIFF_MAX_ENTRIES for
+// -max-entries
+const environmentPrefix = "UNICODE_IDN_DIFF_"
+
+// Sets the flags that were not given on the command line from the
+// environment, exiting as for an invalid flag if a value is invalid. Flags
+// set this way take precedence over those of a profile.
+func environmentFlags(flags *flag.FlagSet) {
+	given := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+	flags.VisitAll(func(f *flag.Flag) {
+		name := environmentPrefix + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+		value, ok := os.LookupEnv(name)
+		if given[f.Name] || !ok {
+			return
+		}
+		if err := flags.Set(f.Name, value); err != nil {
+			fmt.Fprintf(flags.Output(), "invalid value %q for %s: %v\n", value, name, err)
+			flags.Usage()
+			os.Exit(2)
+		}
+	})
+}
+
+// Checks that both versions are valid, and in order, printing a message if
+// not. The first version can also be the baseline of RFC 5892.
+func validVersions(version1, version2 string) bool {
+	if version1 != idndiff.RFC5892Baseline && !unicodeVersionRegex.MatchString(version1) || !unicodeVersionRegex.MatchString(version2) {
+		fmt.Println("Invalid version format. Please use the format 12.0.0, or rfc5892 for the first version")
+		return false
+	}
+	if message := versionOrder(version1, version2); message != "" {
+		fmt.Println(message)
+		return false
+	}
+	return true
+}
+
+// Whether a comparison from a newer version to an older one is allowed
+var allowReverse bool
+
+// Returns why two versions are not compared, if they are the same, or if
+// the first is newer and that is not allowed, since the appendices would then
+// show the changes backwards
+func versionOrder(version1, version2 string) string {
+	if version1 == idndiff.RFC5892Baseline {
+		return ""
+	}
+	switch order := compareVersionNumbers(version1, version2); {
+	case order == 0:
+		return fmt.Sprintf("No changes are possible: version %s is compared with itself", version2)
+	case order > 0 && !allowReverse:
+		return fmt.Sprintf("Version %s is newer than %s, so the changes would be shown backwards. Swap the versions, as in %s %s, or use -allow-reverse to compare in this direction", version1, version2, version2, version1)
+	}
+	return ""
+}
+
+// Compares versions such as 9.0.0 and 12.1.0 by their numbers
+func compareVersionNumbers(a, b string) int {
+	fieldsA, fieldsB := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(fieldsA) || i < len(fieldsB); i++ {
+		var x, y int
+		if i < len(fieldsA) {
+			x, _ = strconv.Atoi(fieldsA[i])
+		}
+		if i < len(fieldsB) {
+			y, _ = strconv.Atoi(fieldsB[i])
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return 0
+}
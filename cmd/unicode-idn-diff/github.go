@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/patrikhson/unicode-idn-diff/pkg/idndiff"
+)
+
+// The label of all issues filed for the review, so that earlier ones can be
+// found and updated
+const reviewLabel = "idna-review"
+
+// Finds the hidden marker of the candidate an issue was filed for, such as
+// "<!-- idna-review: Unicode 16.0.0 U+0B55 -->", which stays when reviewers
+// edit the title or the rest of the body
+var candidateMarkerRegex = regexp.MustCompile(`<!-- idna-review: (.+?) -->`)
+
+// Files the review tickets as issues of a GitHub repository, one per
+// candidate in Appendix E, labelled with the appendices the code point was
+// found in. Issues filed earlier, found by the hidden marker of their
+// candidate, are left as the reviewers edited them: only labels that are
+// missing are added, and a comment is posted when the ticket changed.
+type githubIssues struct {
+	api    string // Such as https://api.github.com
+	repo   string // owner/repo
+	token  string
+	client *http.Client
+}
+
+// An issue as sent to the GitHub REST API
+type githubIssue struct {
+	Title  string   `json:"title"`
+	Body   string   `json:"body"`
+	Labels []string `json:"labels"`
+}
+
+// An issue filed by an earlier run
+type filedIssue struct {
+	number int
+	body   string
+	labels map[string]bool
+}
+
+// Sends a request to the GitHub API and decodes the JSON response, if any
+func (g *githubIssues) request(method, path string, payload, result any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, strings.TrimSuffix(g.api, "/")+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+g.token)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s: unexpected HTTP status %s: %s", method, path, resp.Status, bytes.TrimSpace(message))
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// Returns the issues with the review label, by the marker of their candidate
+func (g *githubIssues) existing() (map[string]filedIssue, error) {
+	filed := make(map[string]filedIssue)
+	for page := 1; ; page++ {
+		var issues []struct {
+			Number int    `json:"number"`
+			Body   string `json:"body"`
+			Labels []struct {
+				Name string `json:"name"`
+			} `json:"labels"`
+			PullRequest json.RawMessage `json:"pull_request"`
+		}
+		path := fmt.Sprintf("/repos/%s/issues?state=all&labels=%s&per_page=100&page=%d", g.repo, reviewLabel, page)
+		if err := g.request(http.MethodGet, path, nil, &issues); err != nil {
+			return nil, err
+		}
+		if len(issues) == 0 {
+			return filed, nil
+		}
+		for _, issue := range issues {
+			match := candidateMarkerRegex.FindStringSubmatch(issue.Body)
+			if match == nil || issue.PullRequest != nil {
+				continue
+			}
+			labels := make(map[string]bool)
+			for _, label := range issue.Labels {
+				labels[label.Name] = true
+			}
+			filed[match[1]] = filedIssue{issue.Number, issue.Body, labels}
+		}
+	}
+}
+
+// Reports whether a comment of an issue has a text, such as the marker of a
+// ticket
+func (g *githubIssues) commented(number int, text string) (bool, error) {
+	for page := 1; ; page++ {
+		var comments []struct {
+			Body string `json:"body"`
+		}
+		path := fmt.Sprintf("/repos/%s/issues/%d/comments?per_page=100&page=%d", g.repo, number, page)
+		if err := g.request(http.MethodGet, path, nil, &comments); err != nil {
+			return false, err
+		}
+		if len(comments) == 0 {
+			return false, nil
+		}
+		for _, comment := range comments {
+			if strings.Contains(comment.Body, text) {
+				return true, nil
+			}
+		}
+	}
+}
+
+// Files an issue per ticket, or updates the one filed earlier, and returns
+// the number of issues created and updated. The tickets are those of
+// idndiff.CandidateTickets, one per code point.
+func (g *githubIssues) file(r *idndiff.Report, tickets []idndiff.ReviewTicket, opts idndiff.RenderOptions) (created, updated int, err error) {
+	filed, err := g.existing()
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, ticket := range tickets {
+		var body strings.Builder
+		if err := idndiff.WriteTicketMarkdown(&body, r, ticket, opts); err != nil {
+			return created, updated, err
+		}
+		// The ticket as written by this run, to tell whether it changed
+		// since the issue or its last update
+		ticketMarker := fmt.Sprintf("<!-- idna-review-ticket: %x -->", sha256.Sum256([]byte(body.String())))
+		candidate := fmt.Sprintf("Unicode %s U+%s", r.Version2, ticket.Start)
+		labels := []string{reviewLabel}
+		for _, source := range ticket.Sources {
+			labels = append(labels, "appendix-"+strings.ToLower(source))
+		}
+
+		issue, ok := filed[candidate]
+		if !ok {
+			fmt.Fprintf(&body, "\n<!-- idna-review: %s -->\n%s\n", candidate, ticketMarker)
+			if err := g.request(http.MethodPost, fmt.Sprintf("/repos/%s/issues", g.repo), githubIssue{ticket.Title, body.String(), labels}, nil); err != nil {
+				return created, updated, err
+			}
+			created++
+			continue
+		}
+
+		var missing []string
+		for _, label := range labels {
+			if !issue.labels[label] {
+				missing = append(missing, label)
+			}
+		}
+		if len(missing) > 0 {
+			if err := g.request(http.MethodPost, fmt.Sprintf("/repos/%s/issues/%d/labels", g.repo, issue.number), map[string][]string{"labels": missing}, nil); err != nil {
+				return created, updated, err
+			}
+		}
+		changed := !strings.Contains(issue.body, ticketMarker)
+		if changed {
+			commented, err := g.commented(issue.number, ticketMarker)
+			if err != nil {
+				return created, updated, err
+			}
+			changed = !commented
+		}
+		if changed {
+			comment := fmt.Sprintf("The comparison of Unicode %s with Unicode %s was run again, and the ticket is now:\n\n%s\n%s\n", r.Version2, r.Version1, body.String(), ticketMarker)
+			if err := g.request(http.MethodPost, fmt.Sprintf("/repos/%s/issues/%d/comments", g.repo, issue.number), map[string]string{"body": comment}, nil); err != nil {
+				return created, updated, err
+			}
+		}
+		if len(missing) > 0 || changed {
+			updated++
+		}
+	}
+	return created, updated, nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/patrikhson/unicode-idn-diff/pkg/idndiff"
+)
+
+// The issues of a repository, as the GitHub API has them
+type fakeGitHub struct {
+	mu       sync.Mutex
+	issues   []fakeIssue
+	requests []string
+}
+
+type fakeIssue struct {
+	Title    string
+	Body     string
+	Labels   []string
+	Comments []string
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, req.Method+" "+req.URL.Path)
+	var payload struct {
+		Title  string   `json:"title"`
+		Body   string   `json:"body"`
+		Labels []string `json:"labels"`
+	}
+	if req.Body != nil {
+		json.NewDecoder(req.Body).Decode(&payload)
+	}
+	page := req.URL.Query().Get("page")
+	parts := strings.Split(strings.TrimPrefix(req.URL.Path, "/repos/owner/repo/issues"), "/")
+	switch {
+	case req.Method == http.MethodGet && len(parts) == 1:
+		type label struct {
+			Name string `json:"name"`
+		}
+		type issue struct {
+			Number int     `json:"number"`
+			Title  string  `json:"title"`
+			Body   string  `json:"body"`
+			Labels []label `json:"labels"`
+		}
+		issues := []issue{}
+		for i, filed := range f.issues {
+			if page == "1" {
+				labels := []label{}
+				for _, name := range filed.Labels {
+					labels = append(labels, label{name})
+				}
+				issues = append(issues, issue{i + 1, filed.Title, filed.Body, labels})
+			}
+		}
+		json.NewEncoder(w).Encode(issues)
+	case req.Method == http.MethodPost && len(parts) == 1:
+		f.issues = append(f.issues, fakeIssue{Title: payload.Title, Body: payload.Body, Labels: payload.Labels})
+		w.WriteHeader(http.StatusCreated)
+	case len(parts) == 3:
+		number, err := strconv.Atoi(parts[1])
+		if err != nil || number < 1 || number > len(f.issues) {
+			http.NotFound(w, req)
+			return
+		}
+		issue := &f.issues[number-1]
+		switch {
+		case req.Method == http.MethodPost && parts[2] == "labels":
+			issue.Labels = append(issue.Labels, payload.Labels...)
+		case req.Method == http.MethodGet && parts[2] == "comments":
+			comments := []map[string]string{}
+			if page == "1" {
+				for _, comment := range issue.Comments {
+					comments = append(comments, map[string]string{"body": comment})
+				}
+			}
+			json.NewEncoder(w).Encode(comments)
+		case req.Method == http.MethodPost && parts[2] == "comments":
+			issue.Comments = append(issue.Comments, payload.Body)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			http.Error(w, "unexpected request", http.StatusMethodNotAllowed)
+		}
+	default:
+		http.Error(w, "unexpected request", http.StatusMethodNotAllowed)
+	}
+}
+
+// An issue is filed per candidate, and found again by its marker when the
+// reviewers edited it, which is then only added labels and comments
+func TestGitHubIssues(t *testing.T) {
+	fake := &fakeGitHub{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	issues := &githubIssues{api: server.URL, repo: "owner/repo", token: "token", client: server.Client()}
+	report := &idndiff.Report{
+		Meta: idndiff.Meta{Version1: "13.0.0", Version2: "14.0.0"},
+		AppendixE: []idndiff.ExceptionCandidate{
+			{CodePoint: "0B55", Name: "ORIYA SIGN OVERLINE", Property: "PVALID", Source: "A"},
+			{CodePoint: "0B56", Name: "ORIYA AI LENGTH MARK", Property: "PVALID", Source: "A"},
+		},
+	}
+
+	for _, run := range []struct {
+		name     string
+		edit     func()
+		created  int
+		updated  int
+		labels   []string // Of the first issue after the run
+		comments int      // Of the first issue after the run
+	}{
+		{"first run", func() {}, 2, 0, []string{"idna-review", "appendix-a"}, 0},
+		{"same tickets", func() {}, 0, 0, []string{"idna-review", "appendix-a"}, 0},
+		{"edited by the reviewers", func() {
+			issue := &fake.issues[0]
+			issue.Title = "ORIYA SIGN OVERLINE: keep PVALID"
+			issue.Body = "Decided at the meeting.\n" + issue.Body[strings.Index(issue.Body, "<!--"):]
+			issue.Labels = []string{"idna-review", "decided"}
+		}, 0, 1, []string{"idna-review", "decided", "appendix-a"}, 0},
+		{"changed tickets", func() {
+			report.AppendixE[0].Name = "ORIYA SIGN OVERLINE (corrected)"
+		}, 0, 1, []string{"idna-review", "decided", "appendix-a"}, 1},
+		{"changed tickets again", func() {}, 0, 0, []string{"idna-review", "decided", "appendix-a"}, 1},
+	} {
+		run.edit()
+		created, updated, err := issues.file(report, idndiff.CandidateTickets(report), idndiff.RenderOptions{})
+		if err != nil {
+			t.Fatalf("%s: %s", run.name, err)
+		}
+		if created != run.created || updated != run.updated {
+			t.Errorf("%s: created %d and updated %d issues, want %d and %d", run.name, created, updated, run.created, run.updated)
+		}
+		if len(fake.issues) != 2 {
+			t.Fatalf("%s: %d issues, want 2", run.name, len(fake.issues))
+		}
+		first := fake.issues[0]
+		if fmt.Sprint(first.Labels) != fmt.Sprint(run.labels) {
+			t.Errorf("%s: labels %v, want %v", run.name, first.Labels, run.labels)
+		}
+		if len(first.Comments) != run.comments {
+			t.Errorf("%s: %d comments, want %d", run.name, len(first.Comments), run.comments)
+		}
+	}
+	if first := fake.issues[0]; first.Title != "ORIYA SIGN OVERLINE: keep PVALID" || !strings.HasPrefix(first.Body, "Decided at the meeting.\n") {
+		t.Errorf("the edits of the reviewers were overwritten: %q, %q", first.Title, first.Body)
+	}
+	if second := fake.issues[1]; !strings.Contains(second.Body, "<!-- idna-review: Unicode 14.0.0 U+0B56 -->") {
+		t.Errorf("no marker in %q", second.Body)
+	}
+	for _, request := range fake.requests {
+		if strings.HasPrefix(request, http.MethodPatch) {
+			t.Errorf("an issue was edited with %s", request)
+		}
+	}
+}
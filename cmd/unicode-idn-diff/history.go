@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/patrikhson/unicode-idn-diff/pkg/idndiff"
+)
+
+// Runs the history command, which writes the changelog of each code point
+// across a sequence of versions
+func historyMain(args []string) {
+	flags := flag.NewFlagSet("history", flag.ExitOnError)
+	var opts idndiff.Options
+	dataDir := compareFlags(flags, &opts)
+	format := flags.String("format", "json", "output format: json or csv")
+	output := flags.String("o", "", "write to this file instead of to standard output")
+	results := flags.String("results", "", "read the changes from precomputed results instead of comparing the versions")
+	save := flags.String("save", "", "also save the changes as precomputed results for later use with -results")
+	checkpoint := flags.String("checkpoint", "", "save the changes of each pair of versions in this directory, and resume from the pairs saved there by an interrupted run")
+	timeout := flags.Duration("timeout", 0, "fail if comparing a pair of versions takes longer than this, such as 10m (0 for no limit)")
+	flags.Parse(args)
+	environmentFlags(flags)
+
+	versions := flags.Args()
+	if len(versions) < 2 {
+		fmt.Println("Usage: unicode-idn-diff history [flags] <version1> <version2> [<version3> ...]")
+		flags.PrintDefaults()
+		return
+	}
+	for i := 0; i+1 < len(versions); i++ {
+		if !validVersions(versions[i], versions[i+1]) {
+			return
+		}
+	}
+	write := idndiff.WriteEventsJSON
+	switch *format {
+	case "json":
+	case "csv":
+		write = idndiff.WriteEventsCSV
+	default:
+		fmt.Printf("Error: unknown output format %q\n", *format)
+		return
+	}
+
+	var events []idndiff.ChangeEvent
+	if *results != "" {
+		artifact, err := idndiff.ReadResults(*results)
+		if err != nil {
+			fmt.Printf("Error reading %s: %s\n", *results, err)
+			os.Exit(1)
+		}
+		events, err = artifact.History(versions)
+		if err != nil {
+			fmt.Printf("Error %s\n", err)
+			os.Exit(1)
+		}
+	} else {
+		var err error
+		events, err = idndiff.History(newLoader(*dataDir), versions, opts, *checkpoint, *timeout)
+		if err != nil {
+			fmt.Printf("Error %s\n", err)
+			os.Exit(1)
+		}
+	}
+	if *save != "" {
+		if err := idndiff.WriteResults(*save, versions, events); err != nil {
+			fmt.Printf("Error writing %s: %s\n", *save, err)
+			os.Exit(1)
+		}
+	}
+
+	w := io.Writer(os.Stdout)
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Printf("Error %s\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		w = file
+	}
+	if err := write(w, events); err != nil {
+		fmt.Printf("Error %s\n", err)
+		os.Exit(1)
+	}
+}
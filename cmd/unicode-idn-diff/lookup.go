@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/patrikhson/unicode-idn-diff/pkg/idndiff"
+)
+
+// Runs the lookup command, which writes the tracked properties of a list of
+// code points in each of the given versions
+func lookupMain(args []string) {
+	flags := flag.NewFlagSet("lookup", flag.ExitOnError)
+	dataDir := flags.String("data", ".", "directory or http(s) URL with one subdirectory per version")
+	file := flags.String("file", "-", "file with the code points, one per line as U+ and hexadecimal digits or as a decimal number, or - for standard input")
+	versionList := flags.String("versions", "", "comma separated versions to look the code points up in, such as 15.1.0,16.0.0")
+	format := flags.String("format", "csv", "output format: csv or json")
+	output := flags.String("o", "", "write to this file instead of to standard output")
+	duplicateFlags(flags)
+	args = parseArgs(flags, args)
+
+	if len(args) != 0 || *versionList == "" {
+		fmt.Println("Usage: unicode-idn-diff lookup [flags] -versions <version1>,<version2> -file <file>")
+		flags.PrintDefaults()
+		return
+	}
+	versions := strings.Split(*versionList, ",")
+	for _, version := range versions {
+		if !unicodeVersionRegex.MatchString(version) {
+			fmt.Println("Invalid version format. Please use the format 12.0.0")
+			return
+		}
+	}
+	write := idndiff.WriteLookupCSV
+	switch *format {
+	case "csv":
+	case "json":
+		write = idndiff.WriteLookupJSON
+	default:
+		fmt.Printf("Error: unknown output format %q\n", *format)
+		return
+	}
+
+	r := io.Reader(os.Stdin)
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			fmt.Printf("Error %s\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		r = f
+	}
+	codepoints, err := idndiff.ReadCodePointList(r)
+	if err != nil {
+		fmt.Printf("Error reading %s: %s\n", *file, err)
+		os.Exit(1)
+	}
+	entries, err := idndiff.Lookup(newLoader(*dataDir), versions, codepoints)
+	if err != nil {
+		fmt.Printf("Error %s\n", err)
+		os.Exit(1)
+	}
+
+	w := io.Writer(os.Stdout)
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Printf("Error %s\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		w = file
+	}
+	if err := write(w, entries); err != nil {
+		fmt.Printf("Error %s\n", err)
+		os.Exit(1)
+	}
+}
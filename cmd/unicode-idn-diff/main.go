@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// A command of the program, run with the arguments after its name
+type command struct {
+	name  string
+	usage string // The arguments, after the name
+	run   func(args []string)
+}
+
+// Returns the commands, in the order of the usage message
+func commands() []command {
+	return []command{
+		{"compare", "[flags] <version1> <version2>", compareMain},
+		{"generate", "[flags] <version>", deriveMain},
+		{"fetch", "[flags] <version>", fetchMain},
+		{"validate", "[flags] <version> [<version> ...]", validateMain},
+		{"serve", "[flags]", serveMain},
+		{"watch", "[flags] <version1> <version2>", watchMain},
+		{"history", "[flags] <version1> <version2> [<version3> ...]", historyMain},
+		{"matrix", "[flags] <version1>..<version2> | <version1> <version2> [<version3> ...]", matrixMain},
+		{"exceptions", "[flags] <version1> <version2>", exceptionsMain},
+		{"tickets", "[flags] <version1> <version2>", ticketsMain},
+		{"lookup", "[flags] -versions <version1>,<version2> -file <file>", lookupMain},
+		{"explain", "[flags] <code point> <version>", explainMain},
+		{"profile", "[flags] <version1> <version2>", profileMain},
+		{"schema", "", schemaMain},
+	}
+}
+
+// Older names of commands
+var commandAliases = map[string]string{
+	"derive": "generate",
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		name := os.Args[1]
+		if alias, ok := commandAliases[name]; ok {
+			name = alias
+		}
+		for _, c := range commands() {
+			if c.name == name {
+				c.run(os.Args[2:])
+				return
+			}
+		}
+	}
+	// Without a command, compare as before there were commands
+	compareMain(os.Args[1:])
+}
+
+// Prints how to run each command
+func printUsage() {
+	fmt.Println("Usage: unicode-idn-diff [flags] <version1> <version2>")
+	for _, c := range commands() {
+		fmt.Println(strings.TrimSpace("       unicode-idn-diff " + c.name + " " + c.usage))
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/patrikhson/unicode-idn-diff/pkg/idndiff"
+)
+
+// Runs the matrix command, which writes the number of changes of derived
+// property values between every pair of versions
+func matrixMain(args []string) {
+	flags := flag.NewFlagSet("matrix", flag.ExitOnError)
+	var opts idndiff.Options
+	dataDir := compareFlags(flags, &opts)
+	format := flags.String("format", "csv", "output format: csv or html")
+	output := flags.String("o", "", "write to this file instead of to standard output")
+	args = parseArgs(flags, args)
+
+	if len(args) == 0 || len(args) == 1 && !strings.Contains(args[0], "..") {
+		fmt.Println("Usage: unicode-idn-diff matrix [flags] <version1>..<version2> | <version1> <version2> [<version3> ...]")
+		flags.PrintDefaults()
+		return
+	}
+	versions := args
+	if len(args) == 1 {
+		first, last, _ := strings.Cut(args[0], "..")
+		var err error
+		versions, err = versionRange(*dataDir, first, last)
+		if err != nil {
+			fmt.Printf("Error %s\n", err)
+			os.Exit(1)
+		}
+	}
+	for i := 0; i+1 < len(versions); i++ {
+		if !validVersions(versions[i], versions[i+1]) {
+			return
+		}
+	}
+	write := idndiff.WriteMatrixCSV
+	switch *format {
+	case "csv":
+	case "html":
+		write = idndiff.WriteMatrixHTML
+	default:
+		fmt.Printf("Error: unknown output format %q\n", *format)
+		return
+	}
+
+	matrix, err := idndiff.BuildMatrix(newLoader(*dataDir), versions, opts)
+	if err != nil {
+		fmt.Printf("Error %s\n", err)
+		os.Exit(1)
+	}
+
+	w := io.Writer(os.Stdout)
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Printf("Error %s\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		w = file
+	}
+	if err := write(w, matrix); err != nil {
+		fmt.Printf("Error %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// Returns the versions in the data directory from first to last, in order
+func versionRange(dataDir, first, last string) ([]string, error) {
+	if !unicodeVersionRegex.MatchString(first) || !unicodeVersionRegex.MatchString(last) {
+		return nil, fmt.Errorf("invalid range %s..%s, use the format 12.0.0..17.0.0", first, last)
+	}
+	if strings.HasPrefix(dataDir, "http://") || strings.HasPrefix(dataDir, "https://") {
+		return nil, fmt.Errorf("the versions of a range are found in a local -data directory, list them instead")
+	}
+	entries, err := os.ReadDir(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	var versions []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() && unicodeVersionRegex.MatchString(name) && compareVersionNumbers(first, name) <= 0 && compareVersionNumbers(name, last) <= 0 {
+			versions = append(versions, name)
+		}
+	}
+	slices.SortFunc(versions, compareVersionNumbers)
+	if len(versions) < 2 {
+		return nil, fmt.Errorf("at least two versions from %s to %s are needed in %s, found %d", first, last, dataDir, len(versions))
+	}
+	return versions, nil
+}
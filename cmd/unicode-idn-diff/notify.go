@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// A destination for notifications about new results
+type sink interface {
+	notify(subject, body string) error
+}
+
+// Prints notifications on standard output
+type stdoutSink struct{}
+
+func (stdoutSink) notify(subject, body string) error {
+	_, err := fmt.Printf("%s\n\n%s", subject, body)
+	return err
+}
+
+// Appends notifications to a file
+type fileSink struct {
+	path string
+}
+
+func (s fileSink) notify(subject, body string) error {
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(file, "%s\n\n%s\n", subject, body); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Posts notifications as JSON to a webhook
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func (s webhookSink) notify(subject, body string) error {
+	payload, err := json.Marshal(map[string]string{"subject": subject, "text": body})
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s: unexpected HTTP status %s", s.url, resp.Status)
+	}
+	return nil
+}
+
+// Parses a comma separated list of sinks, each of which is "stdout", an
+// http(s) URL of a webhook or the name of a file
+func parseSinks(spec string) ([]sink, error) {
+	var sinks []sink
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+			continue
+		case name == "stdout":
+			sinks = append(sinks, stdoutSink{})
+		case strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://"):
+			sinks = append(sinks, webhookSink{url: name, client: &http.Client{Timeout: time.Minute}})
+		default:
+			sinks = append(sinks, fileSink{path: name})
+		}
+	}
+	if len(sinks) == 0 {
+		return nil, fmt.Errorf("no sinks in %q", spec)
+	}
+	return sinks, nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/patrikhson/unicode-idn-diff/pkg/idndiff"
+)
+
+// Whether the console output is porcelain, with -porcelain
+var porcelainMode bool
+
+// Tells that a file or directory was written, as a porcelain line on the form
+// "wrote <path>" with -porcelain
+func reportWritten(path string) {
+	if porcelainMode {
+		fmt.Printf("wrote\t%s\n", path)
+		return
+	}
+	fmt.Printf("Wrote %s\n", path)
+}
+
+// Parses a comma separated list of output formats
+func parseFormats(spec string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if _, ok := idndiff.Formats[name]; !ok {
+			return nil, fmt.Errorf("unknown output format %q", name)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// Writes the report in each of the formats. A single format without an output
+// name goes to standard output; otherwise each format is written to the
+// output name with the extension of the format added.
+func writeReports(r *idndiff.Report, opts idndiff.RenderOptions, names []string, output string) error {
+	if output == "" {
+		if len(names) > 1 {
+			return fmt.Errorf("more than one output format needs an output name (-o)")
+		}
+		return idndiff.Formats[names[0]].Render(os.Stdout, r, opts)
+	}
+
+	for _, name := range names {
+		fileName := output + idndiff.Formats[name].Extension
+		file, err := os.Create(fileName)
+		if err != nil {
+			return err
+		}
+		if err := idndiff.Formats[name].Render(file, r, opts); err != nil {
+			file.Close()
+			return fmt.Errorf("writing %s: %w", fileName, err)
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+		reportWritten(fileName)
+	}
+	return nil
+}
+
+// Writes the summary and each appendix of the text report to its own file in
+// dir, which is created if needed
+func writeSplitOutput(dir string, r *idndiff.Report, opts idndiff.RenderOptions) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, section := range idndiff.RenderTextSections(r, opts) {
+		fileName := filepath.Join(dir, section.Name+".txt")
+		if err := os.WriteFile(fileName, []byte(section.Text), 0o644); err != nil {
+			return err
+		}
+	}
+	reportWritten(dir)
+	return nil
+}
+
+// Writes each of the appendices A-F as CSV or TSV, as named by format, to its
+// own file in dir, which is created if needed
+func writeAppendixTables(dir string, r *idndiff.Report, format string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	comma := ','
+	if format == "tsv" {
+		comma = '\t'
+	}
+	for _, table := range idndiff.AppendixTables(r) {
+		fileName := filepath.Join(dir, table.Letter+"."+format)
+		file, err := os.Create(fileName)
+		if err != nil {
+			return err
+		}
+		if err := idndiff.WriteAppendixTable(file, table, comma); err != nil {
+			file.Close()
+			return fmt.Errorf("writing %s: %w", fileName, err)
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+	}
+	reportWritten(dir)
+	return nil
+}
+
+// Writes the appendices truncated by -max-entries in full to their overflow
+// files in dir, telling about each file if announce is set
+func writeOverflow(dir string, r *idndiff.Report, opts idndiff.RenderOptions, announce bool) error {
+	for _, section := range idndiff.OverflowSections(r, opts) {
+		fileName := filepath.Join(dir, opts.OverflowFileName(section.Name))
+		if err := os.WriteFile(fileName, []byte(section.Text), 0o644); err != nil {
+			return err
+		}
+		if announce {
+			reportWritten(fileName)
+		}
+	}
+	return nil
+}
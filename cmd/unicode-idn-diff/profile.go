@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"text/tabwriter"
+	"time"
+
+	"github.com/patrikhson/unicode-idn-diff/pkg/idndiff"
+)
+
+// Runs the profile command, which compares two versions with the CPU
+// profiler running, writes CPU and heap profiles for go tool pprof, and
+// prints how long each phase of the comparison took
+func profileMain(args []string) {
+	flags := flag.NewFlagSet("profile", flag.ExitOnError)
+	var opts idndiff.Options
+	dataDir := compareFlags(flags, &opts)
+	cpuProfile := flags.String("cpu", "cpu.pprof", "file to write the CPU profile to")
+	heapProfile := flags.String("heap", "heap.pprof", "file to write the heap profile to, taken after the comparison")
+	args = parseArgs(flags, args)
+
+	if len(args) != 2 {
+		fmt.Println("Usage: unicode-idn-diff profile [flags] <version1> <version2>")
+		flags.PrintDefaults()
+		return
+	}
+	if !validVersions(args[0], args[1]) {
+		return
+	}
+
+	cpuFile, err := os.Create(*cpuProfile)
+	if err != nil {
+		fmt.Printf("Error %s\n", err)
+		os.Exit(1)
+	}
+	defer cpuFile.Close()
+	if err := pprof.StartCPUProfile(cpuFile); err != nil {
+		fmt.Printf("Error %s\n", err)
+		os.Exit(1)
+	}
+
+	opts.Timings = &idndiff.Timings{}
+	started := time.Now()
+	report, err := idndiff.Compare(newLoader(*dataDir), args[0], args[1], opts)
+	elapsed := time.Since(started)
+	pprof.StopCPUProfile()
+	if err != nil {
+		fmt.Printf("Error %s\n", err)
+		os.Exit(1)
+	}
+
+	// The heap profile is of what is still in use with the report
+	runtime.GC()
+	heapFile, err := os.Create(*heapProfile)
+	if err != nil {
+		fmt.Printf("Error %s\n", err)
+		os.Exit(1)
+	}
+	defer heapFile.Close()
+	if err := pprof.WriteHeapProfile(heapFile); err != nil {
+		fmt.Printf("Error %s\n", err)
+		os.Exit(1)
+	}
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	runtime.KeepAlive(report)
+
+	fmt.Printf("Comparing version %s and %s\n", args[0], args[1])
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(table, "Phase\tTime\n")
+	for _, phase := range opts.Timings.Phases {
+		fmt.Fprintf(table, "%s\t%s\n", phase.Phase, phase.Duration.Round(time.Microsecond))
+	}
+	fmt.Fprintf(table, "Total\t%s\n", elapsed.Round(time.Microsecond))
+	table.Flush()
+	fmt.Printf("Allocated in total: %d MiB, in use after the comparison: %d MiB\n", memStats.TotalAlloc>>20, memStats.HeapAlloc>>20)
+	fmt.Printf("Wrote %s and %s, see go tool pprof\n", *cpuProfile, *heapProfile)
+}
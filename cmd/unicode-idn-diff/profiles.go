@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Named sets of flag values for the main audiences of the comparison
+var profiles = map[string]map[string]string{
+	// Experts reviewing a new version for the IETF: everything that may need
+	// a decision, with the trail of the computation
+	"expert-review": {
+		"exceptions":             "true",
+		"nfk-hazards":            "true",
+		"decomposition-types":    "true",
+		"decomposition-mappings": "true",
+		"bidi-class":             "true",
+		"joining-type":           "true",
+		"combining-class":        "true",
+		"mark-rendering":         "true",
+		"case-folding":           "true",
+		"case-pairs":             "true",
+		"categories":             "true",
+		"bidi":                   "true",
+		"strict":                 "true",
+		"format":                 "text",
+	},
+	// Registries updating their label generation rules: what changed in
+	// which direction, and how many code points are affected
+	"registry-impact": {
+		"frequencies":    "true",
+		"bidi":           "true",
+		"script-changes": "true",
+		"format":         "text",
+	},
+	// Implementers updating their IDNA tables: only the changes of derived
+	// property values, in a format that is easy to apply
+	"implementer": {
+		"format": "delta",
+	},
+}
+
+// Sets the flags of a profile that were not given on the command line
+func applyProfile(flags *flag.FlagSet, name string) error {
+	profile, ok := profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile %q, expected one of %s", name, strings.Join(slices.Sorted(maps.Keys(profiles)), ", "))
+	}
+	given := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+	for flagName, value := range profile {
+		if given[flagName] || flags.Lookup(flagName) == nil {
+			continue
+		}
+		if err := flags.Set(flagName, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
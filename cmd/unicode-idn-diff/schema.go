@@ -0,0 +1,17 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/patrikhson/unicode-idn-diff/pkg/idndiff"
+)
+
+// Writes the JSON schema of the report to standard output
+func schemaMain(args []string) {
+	if len(args) != 0 {
+		fmt.Println("Usage: unicode-idn-diff schema")
+		return
+	}
+	os.Stdout.Write(idndiff.ReportSchema)
+}
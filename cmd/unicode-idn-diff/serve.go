@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/patrikhson/unicode-idn-diff/pkg/idndiff"
+)
+
+// Runs the serve command, which answers comparisons over HTTP, as in
+// GET /compare?version1=15.1.0&version2=16.0.0&format=json, with the data
+// files read once and kept in memory between requests
+func serveMain(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	var opts idndiff.Options
+	var renderOpts idndiff.RenderOptions
+	dataDir := compareFlags(flags, &opts)
+	renderFlags(flags, &renderOpts)
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	args = parseArgs(flags, args)
+
+	if len(args) != 0 {
+		fmt.Println("Usage: unicode-idn-diff serve [flags]")
+		flags.PrintDefaults()
+		return
+	}
+
+	handler := serveHandler(newLoader(*dataDir), opts, renderOpts)
+	fmt.Printf("Serving comparisons of %s on http://%s/compare\n", *dataDir, *addr)
+	if err := http.ListenAndServe(*addr, handler); err != nil {
+		fmt.Printf("Error %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// Returns the handler of the serve command. The loader is shared by the
+// requests, which can run at the same time.
+func serveHandler(loader *idndiff.Loader, opts idndiff.Options, renderOpts idndiff.RenderOptions) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/compare", func(w http.ResponseWriter, req *http.Request) {
+		version1, version2 := req.FormValue("version1"), req.FormValue("version2")
+		if version1 != idndiff.RFC5892Baseline && !unicodeVersionRegex.MatchString(version1) || !unicodeVersionRegex.MatchString(version2) {
+			http.Error(w, "invalid or missing version1 or version2, use the format 12.0.0", http.StatusBadRequest)
+			return
+		}
+		if message := versionOrder(version1, version2); message != "" {
+			http.Error(w, message, http.StatusBadRequest)
+			return
+		}
+		name := req.FormValue("format")
+		if name == "" {
+			name = "json"
+		}
+		format, ok := idndiff.Formats[name]
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown output format %q", name), http.StatusBadRequest)
+			return
+		}
+		report, err := idndiff.Compare(loader, version1, version2, opts)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		var buffer bytes.Buffer
+		if err := format.Render(&buffer, report, renderOpts); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", format.MediaType)
+		w.Write(buffer.Bytes())
+	})
+	mux.HandleFunc("/schema", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/schema+json")
+		w.Write(idndiff.ReportSchema)
+	})
+	return mux
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/patrikhson/unicode-idn-diff/pkg/idndiff"
+)
+
+// Comparisons are answered in each format with its Content-Type, also when
+// requested at the same time, and invalid requests are refused
+func TestServe(t *testing.T) {
+	loader := idndiff.NewLoader(filepath.Join("..", "..", "pkg", "idndiff", "testdata", "transitions"))
+	server := httptest.NewServer(serveHandler(loader, idndiff.Options{}, idndiff.RenderOptions{}))
+	defer server.Close()
+
+	tests := []struct {
+		path        string
+		status      int
+		contentType string
+		body        string // What the body has
+	}{
+		{"/compare?version1=12.1.0&version2=13.0.0", http.StatusOK, "application/json", `"version2": "13.0.0"`},
+		{"/compare?version1=12.1.0&version2=13.0.0&format=text", http.StatusOK, "text/plain; charset=utf-8", "Appendix A"},
+		{"/compare?version1=12.1.0&version2=13.0.0&format=html", http.StatusOK, "text/html; charset=utf-8", "<html"},
+		{"/compare?version1=12.1.0&version2=13.0.0&format=xml2rfc", http.StatusOK, "application/xml", "<back>"},
+		{"/compare?version1=12.1.0&version2=13.0.0&format=pdf", http.StatusBadRequest, "", `unknown output format "pdf"`},
+		{"/compare?version1=11.0.0&version2=13.0.0", http.StatusBadRequest, "", "invalid or missing version1 or version2"},
+		{"/compare?version2=13.0.0", http.StatusBadRequest, "", "invalid or missing version1 or version2"},
+		{"/compare?version1=13.0.0&version2=12.1.0", http.StatusBadRequest, "", "Swap the versions"},
+		{"/compare?version1=12.1.0&version2=19.0.0", http.StatusInternalServerError, "", "19.0.0"},
+		{"/schema", http.StatusOK, "application/schema+json", `"$schema"`},
+	}
+	var wg sync.WaitGroup
+	for range 4 {
+		for _, test := range tests {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				resp, err := http.Get(server.URL + test.path)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer resp.Body.Close()
+				body, err := io.ReadAll(resp.Body)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if resp.StatusCode != test.status {
+					t.Errorf("%s: status %d, want %d: %s", test.path, resp.StatusCode, test.status, body)
+				}
+				if test.contentType != "" && resp.Header.Get("Content-Type") != test.contentType {
+					t.Errorf("%s: Content-Type %q, want %q", test.path, resp.Header.Get("Content-Type"), test.contentType)
+				}
+				if !strings.Contains(string(body), test.body) {
+					t.Errorf("%s: no %q in %.200q", test.path, test.body, body)
+				}
+				if test.contentType == "application/json" && !json.Valid(body) {
+					t.Errorf("%s: invalid JSON", test.path)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/patrikhson/unicode-idn-diff/pkg/idndiff"
+)
+
+// Runs the tickets command, which writes a stub of an issue per code point,
+// or range of code points, to review
+func ticketsMain(args []string) {
+	flags := flag.NewFlagSet("tickets", flag.ExitOnError)
+	var opts idndiff.Options
+	var renderOpts idndiff.RenderOptions
+	dataDir := compareFlags(flags, &opts)
+	renderFlags(flags, &renderOpts)
+	format := flags.String("format", "markdown", "format of the tickets: markdown or json")
+	output := flags.String("o", "tickets", "directory to write the tickets to, one file per ticket")
+	githubRepo := flags.String("github-repo", "", "file an issue per candidate in this GitHub repository (owner/repo) instead, updating those filed earlier")
+	githubTokenEnv := flags.String("github-token-env", "GITHUB_TOKEN", "environment variable with the GitHub token for -github-repo")
+	githubAPI := flags.String("github-api", "https://api.github.com", "URL of the GitHub API, for GitHub Enterprise")
+	args = parseArgs(flags, args)
+
+	if len(args) != 2 {
+		fmt.Println("Usage: unicode-idn-diff tickets [flags] <version1> <version2>")
+		flags.PrintDefaults()
+		return
+	}
+	if !validVersions(args[0], args[1]) {
+		return
+	}
+	extension, write := ".md", func(w io.Writer, r *idndiff.Report, ticket idndiff.ReviewTicket) error {
+		return idndiff.WriteTicketMarkdown(w, r, ticket, renderOpts)
+	}
+	switch strings.ToLower(*format) {
+	case "markdown":
+	case "json":
+		extension, write = ".json", idndiff.WriteTicketJSON
+	default:
+		fmt.Printf("Error: unknown ticket format %q\n", *format)
+		return
+	}
+
+	report, err := idndiff.Compare(newLoader(*dataDir), args[0], args[1], opts)
+	if err != nil {
+		fmt.Printf("Error %s\n", err)
+		os.Exit(1)
+	}
+	if *githubRepo != "" {
+		token := os.Getenv(*githubTokenEnv)
+		if token == "" {
+			fmt.Printf("Error: no GitHub token in the environment variable %s\n", *githubTokenEnv)
+			os.Exit(1)
+		}
+		issues := &githubIssues{api: *githubAPI, repo: *githubRepo, token: token, client: &http.Client{Timeout: time.Minute}}
+		created, updated, err := issues.file(report, idndiff.CandidateTickets(report), renderOpts)
+		if err != nil {
+			fmt.Printf("Error %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Filed %d new issues and updated %d in %s\n", created, updated, *githubRepo)
+		return
+	}
+
+	tickets := idndiff.ReviewTickets(report)
+	if err := idndiff.WriteTickets(*output, extension, write, report, tickets); err != nil {
+		fmt.Printf("Error %s\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Wrote %d tickets to %s\n", len(tickets), *output)
+}
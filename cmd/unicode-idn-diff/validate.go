@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/patrikhson/unicode-idn-diff/pkg/idndiff"
+)
+
+// Runs the validate command, which checks that the data files of versions
+// are there and can be parsed, before they are compared
+func validateMain(args []string) {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	dataDir := flags.String("data", ".", "directory or http(s) URL with one subdirectory per version")
+	checkBackends := flags.Bool("check-normalization-backends", false, "also normalize every code point with each normalization backend ("+strings.Join(idndiff.NormalizationBackendNames(), ", ")+") and list the code points where they differ")
+	duplicateFlags(flags)
+	args = parseArgs(flags, args)
+
+	if len(args) == 0 {
+		fmt.Println("Usage: unicode-idn-diff validate [flags] <version> [<version> ...]")
+		flags.PrintDefaults()
+		return
+	}
+	for _, version := range args {
+		if version != idndiff.RFC5892Baseline && !unicodeVersionRegex.MatchString(version) {
+			fmt.Println("Invalid version format. Please use the format 12.0.0")
+			return
+		}
+	}
+
+	loader := newLoader(*dataDir)
+	failed := false
+	for _, version := range args {
+		fmt.Printf("Data files of version %s in %s\n", version, loader.Path(version, ""))
+		table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(table, "File\tNeeded by\tStatus\n")
+		for _, check := range idndiff.Validate(loader, version) {
+			neededBy := "some sections"
+			if check.Required {
+				neededBy = "every comparison"
+			}
+			status := check.Status
+			if check.Error != "" {
+				status += ": " + check.Error
+			}
+			fmt.Fprintf(table, "%s\t%s\t%s\n", check.Name, neededBy, status)
+			if check.Status == "invalid" || (check.Required && check.Status == "missing") {
+				failed = true
+			}
+		}
+		table.Flush()
+		for _, warning := range loader.DuplicateWarnings(version) {
+			fmt.Printf("WARNING: %s\n", warning)
+		}
+		if *checkBackends && !checkNormalizationBackends(loader, version) {
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// Compares the normalization backends for a version, and reports whether
+// they agree on every code point
+func checkNormalizationBackends(loader *idndiff.Loader, version string) bool {
+	check, err := idndiff.CheckNormalizationBackends(loader, version)
+	if err != nil {
+		fmt.Printf("ERROR: %s\n", err)
+		return false
+	}
+	fmt.Printf("Normalization backends compared: %s\n", strings.Join(check.Backends, ", "))
+	if len(check.Skipped) > 0 {
+		fmt.Printf("Normalization backends without data: %s\n", strings.Join(check.Skipped, ", "))
+	}
+	if len(check.NotIndependent) > 0 {
+		fmt.Printf("Normalization backends computed by another one: %s\n", strings.Join(check.NotIndependent, ", "))
+	}
+	fmt.Printf("Code points normalized differently: %d\n", len(check.Differences))
+	if len(check.Differences) == 0 {
+		return true
+	}
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(table, "Code point\t%s\n", strings.Join(check.Backends, "\t"))
+	for _, difference := range check.Differences {
+		fmt.Fprintf(table, "U+%s\t%s\n", difference.CodePoint, strings.Join(difference.NFKC, "\t"))
+	}
+	table.Flush()
+	return false
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/patrikhson/unicode-idn-diff/pkg/idndiff"
+)
+
+// Files in the ucd directory of the Unicode beta that are watched for
+// changes: those that allcodepoints.txt and nfk.txt are generated from, as
+// the derivation needs, and Scripts.txt
+var watchedFiles = []string{
+	"UnicodeData.txt",
+	"extracted/DerivedGeneralCategory.txt",
+	"Scripts.txt",
+	"PropList.txt",
+	"DerivedCoreProperties.txt",
+	"DerivedNormalizationProps.txt",
+	"Blocks.txt",
+	"HangulSyllableType.txt",
+}
+
+// Returned by download for a file that is not on the server
+var errNotFound = errors.New("not found")
+
+// The validators of a file as last downloaded, which are sent back so that
+// the server only sends the file again if it changed
+type validators struct {
+	etag         string
+	lastModified string
+}
+
+// Downloads a file, unless it has not changed since it was downloaded with
+// the validators, in which case it returns nil. The validators are updated
+// from the response.
+func download(client *http.Client, url string, cached *validators) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if cached.etag != "" {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+	if cached.lastModified != "" {
+		req.Header.Set("If-Modified-Since", cached.lastModified)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, nil
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", url, err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s: %w", url, errNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: unexpected HTTP status %s", url, resp.Status)
+	}
+	*cached = validators{resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")}
+	return data, nil
+}
+
+// Downloads the watched files from the beta directory and writes the ones
+// that differ from the local copies to dir. The validators of the files, by
+// name, are kept from one check to the next, so that files that have not
+// changed are not downloaded again. Returns the names of the files that
+// changed, and the errors of those that could not be updated, which do not
+// keep the others from being updated.
+func updateBetaFiles(client *http.Client, betaURL, dir string, cache map[string]*validators) ([]string, error) {
+	var changed []string
+	var errs []error
+	for _, name := range watchedFiles {
+		localPath := filepath.Join(dir, path.Base(name))
+		if cache[name] == nil {
+			cache[name] = &validators{}
+		}
+		// A local copy that is gone has to be downloaded again, changed or not
+		if _, err := os.Stat(localPath); err != nil {
+			*cache[name] = validators{}
+		}
+		data, err := download(client, strings.TrimSuffix(betaURL, "/")+"/"+name, cache[name])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if data == nil {
+			continue
+		}
+
+		if old, err := os.ReadFile(localPath); err == nil && bytes.Equal(old, data) {
+			continue
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return changed, err
+		}
+		if err := os.WriteFile(localPath, data, 0o644); err != nil {
+			// Downloaded again on the next check
+			*cache[name] = validators{}
+			errs = append(errs, err)
+			continue
+		}
+		changed = append(changed, path.Base(name))
+	}
+	return changed, errors.Join(errs...)
+}
+
+// Writes allcodepoints.txt and nfk.txt of the beta version again from the
+// updated files, as generate does, so that the comparison does not use the
+// tables of the files before the update. Each is written to a temporary file
+// first, so that a failure leaves no partial table behind.
+func regenerateTables(dataDir, version string) error {
+	loader := newLoader(dataDir)
+	for _, table := range []struct {
+		name  string
+		write func(w io.Writer, loader *idndiff.Loader, version string) error
+	}{
+		{"allcodepoints.txt", idndiff.WriteDerivedTable},
+		{"nfk.txt", idndiff.WriteNFKTable},
+	} {
+		fileName := filepath.Join(dataDir, version, table.name)
+		file, err := os.Create(fileName + ".tmp")
+		if err != nil {
+			return err
+		}
+		if err := table.write(file, loader, version); err != nil {
+			file.Close()
+			os.Remove(fileName + ".tmp")
+			return fmt.Errorf("generating %s: %w", fileName, err)
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+		if err := os.Rename(fileName+".tmp", fileName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Compares two versions of Unicode and writes the report as text
+func compareVersions(w io.Writer, loader *idndiff.Loader, version1, version2 string, opts idndiff.Options, renderOpts idndiff.RenderOptions) {
+	report, err := idndiff.Compare(loader, version1, version2, opts)
+	if err != nil {
+		fmt.Fprintf(w, "Error %s\n", err)
+		return
+	}
+	idndiff.RenderText(w, report, renderOpts)
+}
+
+// Periodically checks the Unicode beta directory for updated data files,
+// and reruns the comparison and notifies the sinks when they change
+func watchMain(args []string) {
+	flags := flag.NewFlagSet("watch", flag.ExitOnError)
+	var opts idndiff.Options
+	var renderOpts idndiff.RenderOptions
+	dataDir := compareFlags(flags, &opts)
+	renderFlags(flags, &renderOpts)
+	interval := flags.Duration("interval", 24*time.Hour, "how often to check the beta directory")
+	betaURL := flags.String("url", "https://www.unicode.org/Public/draft/ucd/", "URL of the ucd directory of the Unicode beta")
+	notify := flags.String("notify", "stdout", "comma separated sinks: stdout, a file name or an http(s) URL of a webhook")
+	args = parseArgs(flags, args)
+
+	if len(args) != 2 {
+		fmt.Println("Usage: unicode-idn-diff watch [flags] <version1> <beta version>")
+		flags.PrintDefaults()
+		return
+	}
+	version1 := args[0]
+	version2 := args[1]
+	if !validVersions(version1, version2) {
+		return
+	}
+	if strings.HasPrefix(*dataDir, "http://") || strings.HasPrefix(*dataDir, "https://") {
+		fmt.Println("The data directory must be a local directory when watching")
+		return
+	}
+
+	sinks, err := parseSinks(*notify)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return
+	}
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	cache := make(map[string]*validators)
+	for {
+		fmt.Printf("%s: checking %s\n", time.Now().Format(time.RFC3339), *betaURL)
+		changed, err := updateBetaFiles(client, *betaURL, filepath.Join(*dataDir, version2), cache)
+		if err != nil {
+			fmt.Printf("Error checking the beta directory: %s\n", err)
+		}
+		if len(changed) > 0 {
+			fmt.Printf("Changed: %s\n", strings.Join(changed, ", "))
+			if err := regenerateTables(*dataDir, version2); err != nil {
+				fmt.Printf("Error %s\n", err)
+				time.Sleep(*interval)
+				continue
+			}
+
+			// Use a new loader so the updated files are read
+			var report bytes.Buffer
+			compareVersions(&report, newLoader(*dataDir), version1, version2, opts, renderOpts)
+			subject := fmt.Sprintf("Unicode %s beta data changed (%s)", version2, strings.Join(changed, ", "))
+			for _, s := range sinks {
+				if err := s.notify(subject, report.String()); err != nil {
+					fmt.Printf("Error notifying: %s\n", err)
+				}
+			}
+		}
+		time.Sleep(*interval)
+	}
+}
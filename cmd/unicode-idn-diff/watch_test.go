@@ -0,0 +1,69 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// A file is only downloaded again when the server says it changed since the
+// last download, and only written when it differs from the local copy
+func TestUpdateBetaFiles(t *testing.T) {
+	var mu sync.Mutex
+	content := "0041;LATIN CAPITAL LETTER A;Lu;0;L;;;;;N;;;;0061;\n"
+	etag := `"1"`
+	var downloads []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if req.URL.Path != "/ucd/UnicodeData.txt" {
+			http.NotFound(w, req)
+			return
+		}
+		if req.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads = append(downloads, etag)
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	dir := filepath.Join(t.TempDir(), "17.0.0")
+	cache := make(map[string]*validators)
+	for _, check := range []struct {
+		name      string
+		change    func()
+		changed   []string
+		downloads int
+	}{
+		{"first check", func() {}, []string{"UnicodeData.txt"}, 1},
+		{"not modified", func() {}, nil, 1},
+		{"new ETag, same content", func() { etag = `"2"` }, nil, 2},
+		{"modified", func() { etag, content = `"3"`, content+"0042;LATIN CAPITAL LETTER B;Lu;0;L;;;;;N;;;;0062;\n" }, []string{"UnicodeData.txt"}, 3},
+		{"local copy removed", func() { os.Remove(filepath.Join(dir, "UnicodeData.txt")) }, []string{"UnicodeData.txt"}, 4},
+	} {
+		mu.Lock()
+		check.change()
+		mu.Unlock()
+		changed, err := updateBetaFiles(server.Client(), server.URL+"/ucd/", dir, cache)
+		// The other watched files are not on the server
+		if err == nil {
+			t.Errorf("%s: no error for the missing files", check.name)
+		}
+		if !slices.Equal(changed, check.changed) {
+			t.Errorf("%s: changed %v, want %v", check.name, changed, check.changed)
+		}
+		if len(downloads) != check.downloads {
+			t.Errorf("%s: downloaded %d times, want %d", check.name, len(downloads), check.downloads)
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "UnicodeData.txt")); err != nil || string(data) != content {
+		t.Errorf("UnicodeData.txt is %q (%v), want %q", data, err, content)
+	}
+}
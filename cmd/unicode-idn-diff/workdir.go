@@ -0,0 +1,140 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/patrikhson/unicode-idn-diff/pkg/idndiff"
+)
+
+// What a workspace directory has: the versions compared, how, when, the
+// checksums of the files read and written, and of each section of the report
+type runManifest struct {
+	SchemaVersion string                    `json:"schema_version"`
+	Version1      string                    `json:"version1"`
+	Version2      string                    `json:"version2"`
+	Command       []string                  `json:"command"`
+	Started       time.Time                 `json:"started"`
+	Finished      time.Time                 `json:"finished"`
+	Inputs        []idndiff.InputFile       `json:"inputs"`
+	Outputs       []idndiff.InputFile       `json:"outputs"`
+	Sections      []idndiff.SectionChecksum `json:"sections"`
+}
+
+// A file of a workspace directory
+type artifact struct {
+	name string
+	data []byte
+}
+
+// Writes everything about a run to a new directory under dir, named by the
+// versions and the time the run started: the report in every format, the
+// changes per code point as CSV, run.log with the warnings, and
+// manifest.json. With zipped, the directory is written as a zip archive
+// instead. Returns the name of the directory or archive.
+func writeWorkdir(dir string, zipped bool, r *idndiff.Report, opts idndiff.RenderOptions, loader *idndiff.Loader, command []string, started time.Time) (string, error) {
+	var artifacts []artifact
+	for _, name := range slices.Sorted(maps.Keys(idndiff.Formats)) {
+		var buffer bytes.Buffer
+		if err := idndiff.Formats[name].Render(&buffer, r, opts); err != nil {
+			return "", fmt.Errorf("rendering the %s report: %w", name, err)
+		}
+		artifacts = append(artifacts, artifact{"report" + idndiff.Formats[name].Extension, buffer.Bytes()})
+	}
+	for _, section := range idndiff.OverflowSections(r, opts) {
+		artifacts = append(artifacts, artifact{opts.OverflowFileName(section.Name), []byte(section.Text)})
+	}
+	var changes bytes.Buffer
+	if err := idndiff.WriteEventsCSV(&changes, idndiff.ChangeEvents(r)); err != nil {
+		return "", err
+	}
+	artifacts = append(artifacts, artifact{"changes.csv", changes.Bytes()})
+
+	finished := time.Now().UTC()
+	var log strings.Builder
+	fmt.Fprintf(&log, "Command: %s\n", strings.Join(command, " "))
+	fmt.Fprintf(&log, "Compared Unicode %s with Unicode %s in %s\n", r.Version1, r.Version2, finished.Sub(started).Round(time.Millisecond))
+	for _, warning := range r.Warnings {
+		fmt.Fprintf(&log, "WARNING: %s\n", warning)
+	}
+	for _, e := range r.Errors {
+		fmt.Fprintf(&log, "ERROR: %s skipped: %s\n", e.Section, e.Error)
+	}
+	artifacts = append(artifacts, artifact{"run.log", []byte(log.String())})
+
+	manifest := runManifest{
+		SchemaVersion: idndiff.ReportSchemaVersion,
+		Version1:      r.Version1,
+		Version2:      r.Version2,
+		Command:       command,
+		Started:       started,
+		Finished:      finished,
+		Inputs:        loader.Inputs(),
+	}
+	for _, a := range artifacts {
+		sum := sha256.Sum256(a.data)
+		manifest.Outputs = append(manifest.Outputs, idndiff.InputFile{Path: a.name, Size: int64(len(a.data)), SHA256: hex.EncodeToString(sum[:])})
+	}
+	var err error
+	if manifest.Sections, err = idndiff.SectionChecksums(r); err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	artifacts = append(artifacts, artifact{"manifest.json", append(data, '\n')})
+
+	name := fmt.Sprintf("%s-%s-%s", r.Version1, r.Version2, started.Format("20060102T150405Z"))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	if zipped {
+		return writeWorkdirZip(filepath.Join(dir, name+".zip"), name, artifacts)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.Mkdir(path, 0o755); err != nil {
+		return "", err
+	}
+	for _, a := range artifacts {
+		if err := os.WriteFile(filepath.Join(path, a.name), a.data, 0o644); err != nil {
+			return "", err
+		}
+	}
+	return path, nil
+}
+
+// Writes the files of a workspace directory to a zip archive, in a directory
+// of their own
+func writeWorkdirZip(path, dir string, artifacts []artifact) (string, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	archive := zip.NewWriter(file)
+	for _, a := range artifacts {
+		w, err := archive.Create(dir + "/" + a.name)
+		if err == nil {
+			_, err = w.Write(a.data)
+		}
+		if err != nil {
+			file.Close()
+			return "", err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		file.Close()
+		return "", err
+	}
+	return path, file.Close()
+}
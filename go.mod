@@ -1,3 +1,5 @@
-module check_changes
+module github.com/patrikhson/unicode-idn-diff
 
 go 1.24.0
+
+require golang.org/x/text v0.34.0
@@ -0,0 +1,60 @@
+package idndiff
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Reads DerivedAge.txt of the second version and returns the age of each
+// code point in the appendices A-E, which is the version of Unicode it was
+// first assigned in. Unassigned code points have no age and are left out.
+func ageSection(loader *Loader, version2 string, r *Report) ([]CodePointAge, error) {
+	ages, err := loader.PropertyFile(version2, "DerivedAge.txt")
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version2, "DerivedAge.txt"), err)
+	}
+	// Appendix E only lists code points from A, C and D, but the resolved
+	// candidates are not in it
+	listed := listedCodePoints(r)
+	for _, entry := range r.Resolved {
+		listed = append(listed, entry.CodePoint)
+	}
+	slices.SortFunc(listed, func(a, b string) int { return hexToInt(a) - hexToInt(b) })
+	result := []CodePointAge{}
+	for _, codepoint := range slices.Compact(listed) {
+		if age, ok := ages[codepoint]; ok {
+			result = append(result, CodePointAge{codepoint, age})
+		}
+	}
+	return result, nil
+}
+
+// Returns the age of a code point in the appendices A-E, or "Unassigned"
+func (r *Report) age(codepoint string) string {
+	i, found := slices.BinarySearchFunc(r.Ages, hexToInt(codepoint), func(age CodePointAge, codepointInt int) int {
+		return hexToInt(age.CodePoint) - codepointInt
+	})
+	if !found {
+		return "Unassigned"
+	}
+	return r.Ages[i].Age
+}
+
+// Returns the columns of a table of the appendices A-E, with the age after
+// the code point if the report has the ages
+func ageColumns(r *Report, columns ...string) []string {
+	if r.Ages == nil {
+		return columns
+	}
+	return slices.Insert(columns, 1, "Age")
+}
+
+// Returns the values of an entry of the appendices A-E, the first of which is
+// the code point, with the age of the code point after it if the report has
+// the ages
+func ageValues(r *Report, codepoint string, values ...string) []string {
+	if r.Ages == nil {
+		return values
+	}
+	return slices.Insert(values, 1, r.age(codepoint))
+}
@@ -0,0 +1,637 @@
+package idndiff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// An appendix of the reports. The text report writes it with text, and the
+// HTML, Markdown and xml2rfc reports render its tables, which are built from
+// the same fields of the Report.
+type appendix struct {
+	letter string
+	title  string
+	text   func(buffer *strings.Builder, heading string, opts RenderOptions)
+	tables func() []appendixTable
+}
+
+// A table of an appendix
+type appendixTable struct {
+	caption string // "" for the first table of an appendix
+	columns []string
+	rows    []appendixRow
+	// What it means that there are no rows, or "" to leave the table out then
+	empty string
+	// Whether the rows sum up the appendix, as counts or the ranges missing
+	// from it, rather than being entries, which are counted as the entries of
+	// the appendix and left out after MaxEntries
+	summary bool
+}
+
+// A row of a table, with a code point, a range or another key in the first
+// cell
+type appendixRow struct {
+	codepoint string // The code point in the first cell, or "" if it is not one
+	cells     []string
+}
+
+// Returns the heading of an appendix, as in "Appendix A: Code points that
+// changed derived property values"
+func (a appendix) heading() string {
+	return "Appendix " + a.letter + ": " + a.title
+}
+
+// Returns the number of entries in the tables of an appendix
+func (a appendix) entries() int {
+	count := 0
+	for _, table := range a.tables() {
+		if !table.summary {
+			count += len(table.rows)
+		}
+	}
+	return count
+}
+
+// Returns the appendices of the report in order. The optional ones are
+// lettered in order after F.
+func reportAppendices(r *Report) []appendix {
+	appendices := []appendix{
+		{"A", "Code points that changed derived property values", func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderAppendixA(buffer, heading, r, opts)
+		}, func() []appendixTable { return appendixATables(r) }},
+		{"B", "Changes in General Category", func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderAppendixB(buffer, heading, r, opts)
+		}, func() []appendixTable { return appendixBTables(r) }},
+		{"C", "New code points where General Category is Mn", func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderAppendixC(buffer, heading, r, opts)
+		}, func() []appendixTable { return appendixCTables(r) }},
+		{"D", "New code points with NFK normalization", func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderAppendixD(buffer, heading, r, opts)
+		}, func() []appendixTable { return appendixDTables(r) }},
+		{"E", "Additions to Exceptions (F)", func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderAppendixE(buffer, heading, r, opts)
+		}, func() []appendixTable { return appendixETables(r) }},
+		{"F", "Derived property values Unicode " + r.Version2, func(buffer *strings.Builder, heading string, _ RenderOptions) {
+			renderAppendixF(buffer, heading, r)
+		}, func() []appendixTable { return appendixFTables(r) }},
+	}
+	optional := func(title string, text func(buffer *strings.Builder, heading string, opts RenderOptions), tables func() []appendixTable) {
+		appendices = append(appendices, appendix{appendixLetter(len(appendices)), title, text, tables})
+	}
+	if r.Exceptions != nil {
+		optional("Comparison of Exceptions (F) with RFC 5892", func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderExceptions(buffer, heading, r.Exceptions, opts)
+		}, func() []appendixTable { return exceptionsTables(r.Exceptions) })
+	}
+	if r.NFKHazards != nil {
+		optional("PVALID code points with NFK normalization that now includes other derived property values", func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderNFKHazards(buffer, heading, r.NFKHazards, opts)
+		}, func() []appendixTable { return nfkHazardsTables(r.NFKHazards) })
+	}
+	if r.NFKCCaseFold != nil {
+		optional("Code points with NFKC_Casefold mapping changes", func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderNFKCCaseFold(buffer, heading, r.NFKCCaseFold, opts)
+		}, func() []appendixTable { return nfkcCaseFoldTables(r.NFKCCaseFold) })
+	}
+	if r.CaseFolding != nil {
+		optional("Code points with case folding changes", func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderCaseFolding(buffer, heading, r.CaseFolding, opts)
+		}, func() []appendixTable { return caseFoldingTables(r.CaseFolding) })
+	}
+	if r.CaseConsistency != nil {
+		optional("Newly assigned case pairs with unexpected derived property values", func(buffer *strings.Builder, heading string, _ RenderOptions) {
+			renderCaseConsistency(buffer, heading, r.CaseConsistency)
+		}, func() []appendixTable { return caseConsistencyTables(r.CaseConsistency) })
+	}
+	if r.GCConsistency != nil {
+		optional("Code points with a General_Category but no derived property value, or the other way around", func(buffer *strings.Builder, heading string, _ RenderOptions) {
+			renderGCConsistency(buffer, heading, r.GCConsistency)
+		}, func() []appendixTable { return gcConsistencyTables(r.GCConsistency) })
+	}
+	if r.Frequencies != nil {
+		optional("Number of code points per derived property value", func(buffer *strings.Builder, heading string, _ RenderOptions) {
+			renderFrequencies(buffer, heading, r)
+		}, func() []appendixTable { return frequenciesTables(r) })
+	}
+	if r.NewAssignments != nil {
+		optional("Newly assigned code points in Unicode "+r.Version2, func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderNewAssignments(buffer, heading, r.NewAssignments, opts)
+		}, func() []appendixTable { return newAssignmentsTables(r.NewAssignments) })
+	}
+	if r.BidiImpact != nil {
+		optional("New valid code points that matter for the Bidi Rule (RFC 5893)", func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderBidiImpact(buffer, heading, r.BidiImpact, opts)
+		}, func() []appendixTable { return bidiImpactTables(r.BidiImpact) })
+	}
+	if r.CrossCheck != nil {
+		optional("Differences from the appendices of "+r.CrossCheck.Document, func(buffer *strings.Builder, heading string, _ RenderOptions) {
+			renderCrossCheck(buffer, heading, r.CrossCheck)
+		}, func() []appendixTable { return crossCheckTables(r.CrossCheck) })
+	}
+	if r.UTS46 != nil {
+		optional("Differences from the UTS #46 IDNA Mapping Table for Unicode "+r.Version2, func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderUTS46(buffer, heading, r.UTS46, opts)
+		}, func() []appendixTable { return uts46Tables(r.UTS46) })
+	}
+	if r.Homoglyphs != nil {
+		optional("New PVALID code points that are confusable (UTS #39), most risky first", func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderHomoglyphs(buffer, heading, r.Homoglyphs, opts)
+		}, func() []appendixTable { return homoglyphsTables(r.Homoglyphs) })
+	}
+	if r.Informational != nil {
+		optional("Code points whose derived property value held despite related changes (informational)", func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderInformational(buffer, heading, r.Informational, opts)
+		}, func() []appendixTable { return informationalTables(r.Informational) })
+	}
+	if r.RootCauses != nil {
+		optional("Why the derived property values in Appendix A changed", func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderRootCauses(buffer, heading, r.RootCauses, opts)
+		}, func() []appendixTable { return rootCausesTables(r.RootCauses) })
+	}
+	if r.RestrictedScripts != nil {
+		optional("Code points that became PVALID in scripts restricted by "+r.RestrictedScripts.Policy, func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderRestrictedScripts(buffer, heading, r.RestrictedScripts, opts)
+		}, func() []appendixTable { return restrictedScriptsTables(r.RestrictedScripts) })
+	}
+	if r.DecompositionTypes != nil {
+		optional("Code points with decomposition type changes", func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderDecompositionTypes(buffer, heading, r.DecompositionTypes, opts)
+		}, func() []appendixTable { return decompositionTypesTables(r.DecompositionTypes) })
+	}
+	if r.DecompositionMappings != nil {
+		optional("Code points with decomposition mapping changes", func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderDecompositionMappings(buffer, heading, r.DecompositionMappings, opts)
+		}, func() []appendixTable { return decompositionMappingsTables(r.DecompositionMappings) })
+	}
+	if r.BidiClasses != nil {
+		optional("Changes in Bidi_Class", func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderBidiClasses(buffer, heading, r.BidiClasses, opts)
+		}, func() []appendixTable { return propertyChangesTables("Bidi_Class", r.BidiClasses.Changes) })
+	}
+	if r.JoiningTypes != nil {
+		optional("Changes in Joining_Type", func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderJoiningTypes(buffer, heading, r.JoiningTypes, opts)
+		}, func() []appendixTable { return propertyChangesTables("Joining_Type", r.JoiningTypes.Changes) })
+	}
+	if r.ScriptChanges != nil {
+		optional("Changes in Script", func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderScriptChanges(buffer, heading, r.ScriptChanges, opts)
+		}, func() []appendixTable { return propertyChangesTables("Script", r.ScriptChanges.Changes) })
+	}
+	if r.CombiningClasses != nil {
+		optional("Changes in Canonical_Combining_Class", func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderCombiningClasses(buffer, heading, r.CombiningClasses, opts)
+		}, func() []appendixTable { return combiningClassesTables(r.CombiningClasses) })
+	}
+	if r.MarkRendering != nil {
+		optional("Likely rendering of the new code points with General Category Mn", func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderMarkRendering(buffer, heading, r.MarkRendering, opts)
+		}, func() []appendixTable { return markRenderingTables(r.MarkRendering) })
+	}
+	for _, findings := range r.Findings {
+		optional("Found by the detector "+findings.Detector, func(buffer *strings.Builder, heading string, opts RenderOptions) {
+			renderFindings(buffer, heading, findings, opts)
+		}, func() []appendixTable { return findingsTables(findings) })
+	}
+	return appendices
+}
+
+// Returns the letter of the appendix at an index: A to Z, and then AA, AB
+// and so on, as the optional appendices may be more than the alphabet
+func appendixLetter(index int) string {
+	if index < 26 {
+		return string(rune('A' + index))
+	}
+	return string(rune('A'+index/26-1)) + string(rune('A'+index%26))
+}
+
+// Returns a row starting with a code point
+func codePointRow(codepoint string, cells ...string) appendixRow {
+	return appendixRow{codepoint, append([]string{"U+" + codepoint}, cells...)}
+}
+
+// Returns a row of the appendices A-E, with the age after the code point if
+// the report has the ages
+func entryRow(r *Report, codepoint string, cells ...string) appendixRow {
+	return appendixRow{codepoint, ageValues(r, codepoint, append([]string{"U+" + codepoint}, cells...)...)}
+}
+
+// Returns a row starting with a range of code points, which is a code point
+// if the range has only one
+func rangeRow(start, end string, cells ...string) appendixRow {
+	if start == end {
+		return codePointRow(start, cells...)
+	}
+	return appendixRow{"", append([]string{"U+" + start + "..U+" + end}, cells...)}
+}
+
+// Returns the tables of Appendix A: the code points, and the number of them
+// per change of derived property value
+func appendixATables(r *Report) []appendixTable {
+	table := appendixTable{columns: ageColumns(r, "Code point", "Old", "New", "Name"), empty: "No change in derived property value except from UNASSIGNED"}
+	for _, change := range r.AppendixA {
+		table.rows = append(table.rows, entryRow(r, change.CodePoint, change.Old, change.New, change.Name))
+	}
+	counts := appendixTable{caption: "Code points per change", columns: []string{"Old", "New", "Code points"}, summary: true}
+	for _, change := range r.ChangeCounts {
+		counts.rows = append(counts.rows, appendixRow{cells: []string{change.Old, change.New, strconv.Itoa(change.Count)}})
+	}
+	return []appendixTable{table, counts}
+}
+
+// Returns the table of Appendix B
+func appendixBTables(r *Report) []appendixTable {
+	table := appendixTable{columns: ageColumns(r, "Code point", "Old General Category", "New General Category", "Old", "New", "Name"), empty: "No changes in General Category"}
+	for _, change := range r.AppendixB {
+		table.rows = append(table.rows, entryRow(r, change.CodePoint, change.Old, change.New, change.OldProperty, change.NewProperty, change.Name))
+	}
+	return []appendixTable{table}
+}
+
+// Returns the table of Appendix C, with the script of each code point if
+// the report groups them by script
+func appendixCTables(r *Report) []appendixTable {
+	empty := "No new code points with General Category Mn"
+	if r.AppendixCScripts != nil {
+		table := appendixTable{columns: ageColumns(r, "Code point", "Script", "Name"), empty: empty}
+		for _, group := range r.AppendixCScripts {
+			for _, entry := range group.CodePoints {
+				table.rows = append(table.rows, entryRow(r, entry.CodePoint, group.Script, entry.Name))
+			}
+		}
+		return []appendixTable{table}
+	}
+	table := appendixTable{columns: ageColumns(r, "Code point", "Name"), empty: empty}
+	for _, entry := range r.AppendixC {
+		table.rows = append(table.rows, entryRow(r, entry.CodePoint, entry.Name))
+	}
+	return []appendixTable{table}
+}
+
+// Returns the table of Appendix D
+func appendixDTables(r *Report) []appendixTable {
+	table := appendixTable{columns: ageColumns(r, "Code point", "NFK", "Name"), empty: "No new code points with NFK normalization"}
+	for _, entry := range r.AppendixD {
+		table.rows = append(table.rows, entryRow(r, entry.CodePoint, entry.NFK, entry.Name))
+	}
+	return []appendixTable{table}
+}
+
+// Returns the tables of Appendix E: the candidates, and those already
+// resolved
+func appendixETables(r *Report) []appendixTable {
+	table := appendixTable{columns: ageColumns(r, "Code point", "Status", "Name"), empty: "No additional code points to become UNDER REVIEW"}
+	for _, entry := range r.AppendixE {
+		status := "UNDER REVIEW"
+		if entry.Excluded {
+			status = fmt.Sprintf("EXCLUDED FROM REVIEW (%s)", entry.ExclusionReason)
+		}
+		table.rows = append(table.rows, entryRow(r, entry.CodePoint, status, entry.Name+registryNote(entry)))
+	}
+	resolved := appendixTable{caption: "Already resolved", columns: ageColumns(r, "Code point", "Outcome", "Note", "Name")}
+	for _, entry := range r.Resolved {
+		resolved.rows = append(resolved.rows, entryRow(r, entry.CodePoint, entry.Outcome, entry.Note, entry.Name))
+	}
+	return []appendixTable{table, resolved}
+}
+
+// Returns the tables of Appendix F: the ranges, in the CSV format of the
+// IANA registry with that style, and the ranges missing from it
+func appendixFTables(r *Report) []appendixTable {
+	table := appendixTable{columns: []string{"Code points", "Derived property value", "Categories"}, empty: "No ranges"}
+	if r.AppendixFStyle == "iana" {
+		table.columns = ianaHeader
+	}
+	for _, entry := range r.AppendixF {
+		if r.AppendixFStyle == "iana" {
+			table.rows = append(table.rows, appendixRow{cells: ianaRecord(entry)})
+			continue
+		}
+		row := rangeRow(entry.Start, entry.End, entry.Property, strings.Join(entry.Categories, ", "))
+		// A range of one is still a range in Appendix F
+		row.codepoint = ""
+		table.rows = append(table.rows, row)
+	}
+	gaps := appendixTable{caption: "Missing from allcodepoints.txt", columns: []string{"Code points"}, summary: true}
+	for _, gap := range r.AppendixFGaps {
+		gaps.rows = append(gaps.rows, rangeRow(gap.Start, gap.End))
+	}
+	return []appendixTable{table, gaps}
+}
+
+// Returns the tables of the comparison of Exceptions (F) with RFC 5892
+func exceptionsTables(comparison *ExceptionsComparison) []appendixTable {
+	additions := appendixTable{caption: "Additions", columns: []string{"Code point", "Value", "Name"}, empty: "No additions"}
+	for _, entry := range comparison.Additions {
+		additions.rows = append(additions.rows, codePointRow(entry.CodePoint, entry.Value, entry.Name))
+	}
+	removals := appendixTable{caption: "Removals", columns: []string{"Code point", "Value", "Name"}, empty: "No removals"}
+	for _, entry := range comparison.Removals {
+		removals.rows = append(removals.rows, codePointRow(entry.CodePoint, entry.Value, entry.Name))
+	}
+	changes := appendixTable{caption: "Value changes", columns: []string{"Code point", "Published", "Proposed", "Name"}, empty: "No value changes"}
+	for _, entry := range comparison.ValueChanges {
+		changes.rows = append(changes.rows, codePointRow(entry.CodePoint, entry.Published, entry.Proposed, entry.Name))
+	}
+	return []appendixTable{additions, removals, changes}
+}
+
+// Returns the table of the code points with a normalization that now
+// includes other derived property values
+func nfkHazardsTables(hazards *NFKHazards) []appendixTable {
+	table := appendixTable{
+		columns: []string{"Code point", "Old NFK", "New NFK", "Name", "Code points in new NFK"},
+		empty:   "No PVALID code points with NFK normalization that now includes other derived property values",
+	}
+	for _, entry := range hazards.Entries {
+		var targets []string
+		for _, target := range entry.Targets {
+			targets = append(targets, fmt.Sprintf("U+%s %s", target.CodePoint, target.Property))
+		}
+		table.rows = append(table.rows, codePointRow(entry.CodePoint, entry.Old, entry.New, entry.Name, strings.Join(targets, ", ")))
+	}
+	return []appendixTable{table}
+}
+
+// Returns the table of the code points with NFKC_Casefold mapping changes
+func nfkcCaseFoldTables(caseFold *NFKCCaseFoldChanges) []appendixTable {
+	table := appendixTable{
+		columns: []string{"Code point", "Old NFKC_CF", "New NFKC_CF", "Old derived property value", "New derived property value"},
+		empty:   "No NFKC_Casefold mapping changes",
+	}
+	for _, change := range caseFold.Changes {
+		table.rows = append(table.rows, codePointRow(change.CodePoint, caseFoldMapping(change.Old), caseFoldMapping(change.New), change.OldProperty, change.NewProperty))
+	}
+	return []appendixTable{table}
+}
+
+// Returns the table of the code points with full case folding changes
+func caseFoldingTables(caseFolding *CaseFoldingChanges) []appendixTable {
+	table := appendixTable{
+		columns: []string{"Code point", "Old case folding", "New case folding", "Old derived property value", "New derived property value"},
+		empty:   "No case folding changes",
+	}
+	for _, change := range caseFolding.Changes {
+		table.rows = append(table.rows, codePointRow(change.CodePoint, change.Old, change.New, change.OldProperty, change.NewProperty))
+	}
+	return []appendixTable{table}
+}
+
+// Returns the table of the newly assigned case pairs with unexpected
+// derived property values
+func caseConsistencyTables(consistency *CaseConsistency) []appendixTable {
+	table := appendixTable{
+		columns: []string{"Uppercase", "Property", "Lowercase", "Property", "Names"},
+		empty:   fmt.Sprintf("All %d newly assigned case pairs are DISALLOWED (uppercase) and PVALID (lowercase)", consistency.Checked),
+	}
+	for _, pair := range consistency.Anomalies {
+		table.rows = append(table.rows, codePointRow(pair.Upper, pair.UpperProperty, "U+"+pair.Lower, pair.LowerProperty, pair.UpperName+" / "+pair.LowerName))
+	}
+	return []appendixTable{table}
+}
+
+// Returns a table per version of the ranges of code points listed in only
+// one of allcodepoints.txt and DerivedGeneralCategory.txt
+func gcConsistencyTables(consistency *GCConsistency) []appendixTable {
+	var tables []appendixTable
+	for _, version := range consistency.Versions {
+		table := appendixTable{
+			caption: "Version " + version.Version,
+			columns: []string{"Code points", "Missing"},
+			empty:   "allcodepoints.txt and DerivedGeneralCategory.txt list the same code points",
+		}
+		for _, missing := range version.MissingProperty {
+			comment := "no derived property value"
+			if version.Synthesized {
+				comment += ", compared as UNASSIGNED"
+			}
+			table.rows = append(table.rows, rangeRow(missing.Start, missing.End, comment))
+		}
+		for _, missing := range version.MissingCategory {
+			table.rows = append(table.rows, rangeRow(missing.Start, missing.End, "no General_Category"))
+		}
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+// Returns the table of the number of code points per derived property value
+// in both versions
+func frequenciesTables(r *Report) []appendixTable {
+	table := appendixTable{columns: []string{"Property", r.Version1, r.Version2, "Change"}, summary: true}
+	row := func(property string, count1, count2 int) appendixRow {
+		return appendixRow{cells: []string{property, strconv.Itoa(count1), strconv.Itoa(count2), fmt.Sprintf("%+d", count2-count1)}}
+	}
+	for _, value := range r.Frequencies.Values {
+		table.rows = append(table.rows, row(value.Property, value.Count1, value.Count2))
+	}
+	table.rows = append(table.rows, row("Total", r.Frequencies.Total1, r.Frequencies.Total2))
+	return []appendixTable{table}
+}
+
+// Returns the table of the newly assigned code points, a row per range
+func newAssignmentsTables(assignments *NewAssignments) []appendixTable {
+	table := appendixTable{columns: []string{"Code point", "Derived property", "Name"}, empty: "No newly assigned code points"}
+	for _, r := range assignments.Ranges {
+		table.rows = append(table.rows, rangeRow(r.Start, r.End, r.Property, r.Name))
+	}
+	return []appendixTable{table}
+}
+
+// Returns the tables of the code points that became valid and matter for
+// the Bidi Rule, and of their number per script and Bidi_Class
+func bidiImpactTables(impact *BidiImpact) []appendixTable {
+	table := appendixTable{columns: []string{"Code point", "Old", "New", "Bidi_Class", "Script", "Name"}, empty: "No new valid right-to-left letters or digits"}
+	for _, entry := range impact.Entries {
+		old := entry.Old
+		if old == "" {
+			old = "UNASSIGNED"
+		}
+		table.rows = append(table.rows, codePointRow(entry.CodePoint, old, entry.New, entry.BidiClass, entry.Script, entry.Name))
+	}
+	counts := appendixTable{caption: "Code points per script and Bidi_Class", columns: []string{"Script", "Bidi_Class", "Code points"}, summary: true}
+	for _, count := range impact.Counts {
+		counts.rows = append(counts.rows, appendixRow{cells: []string{count.Script, fmt.Sprintf("%s (%s)", count.BidiClass, bidiRuleClasses[count.BidiClass]), strconv.Itoa(count.Count)}})
+	}
+	return []appendixTable{table, counts}
+}
+
+// Returns the tables of the differences between the appendices A-E and
+// those of a published review document
+func crossCheckTables(check *CrossCheck) []appendixTable {
+	counts := appendixTable{columns: []string{"Appendix", "Published", "Computed", "Only published", "Only computed"}, summary: true}
+	differences := appendixTable{caption: "Differences", columns: []string{"Code point", "Difference"}}
+	for _, appendix := range check.Appendices {
+		counts.rows = append(counts.rows, appendixRow{cells: []string{appendix.Appendix, strconv.Itoa(appendix.Published), strconv.Itoa(appendix.Computed),
+			strconv.Itoa(len(appendix.OnlyPublished)), strconv.Itoa(len(appendix.OnlyComputed))}})
+		for _, codepoint := range appendix.OnlyPublished {
+			differences.rows = append(differences.rows, codePointRow(codepoint, "only in the published Appendix "+appendix.Appendix))
+		}
+		for _, codepoint := range appendix.OnlyComputed {
+			differences.rows = append(differences.rows, codePointRow(codepoint, "only in the computed Appendix "+appendix.Appendix))
+		}
+	}
+	return []appendixTable{counts, differences}
+}
+
+// Returns the table of the code points where the derived property value and
+// UTS #46 disagree
+func uts46Tables(comparison *UTS46Comparison) []appendixTable {
+	table := appendixTable{
+		columns: []string{"Code point", "Derived property", "UTS #46 status", "Name"},
+		empty:   fmt.Sprintf("No differences in %d code points", comparison.Checked),
+	}
+	for _, entry := range comparison.Discrepancies {
+		status := entry.Status
+		if entry.IDNA2008Status != "" {
+			status += " " + entry.IDNA2008Status
+		}
+		table.rows = append(table.rows, codePointRow(entry.CodePoint, entry.Property, status, entry.Name))
+	}
+	return []appendixTable{table}
+}
+
+// Returns the tables of the code points that became PVALID and are
+// confusable, and of the new scripts and what they are confusable with
+func homoglyphsTables(risks *HomoglyphRisks) []appendixTable {
+	table := appendixTable{
+		columns: []string{"Code point", "Score", "Script", "Prototype", "Prototype scripts", "Name", "Flags"},
+		empty:   fmt.Sprintf("None of the %d code points that became PVALID are confusable", risks.Checked),
+	}
+	for _, entry := range risks.Entries {
+		table.rows = append(table.rows, codePointRow(entry.CodePoint, strconv.Itoa(entry.Score), entry.Script, entry.Prototype,
+			strings.Join(entry.PrototypeScripts, " "), entry.Name, strings.Join(entry.Flags, ", ")))
+	}
+	scripts := appendixTable{caption: "New scripts", columns: []string{"Script", "PVALID code points", "Whole-script confusable with"}, summary: true}
+	for _, script := range risks.NewScripts {
+		confusables := []string{}
+		for _, with := range script.ConfusableWith {
+			confusables = append(confusables, fmt.Sprintf("%s (%d): U+%s", with.Script, len(with.CodePoints), strings.Join(with.CodePoints, " U+")))
+		}
+		if len(confusables) == 0 {
+			confusables = append(confusables, "none")
+		}
+		scripts.rows = append(scripts.rows, appendixRow{cells: []string{script.Script, strconv.Itoa(script.PVALID), strings.Join(confusables, "; ")}})
+	}
+	return []appendixTable{table, scripts}
+}
+
+// Returns the table of the code points whose derived property value held
+// despite related changes
+func informationalTables(informational *Informational) []appendixTable {
+	table := appendixTable{
+		columns: []string{"Code point", "Change", "Old", "New", "Derived property value", "Reason", "Name"},
+		empty:   "No code points with related changes that kept their derived property value",
+	}
+	for _, entry := range informational.Entries {
+		table.rows = append(table.rows, codePointRow(entry.CodePoint, entry.Change, entry.Old, entry.New, entry.Property, entry.Reason, entry.Name))
+	}
+	return []appendixTable{table}
+}
+
+// Returns the table of why the derived property values in Appendix A changed
+func rootCausesTables(causes *RootCauses) []appendixTable {
+	table := appendixTable{
+		columns: []string{"Code point", "Old", "New", "Old rule", "New rule", "Property changes", "Name"},
+		empty:   "No changes in Appendix A",
+	}
+	for _, entry := range causes.Entries {
+		changes := strings.Join(entry.Changes, ", ")
+		if changes == "" {
+			changes = "none in the UCD files"
+		}
+		if entry.Note != "" {
+			changes += ", " + entry.Note
+		}
+		table.rows = append(table.rows, codePointRow(entry.CodePoint, entry.Old, entry.New, entry.OldRule, entry.NewRule, changes, entry.Name))
+	}
+	return []appendixTable{table}
+}
+
+// Returns the table of the code points that became PVALID in restricted
+// scripts
+func restrictedScriptsTables(restricted *RestrictedScripts) []appendixTable {
+	table := appendixTable{columns: []string{"Code point", "Old", "Script", "Reason", "Name"}, empty: "No code points became PVALID in the restricted scripts"}
+	for _, entry := range restricted.Entries {
+		table.rows = append(table.rows, codePointRow(entry.CodePoint, entry.Old, entry.Script, entry.Reason, entry.Name))
+	}
+	return []appendixTable{table}
+}
+
+// Returns the table of the code points whose decomposition type changed
+func decompositionTypesTables(decompositions *DecompositionChanges) []appendixTable {
+	table := appendixTable{
+		columns: []string{"Code point", "Old type", "New type", "Old decomposition", "New decomposition", "Old derived property value", "New derived property value", "Name"},
+		empty:   "No decomposition type changes",
+	}
+	for _, change := range decompositions.Changes {
+		table.rows = append(table.rows, codePointRow(change.CodePoint, change.Old, change.New, change.OldDecomposition, change.NewDecomposition, change.OldProperty, change.NewProperty, change.Name))
+	}
+	return []appendixTable{table}
+}
+
+// Returns the table of the code points whose full decomposition changed
+func decompositionMappingsTables(decompositions *DecompositionMappingChanges) []appendixTable {
+	table := appendixTable{
+		columns: []string{"Code point", "Old canonical", "New canonical", "Old compatibility", "New compatibility", "Old derived property value", "New derived property value", "Name"},
+		empty:   "No decomposition mapping changes",
+	}
+	for _, change := range decompositions.Changes {
+		table.rows = append(table.rows, codePointRow(change.CodePoint, change.OldCanonical, change.NewCanonical, change.OldCompatibility, change.NewCompatibility, change.OldProperty, change.NewProperty, change.Name))
+	}
+	return []appendixTable{table}
+}
+
+// Returns the table of the assigned code points whose value of a property,
+// such as Bidi_Class, changed. The changes of each property have a type of
+// their own, with the same fields.
+func propertyChangesTables[C BidiClassChange | JoiningTypeChange | ScriptChange](property string, changes []C) []appendixTable {
+	table := appendixTable{
+		columns: []string{"Code point", "Old " + property, "New " + property, "Old derived property value", "New derived property value", "Name"},
+		empty:   "No changes in " + property,
+	}
+	for _, c := range changes {
+		change := BidiClassChange(c)
+		table.rows = append(table.rows, codePointRow(change.CodePoint, change.Old, change.New, change.OldProperty, change.NewProperty, change.Name))
+	}
+	return []appendixTable{table}
+}
+
+// Returns the table of the assigned code points whose
+// Canonical_Combining_Class changed, flagging the changes to or from Virama
+func combiningClassesTables(combiningClasses *CombiningClassChanges) []appendixTable {
+	table := appendixTable{
+		columns: []string{"Code point", "Old ccc", "New ccc", "Old derived property value", "New derived property value", "Name", "Note"},
+		empty:   "No changes in Canonical_Combining_Class",
+	}
+	for _, change := range combiningClasses.Changes {
+		note := ""
+		if change.Virama {
+			note = "VIRAMA, affects the CONTEXTJ rules of ZWNJ and ZWJ"
+		}
+		table.rows = append(table.rows, codePointRow(change.CodePoint, change.Old, change.New, change.OldProperty, change.NewProperty, change.Name, note))
+	}
+	return []appendixTable{table}
+}
+
+// Returns the table of how the code points in Appendix C are likely rendered
+func markRenderingTables(rendering *MarkRendering) []appendixTable {
+	table := appendixTable{
+		columns: []string{"Code point", "Rendering", "Indic_Syllabic_Category", "Indic_Positional_Category", "Canonical_Combining_Class", "Script", "Script_Extensions", "Name"},
+		empty:   "No new code points with General Category Mn",
+	}
+	for _, entry := range rendering.Entries {
+		table.rows = append(table.rows, codePointRow(entry.CodePoint, entry.Rendering, entry.SyllabicCategory, entry.PositionalCategory, entry.CombiningClass, entry.Script, entry.ScriptExtensions, entry.Name))
+	}
+	return []appendixTable{table}
+}
+
+// Returns the table of what an additional change detector found
+func findingsTables(findings DetectorFindings) []appendixTable {
+	table := appendixTable{columns: []string{"Code point", "Old", "New", "Old property", "New property", "Name"}, empty: "Nothing found"}
+	for _, finding := range findings.Findings {
+		table.rows = append(table.rows, codePointRow(finding.CodePoint, finding.Old, finding.New, finding.OldProperty, finding.NewProperty, finding.Name))
+	}
+	return []appendixTable{table}
+}
@@ -0,0 +1,126 @@
+package idndiff
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// The xml2rfc report, rendered from the tables of the appendices, lists the
+// same code points in each appendix as the text report, as -cross-check
+// reads them
+func TestXML2RFCAppendices(t *testing.T) {
+	loader := NewLoader(filepath.Join("testdata", "transitions"))
+	for _, tc := range transitions {
+		report, err := Compare(loader, tc.version1, tc.version2, Options{Exceptions: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		listed := make(map[string]map[string][]string)
+		for _, name := range []string{"text", "xml2rfc"} {
+			var buffer strings.Builder
+			if err := Formats[name].Render(&buffer, report, RenderOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			if name == "xml2rfc" {
+				for _, appendix := range reportAppendices(report) {
+					if !strings.Contains(buffer.String(), "<name>"+escapeXML(appendix.title)+"</name>") {
+						t.Errorf("%s-%s: no section for %s", tc.version1, tc.version2, appendix.heading())
+					}
+				}
+			}
+			path := filepath.Join(t.TempDir(), "report."+name)
+			if err := os.WriteFile(path, []byte(buffer.String()), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if listed[name], err = readPublishedAppendices(path); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if !reflect.DeepEqual(listed["text"], listed["xml2rfc"]) {
+			t.Errorf("%s-%s: the xml2rfc report lists %v, the text report %v", tc.version1, tc.version2, listed["xml2rfc"], listed["text"])
+		}
+	}
+
+	// Each table has at most MaxEntries entries, while the counts are all kept
+	report := Report{
+		AppendixA:    []PropertyChange{{"0B55", "DISALLOWED", "PVALID", "ORIYA SIGN OVERLINE"}, {"19DA", "PVALID", "DISALLOWED", "NEW TAI LUE THAM DIGIT ONE"}},
+		ChangeCounts: []ChangeCount{{"DISALLOWED", "PVALID", 1}, {"PVALID", "DISALLOWED", 1}},
+	}
+	var buffer strings.Builder
+	if err := RenderXML2RFC(&buffer, &report, RenderOptions{MaxEntries: 1}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"U+0B55; DISALLOWED; PVALID; ORIYA SIGN OVERLINE\n# 1 more, all 2 entries are in appendix-A.txt\n", "PVALID; DISALLOWED; 1\n"} {
+		if !strings.Contains(buffer.String(), want) {
+			t.Errorf("no %q in the xml2rfc report", want)
+		}
+	}
+}
+
+// The HTML report has the tables of every appendix, the optional ones too,
+// with each code point expanding to what the report has on it
+func TestHTMLAppendices(t *testing.T) {
+	report := Report{
+		AppendixA: []PropertyChange{{"0B55", "DISALLOWED", "PVALID", "ORIYA SIGN OVERLINE"}},
+		Exceptions: &ExceptionsComparison{
+			Additions:    []ExceptionValue{{"0B55", "PVALID", "ORIYA SIGN OVERLINE"}},
+			ValueChanges: []ExceptionValueChange{{"00DF", "PVALID", "DISALLOWED", "LATIN SMALL LETTER SHARP S"}},
+		},
+	}
+	var buffer strings.Builder
+	if err := RenderHTML(&buffer, &report, RenderOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	page := buffer.String()
+	for _, want := range []string{
+		`<a href="#appendix-G">Appendix G: Comparison of Exceptions (F) with RFC 5892</a> (2)`,
+		"<h3>Additions</h3>",
+		"<thead><tr><th>Code point</th><th>Value</th><th>Name</th></tr></thead>",
+		"<tr><td><details><summary>U&#43;0B55</summary>",
+		"<p>No removals.</p>",
+		"<tr><td>U&#43;00DF</td><td>PVALID</td><td>DISALLOWED</td><td><bdi>LATIN SMALL LETTER SHARP S</bdi></td></tr>",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("no %q in the HTML report", want)
+		}
+	}
+	if strings.Count(page, "<pre>") != 1 {
+		t.Errorf("the HTML report has appendices in <pre>, not only the summary")
+	}
+}
+
+// The Markdown report has the tables of every appendix, the optional ones
+// too, counted in the table of appendices and with at most MaxEntries rows of
+// entries each
+func TestMarkdownAppendices(t *testing.T) {
+	report := Report{
+		AppendixA:    []PropertyChange{{"0B55", "DISALLOWED", "PVALID", "ORIYA SIGN OVERLINE"}, {"19DA", "PVALID", "DISALLOWED", "NEW TAI LUE THAM DIGIT ONE"}},
+		ChangeCounts: []ChangeCount{{"DISALLOWED", "PVALID", 1}, {"PVALID", "DISALLOWED", 1}},
+		Exceptions: &ExceptionsComparison{
+			Additions: []ExceptionValue{{"0B55", "PVALID", "ORIYA SIGN OVERLINE"}},
+			Removals:  []ExceptionValue{{"3007", "PVALID", "IDEOGRAPHIC NUMBER ZERO"}, {"302E", "DISALLOWED", "HANGUL SINGLE DOT TONE MARK"}},
+		},
+	}
+	var buffer strings.Builder
+	if err := RenderMarkdown(&buffer, &report, RenderOptions{MaxEntries: 1}); err != nil {
+		t.Fatal(err)
+	}
+	markdown := buffer.String()
+	for _, want := range []string{
+		"| G | 3 | Comparison of Exceptions (F) with RFC 5892 |\n",
+		"Removals:\n\n| Code point | Value | Name |\n|---|---|---|\n| U+3007 | PVALID | IDEOGRAPHIC NUMBER ZERO |\n\n1 more, all 2 entries are in appendix-G.txt.\n",
+		"No value changes.\n",
+		"| U+0B55 | DISALLOWED | PVALID | ORIYA SIGN OVERLINE |\n\n1 more, all 2 entries are in appendix-A.txt.\n",
+		"| DISALLOWED | PVALID | 1 |\n| PVALID | DISALLOWED | 1 |\n",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("no %q in the Markdown report", want)
+		}
+	}
+	if strings.Contains(markdown, "```") {
+		t.Errorf("the Markdown report has appendices in code blocks")
+	}
+}
@@ -0,0 +1,106 @@
+package idndiff
+
+import (
+	"encoding/csv"
+	"io"
+	"slices"
+	"strings"
+)
+
+// An appendix as rows of values under a header, for spreadsheets
+type AppendixTable struct {
+	Letter string
+	Header []string
+	Rows   [][]string
+}
+
+// Returns the appendices A-F as tables, leaving out the skipped ones. Code
+// points are written as U+XXXX, so that spreadsheets keep them as text
+// rather than reading "0030" as a number.
+func AppendixTables(r *Report) []AppendixTable {
+	// Room for all six, so that the tables being filled in stay in place
+	tables := make([]AppendixTable, 0, 6)
+	add := func(letter string, header ...string) *AppendixTable {
+		if r.Skipped(letter) {
+			return &AppendixTable{}
+		}
+		tables = append(tables, AppendixTable{Letter: letter, Header: header})
+		return &tables[len(tables)-1]
+	}
+
+	a := add("A", "code_point", "old", "new", "name")
+	for _, change := range r.AppendixA {
+		a.Rows = append(a.Rows, []string{"U+" + change.CodePoint, change.Old, change.New, change.Name})
+	}
+	b := add("B", "code_point", "old_general_category", "new_general_category", "old", "new", "name")
+	for _, change := range r.AppendixB {
+		b.Rows = append(b.Rows, []string{"U+" + change.CodePoint, change.Old, change.New, change.OldProperty, change.NewProperty, change.Name})
+	}
+	if r.AppendixCScripts != nil {
+		c := add("C", "code_point", "script", "name")
+		for _, group := range r.AppendixCScripts {
+			for _, entry := range group.CodePoints {
+				c.Rows = append(c.Rows, []string{"U+" + entry.CodePoint, group.Script, entry.Name})
+			}
+		}
+	} else {
+		c := add("C", "code_point", "name")
+		for _, entry := range r.AppendixC {
+			c.Rows = append(c.Rows, []string{"U+" + entry.CodePoint, entry.Name})
+		}
+	}
+	d := add("D", "code_point", "nfk", "name")
+	for _, entry := range r.AppendixD {
+		d.Rows = append(d.Rows, []string{"U+" + entry.CodePoint, entry.NFK, entry.Name})
+	}
+	e := add("E", "code_point", "property", "source", "status", "outcome", "note", "registry", "name")
+	for _, entry := range r.AppendixE {
+		status := "UNDER REVIEW"
+		if entry.Excluded {
+			status = "EXCLUDED FROM REVIEW"
+		}
+		registry := entry.Registry
+		if entry.RegistryValue != "" {
+			registry += " " + entry.RegistryValue
+		}
+		e.Rows = append(e.Rows, []string{"U+" + entry.CodePoint, entry.Property, entry.Source, status, "", entry.ExclusionReason, registry, entry.Name})
+	}
+	for _, entry := range r.Resolved {
+		e.Rows = append(e.Rows, []string{"U+" + entry.CodePoint, entry.Property, entry.Source, "RESOLVED", entry.Outcome, entry.Note, "", entry.Name})
+	}
+	// The age after the code point, in the tables so far, which are those of
+	// the appendices A-E
+	if r.Ages != nil {
+		for i := range tables {
+			tables[i].Header = slices.Insert(tables[i].Header, 1, "age")
+			for j, row := range tables[i].Rows {
+				tables[i].Rows[j] = slices.Insert(row, 1, r.age(strings.TrimPrefix(row[0], "U+")))
+			}
+		}
+	}
+	if r.AppendixFStyle == "iana" {
+		// As the registry, to be compared with it byte for byte
+		f := add("F", ianaHeader...)
+		for _, entry := range r.AppendixF {
+			f.Rows = append(f.Rows, ianaRecord(entry))
+		}
+	} else {
+		f := add("F", "start", "end", "property", "categories")
+		for _, entry := range r.AppendixF {
+			f.Rows = append(f.Rows, []string{"U+" + entry.Start, "U+" + entry.End, entry.Property, strings.Join(entry.Categories, " ")})
+		}
+	}
+	return tables
+}
+
+// Writes an appendix as CSV with a header line, or as TSV if comma is a tab
+func WriteAppendixTable(w io.Writer, table AppendixTable, comma rune) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+	writer.Write(table.Header)
+	for _, row := range table.Rows {
+		writer.Write(row)
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,35 @@
+package idndiff
+
+// The code points that were UNASSIGNED in the first version, with their
+// derived property values in the second, which Appendix A leaves out
+type NewAssignments struct {
+	Count  int                  `json:"count"`
+	Ranges []NewAssignmentRange `json:"ranges"`
+}
+
+// A range of newly assigned code points with the same derived property
+// value. The name of a range is that of its first and last code points, as
+// in "CJK UNIFIED IDEOGRAPH-31350..CJK UNIFIED IDEOGRAPH-323AF".
+type NewAssignmentRange struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Property string `json:"property"`
+	Name     string `json:"name"`
+}
+
+// Finds the newly assigned code points in the ranges of the delta table
+func findNewAssignments(delta []DeltaRange, codePointNames2 map[string]string) *NewAssignments {
+	assignments := &NewAssignments{Ranges: []NewAssignmentRange{}}
+	for _, r := range delta {
+		if r.Old != "UNASSIGNED" {
+			continue
+		}
+		name := codePointNames2[r.Start]
+		if r.End != r.Start {
+			name += ".." + codePointNames2[r.End]
+		}
+		assignments.Ranges = append(assignments.Ranges, NewAssignmentRange{r.Start, r.End, r.New, name})
+		assignments.Count += hexToInt(r.End) - hexToInt(r.Start) + 1
+	}
+	return assignments
+}
@@ -0,0 +1,178 @@
+package idndiff
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Computes the NFKC normalization of code points in one version of Unicode.
+// Each backend computes it its own way, so comparing them with
+// CheckNormalizationBackends catches errors in the tables as well as in the
+// algorithms.
+type NormalizationBackend interface {
+	NFKC(r rune) []rune
+}
+
+// Opens a normalization backend for a version. An error wrapping
+// fs.ErrNotExist means the backend has no data for the version, and one
+// wrapping ErrNotIndependent that its data was computed by another backend;
+// either way it is left out of a check.
+type NormalizationBackendOpener func(loader *Loader, version string) (NormalizationBackend, error)
+
+// Returned by a normalization backend whose data was computed by another
+// backend, so that comparing the two could not find an error in either
+var ErrNotIndependent = errors.New("computed by another normalization backend")
+
+// A registered normalization backend
+type normalizationBackend struct {
+	name string
+	open NormalizationBackendOpener
+}
+
+// The normalization backends, in the order they were registered
+var normalizationBackends []normalizationBackend
+
+// Makes a normalization backend available to CheckNormalizationBackends, such
+// as one built on ICU in a program that uses this package. It panics if the
+// name is taken.
+func RegisterNormalizationBackend(name string, open NormalizationBackendOpener) {
+	for _, backend := range normalizationBackends {
+		if backend.name == name {
+			panic("normalization backend " + name + " registered twice")
+		}
+	}
+	normalizationBackends = append(normalizationBackends, normalizationBackend{name, open})
+}
+
+func init() {
+	RegisterNormalizationBackend("internal", func(loader *Loader, version string) (NormalizationBackend, error) {
+		return openNormalizer(loader, version)
+	})
+	RegisterNormalizationBackend("nfk.txt", func(loader *Loader, version string) (NormalizationBackend, error) {
+		// The nfk.txt written by generate -nfk or fetch is the output of the
+		// internal backend
+		generated, err := loader.nfkGenerated(version)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", loader.Path(version, "nfk.txt"), err)
+		}
+		if generated {
+			return nil, fmt.Errorf("%s: %w", loader.Path(version, "nfk.txt"), ErrNotIndependent)
+		}
+		nfk, err := loader.NFKData(version)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", loader.Path(version, "nfk.txt"), err)
+		}
+		return nfkTable{nfk}, nil
+	})
+	RegisterNormalizationBackend("x/text", func(loader *Loader, version string) (NormalizationBackend, error) {
+		// The tables of golang.org/x/text are for the one version of Unicode
+		// it was built with
+		if version != norm.Version {
+			return nil, fmt.Errorf("golang.org/x/text/unicode/norm implements Unicode %s, not %s: %w", norm.Version, version, fs.ErrNotExist)
+		}
+		return xtextNormalizer{}, nil
+	})
+}
+
+// Returns the names of the normalization backends, in the order they are
+// compared
+func NormalizationBackendNames() []string {
+	var names []string
+	for _, backend := range normalizationBackends {
+		names = append(names, backend.name)
+	}
+	return names
+}
+
+// The pure Go normalizer, computed from the UCD files of the version
+func (n *normalizer) NFKC(r rune) []rune {
+	return n.nfkc(r)
+}
+
+// The normalization as listed in nfk.txt, made by ICU gennorm2 or by a table
+// generation script, but not by WriteNFKTable
+type nfkTable struct {
+	data NFKData
+}
+
+func (t nfkTable) NFKC(r rune) []rune {
+	return t.data.mapping(int(r))
+}
+
+// The normalization by golang.org/x/text/unicode/norm
+type xtextNormalizer struct{}
+
+func (xtextNormalizer) NFKC(r rune) []rune {
+	// Surrogates cannot be encoded in UTF-8, and have no decomposition
+	if !utf8.ValidRune(r) {
+		return []rune{r}
+	}
+	return []rune(norm.NFKC.String(string(r)))
+}
+
+// The result of comparing the normalization backends for a version
+type BackendCheck struct {
+	Version string
+	// The backends compared, those without data for the version, and those
+	// whose data was computed by another backend
+	Backends       []string
+	Skipped        []string
+	NotIndependent []string
+	// Code points where the backends differ
+	Differences []BackendDifference
+}
+
+// A code point that the backends normalize differently, with the
+// normalization by each backend, in the order of BackendCheck.Backends
+type BackendDifference struct {
+	CodePoint string
+	NFKC      []string
+}
+
+// Normalizes every code point with each normalization backend that has data
+// for the version, and finds the code points where they differ. It fails if
+// fewer than two backends have data.
+func CheckNormalizationBackends(loader *Loader, version string) (*BackendCheck, error) {
+	check := &BackendCheck{Version: version}
+	var backends []NormalizationBackend
+	for _, backend := range normalizationBackends {
+		opened, err := backend.open(loader, version)
+		if errors.Is(err, fs.ErrNotExist) {
+			check.Skipped = append(check.Skipped, backend.name)
+			continue
+		}
+		if errors.Is(err, ErrNotIndependent) {
+			check.NotIndependent = append(check.NotIndependent, backend.name)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("normalization backend %s: %w", backend.name, err)
+		}
+		check.Backends = append(check.Backends, backend.name)
+		backends = append(backends, opened)
+	}
+	if len(backends) < 2 {
+		return nil, fmt.Errorf("only %d independent normalization backends have data for %s, at least two are needed (missing: %s; not independent: %s)",
+			len(backends), version, strings.Join(check.Skipped, ", "), strings.Join(check.NotIndependent, ", "))
+	}
+
+	for codepointInt := 0; codepointInt < codeSpaceSize; codepointInt++ {
+		first := formatNFK(backends[0].NFKC(rune(codepointInt)))
+		differs := false
+		results := []string{first}
+		for _, backend := range backends[1:] {
+			result := formatNFK(backend.NFKC(rune(codepointInt)))
+			differs = differs || result != first
+			results = append(results, result)
+		}
+		if differs {
+			check.Differences = append(check.Differences, BackendDifference{codepointKey(codepointInt), results})
+		}
+	}
+	return check, nil
+}
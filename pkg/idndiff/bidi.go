@@ -0,0 +1,70 @@
+package idndiff
+
+import (
+	"fmt"
+	"sort"
+)
+
+// The Bidi_Class values that matter most for the Bidi Rule of RFC 5893:
+// right-to-left letters, which make a label an RTL label, and the two kinds of
+// digits that may not be mixed in one RTL label
+var bidiRuleClasses = map[string]string{
+	"R":  "right-to-left letter",
+	"AL": "right-to-left Arabic letter",
+	"AN": "Arabic number",
+	"EN": "European number",
+}
+
+// Reads the Bidi_Class and Script of the second version and finds the code
+// points that matter for the Bidi Rule
+func bidiSection(loader *Loader, version2 string, codepoints []int, properties1, properties2 map[string]string) (*BidiImpact, error) {
+	unicodeData2, err := loader.UnicodeData(version2)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version2, "UnicodeData.txt"), err)
+	}
+	scripts2, err := loader.PropertyFile(version2, "Scripts.txt")
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version2, "Scripts.txt"), err)
+	}
+	return findBidiImpact(codepoints, properties1, properties2, unicodeData2, scripts2), nil
+}
+
+// Finds the code points that became PVALID, or CONTEXTJ or CONTEXTO, and
+// have a Bidi_Class among bidiRuleClasses, and counts them per script and
+// Bidi_Class
+func findBidiImpact(codepoints []int, properties1, properties2 map[string]string, unicodeData2 map[string]unicodeDataEntry, scripts2 map[string]string) *BidiImpact {
+	impact := &BidiImpact{}
+	counts := make(map[BidiCount]int)
+	for _, codepointInt := range codepoints {
+		codepoint := codepointKey(codepointInt)
+		newProperty := properties2[codepoint]
+		if newProperty != "PVALID" && newProperty != "CONTEXTJ" && newProperty != "CONTEXTO" {
+			continue
+		}
+		if oldProperty, existedBefore := properties1[codepoint]; existedBefore && oldProperty == newProperty {
+			continue
+		}
+		entry := unicodeData2[codepoint]
+		if _, ok := bidiRuleClasses[entry.BidiClass]; !ok {
+			continue
+		}
+		script := scripts2[codepoint]
+		if script == "" {
+			script = "Unknown"
+		}
+		impact.Entries = append(impact.Entries, BidiEntry{codepoint, properties1[codepoint], newProperty, entry.BidiClass, script, entry.Name})
+		counts[BidiCount{Script: script, BidiClass: entry.BidiClass}]++
+	}
+
+	for count, n := range counts {
+		count.Count = n
+		impact.Counts = append(impact.Counts, count)
+	}
+	sort.Slice(impact.Counts, func(i, j int) bool {
+		if impact.Counts[i].Script != impact.Counts[j].Script {
+			return impact.Counts[i].Script < impact.Counts[j].Script
+		}
+		return impact.Counts[i].BidiClass < impact.Counts[j].BidiClass
+	})
+	return impact
+}
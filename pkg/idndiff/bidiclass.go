@@ -0,0 +1,60 @@
+package idndiff
+
+import "fmt"
+
+// Assigned code points whose Bidi_Class changed, if requested
+type BidiClassChanges struct {
+	Changes []BidiClassChange `json:"changes"`
+}
+
+// A change of Bidi_Class, with the derived property values of the code point
+type BidiClassChange struct {
+	CodePoint   string `json:"code_point"`
+	Old         string `json:"old"`
+	New         string `json:"new"`
+	OldProperty string `json:"old_property"`
+	NewProperty string `json:"new_property"`
+	Name        string `json:"name"`
+}
+
+// Returns the Bidi_Class of a code point from DerivedBidiClass.txt. Code
+// points that are not listed have the default, L.
+func bidiClassOf(bidiClasses map[string]string, codepoint string) string {
+	if class, ok := bidiClasses[codepoint]; ok {
+		return class
+	}
+	return "L"
+}
+
+// Finds the code points assigned in both versions whose Bidi_Class changed.
+// The Bidi Rule of RFC 5893 is defined by Bidi_Class, so such changes can
+// make labels valid or invalid as much as changes of General Category can.
+func compareBidiClasses(codepoints []int, properties1, properties2, codePointNames2 map[string]string, bidiClasses1, bidiClasses2 map[string]string) []BidiClassChange {
+	var changes []BidiClassChange
+	for _, codepointInt := range codepoints {
+		codepoint := codepointKey(codepointInt)
+		oldProperty, existedBefore := properties1[codepoint]
+		newProperty := properties2[codepoint]
+		if !existedBefore || oldProperty == "UNASSIGNED" || newProperty == "UNASSIGNED" {
+			continue
+		}
+		old, new := bidiClassOf(bidiClasses1, codepoint), bidiClassOf(bidiClasses2, codepoint)
+		if old != new {
+			changes = append(changes, BidiClassChange{codepoint, old, new, oldProperty, newProperty, codePointNames2[codepoint]})
+		}
+	}
+	return changes
+}
+
+// Reads DerivedBidiClass.txt of both versions and compares the Bidi_Class
+func bidiClassSection(loader *Loader, version1, version2 string, codepoints []int, properties1, properties2, codePointNames2 map[string]string) (*BidiClassChanges, error) {
+	bidiClasses1, err := loader.PropertyFile(version1, "DerivedBidiClass.txt")
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version1, "DerivedBidiClass.txt"), err)
+	}
+	bidiClasses2, err := loader.PropertyFile(version2, "DerivedBidiClass.txt")
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version2, "DerivedBidiClass.txt"), err)
+	}
+	return &BidiClassChanges{compareBidiClasses(codepoints, properties1, properties2, codePointNames2, bidiClasses1, bidiClasses2)}, nil
+}
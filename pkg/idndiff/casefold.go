@@ -0,0 +1,141 @@
+package idndiff
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Reads the NFKC_Casefold (NFKC_CF) mappings from DerivedNormalizationProps.txt,
+// with lines like "00A0 ; NFKC_CF; 0020 # Zs NO-BREAK SPACE". Code points
+// that NFKC_Casefold removes, such as "00AD ; NFKC_CF; # Cf SOFT HYPHEN", map
+// to an empty mapping.
+func readNFKCCaseFold(r io.Reader) (NFKData, error) {
+	data := make(NFKData)
+	scanner := newLineScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		fields := strings.Split(strings.Split(scanner.Text(), "#")[0], ";")
+		if len(fields) < 3 || strings.TrimSpace(fields[1]) != "NFKC_CF" {
+			continue
+		}
+		mapping := NFKMapping{}
+		for _, field := range strings.Fields(fields[2]) {
+			target, err := parseCodepoint(field)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			mapping = append(mapping, target)
+		}
+		first, last, isRange := strings.Cut(strings.TrimSpace(fields[0]), "..")
+		if !isRange {
+			last = first
+		}
+		start, err := parseCodepoint(first)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		end, err := parseCodepoint(last)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		for i := start; i <= end; i++ {
+			data[codepointKey(int(i))] = mapping
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Reads the NFKC_Casefold mappings of both versions and compares them
+func nfkcCaseFoldSection(loader *Loader, version1, version2 string, codepoints []int, properties1, properties2 map[string]string) (*NFKCCaseFoldChanges, error) {
+	caseFold1, err := loader.NFKCCaseFold(version1)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version1, "DerivedNormalizationProps.txt"), err)
+	}
+	caseFold2, err := loader.NFKCCaseFold(version2)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version2, "DerivedNormalizationProps.txt"), err)
+	}
+	return &NFKCCaseFoldChanges{compareNFKCCaseFold(codepoints, properties1, properties2, caseFold1, caseFold2)}, nil
+}
+
+// Finds the code points that were assigned in the first version whose
+// NFKC_Casefold mapping changed. Unstable (B) of RFC 5892 is defined by
+// NFKC_Casefold, so such changes can change the derived property value.
+func compareNFKCCaseFold(codepoints []int, properties1, properties2 map[string]string, caseFold1, caseFold2 NFKData) []NFKChange {
+	var changes []NFKChange
+	for _, codepointInt := range codepoints {
+		codepoint := codepointKey(codepointInt)
+		oldProperty, existedBefore := properties1[codepoint]
+		if !existedBefore || oldProperty == "UNASSIGNED" {
+			continue
+		}
+		oldMapping := caseFold1.mapping(codepointInt)
+		newMapping := caseFold2.mapping(codepointInt)
+		if !slices.Equal(oldMapping, newMapping) {
+			changes = append(changes, NFKChange{codepoint, formatNFK(oldMapping), formatNFK(newMapping), oldProperty, properties2[codepoint]})
+		}
+	}
+	return changes
+}
+
+// Reads the full case folding from CaseFolding.txt, the mappings with
+// status C (common) or F (full), with lines like
+// "00DF; F; 0073 0073; # LATIN SMALL LETTER SHARP S". The simple (S) and
+// Turkic (T) mappings are left out, as toCaseFold and NFKC_Casefold do.
+func readCaseFolding(r io.Reader) (NFKData, error) {
+	data := make(NFKData)
+	scanner := newLineScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		fields := strings.Split(strings.Split(scanner.Text(), "#")[0], ";")
+		if len(fields) < 3 {
+			continue
+		}
+		if status := strings.TrimSpace(fields[1]); status != "C" && status != "F" {
+			continue
+		}
+		codepoint, err := parseCodepoint(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		var mapping NFKMapping
+		for _, field := range strings.Fields(fields[2]) {
+			target, err := parseCodepoint(field)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			mapping = append(mapping, target)
+		}
+		data[codepointKey(int(codepoint))] = mapping
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Reads the full case folding of both versions and compares them
+func caseFoldingSection(loader *Loader, version1, version2 string, codepoints []int, properties1, properties2 map[string]string) (*CaseFoldingChanges, error) {
+	caseFolding1, err := loader.CaseFolding(version1)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version1, "CaseFolding.txt"), err)
+	}
+	caseFolding2, err := loader.CaseFolding(version2)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version2, "CaseFolding.txt"), err)
+	}
+	// Code points that are not listed fold to themselves, as they do without
+	// a mapping in nfk.txt, so the comparison is the same
+	return &CaseFoldingChanges{compareNFKCCaseFold(codepoints, properties1, properties2, caseFolding1, caseFolding2)}, nil
+}
@@ -0,0 +1,50 @@
+package idndiff
+
+// Checks the case pairs in which at least one of the code points is newly
+// assigned. The uppercase letter is expected to be DISALLOWED (it is Unstable)
+// and the lowercase letter PVALID; pairs where that does not hold are
+// returned, as such anomalies have historically needed exceptions.
+func findCaseAnomalies(codepoints []int, properties1, properties2 map[string]string, unicodeData2 map[string]unicodeDataEntry) (int, []CasePair) {
+	checked := 0
+	var anomalies []CasePair
+	seen := make(map[[2]string]bool)
+
+	for _, codepointInt := range codepoints {
+		codepoint := codepointKey(codepointInt)
+		if oldProperty, existedBefore := properties1[codepoint]; existedBefore && oldProperty != "UNASSIGNED" {
+			continue
+		}
+		if properties2[codepoint] == "UNASSIGNED" {
+			continue
+		}
+
+		var upper, lower string
+		entry := unicodeData2[codepoint]
+		switch {
+		case entry.GeneralCategory == "Lu" && entry.Lowercase != "":
+			upper, lower = codepoint, entry.Lowercase
+		case entry.GeneralCategory == "Ll" && entry.Uppercase != "":
+			upper, lower = entry.Uppercase, codepoint
+		default:
+			continue
+		}
+		// Only pairs where both letters are assigned, and each pair once
+		if properties2[upper] == "UNASSIGNED" || properties2[lower] == "UNASSIGNED" || seen[[2]string{upper, lower}] {
+			continue
+		}
+		seen[[2]string{upper, lower}] = true
+		checked++
+
+		if properties2[upper] != "DISALLOWED" || properties2[lower] != "PVALID" {
+			anomalies = append(anomalies, CasePair{
+				Upper:         upper,
+				UpperProperty: properties2[upper],
+				UpperName:     unicodeData2[upper].Name,
+				Lower:         lower,
+				LowerProperty: properties2[lower],
+				LowerName:     unicodeData2[lower].Name,
+			})
+		}
+	}
+	return checked, anomalies
+}
@@ -0,0 +1,85 @@
+package idndiff
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Where the code charts of Unicode are published, one PDF file per block
+// named by the first code point of the block, as in U0900.pdf
+const ChartBaseURL = "https://www.unicode.org/charts/PDF/"
+
+// A block of the second version with code points listed in the appendices
+// A-E, and the file of its code chart
+type ChartBlock struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+	Name  string `json:"name"`
+	Chart string `json:"chart"`
+}
+
+// Returns the code points listed in the appendices A-E, in order and without
+// duplicates
+func listedCodePoints(r *Report) []string {
+	var listed []string
+	for _, change := range r.AppendixA {
+		listed = append(listed, change.CodePoint)
+	}
+	for _, change := range r.AppendixB {
+		listed = append(listed, change.CodePoint)
+	}
+	for _, entry := range r.AppendixC {
+		listed = append(listed, entry.CodePoint)
+	}
+	for _, entry := range r.AppendixD {
+		listed = append(listed, entry.CodePoint)
+	}
+	// Appendix E only lists code points from A, C and D
+	slices.SortFunc(listed, func(a, b string) int { return hexToInt(a) - hexToInt(b) })
+	return slices.Compact(listed)
+}
+
+// Returns the blocks, from Blocks.txt, of the code points listed in the
+// appendices A-E, in order
+func chartBlocks(r *Report, blocks map[string]string) []ChartBlock {
+	needed := make(map[string]bool)
+	for _, codepoint := range listedCodePoints(r) {
+		if name, ok := blocks[codepoint]; ok {
+			needed[name] = true
+		}
+	}
+	if len(needed) == 0 {
+		return nil
+	}
+	// Blocks are ranges of code points, so a block is from the first to the
+	// last of its code points
+	start, end := make(map[string]int), make(map[string]int)
+	for codepoint, name := range blocks {
+		if !needed[name] {
+			continue
+		}
+		codepointInt := hexToInt(codepoint)
+		if first, ok := start[name]; !ok || codepointInt < first {
+			start[name] = codepointInt
+		}
+		end[name] = max(end[name], codepointInt)
+	}
+	var charts []ChartBlock
+	for name := range needed {
+		charts = append(charts, ChartBlock{codepointKey(start[name]), codepointKey(end[name]), name, fmt.Sprintf("U%04X.pdf", start[name])})
+	}
+	slices.SortFunc(charts, func(a, b ChartBlock) int { return hexToInt(a.Start) - hexToInt(b.Start) })
+	return charts
+}
+
+// Returns the URL of the code chart with a code point listed in the
+// appendices A-E, or "" if its block is not known
+func ChartURL(r *Report, codepoint string) string {
+	codepointInt := hexToInt(codepoint)
+	for _, block := range r.ChartBlocks {
+		if hexToInt(block.Start) <= codepointInt && codepointInt <= hexToInt(block.End) {
+			return ChartBaseURL + block.Chart
+		}
+	}
+	return ""
+}
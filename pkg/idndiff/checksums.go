@@ -0,0 +1,51 @@
+package idndiff
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// The SHA-256 checksum of a section of a report, named as in the JSON
+// report, such as appendix_a or nfk_hazards
+type SectionChecksum struct {
+	Section string `json:"section"`
+	SHA256  string `json:"sha256"`
+}
+
+// The fields of the JSON report that say what was compared rather than what
+// was found, and so are not sections
+var metaFields = map[string]bool{"schema_version": true, "version1": true, "version2": true}
+
+// Returns a checksum per section of the report, in the order of the JSON
+// report, over the JSON encoding of the section. A consumer can compare them
+// with those of an earlier run to find the sections that changed, as after a
+// refresh of beta data, without comparing whole reports. Sections left out
+// of the JSON report, as optional ones that were not requested, have none.
+func SectionChecksums(r *Report) ([]SectionChecksum, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	var checksums []SectionChecksum
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		var section json.RawMessage
+		if err := decoder.Decode(&section); err != nil {
+			return nil, err
+		}
+		if name := token.(string); !metaFields[name] {
+			sum := sha256.Sum256(section)
+			checksums = append(checksums, SectionChecksum{name, hex.EncodeToString(sum[:])})
+		}
+	}
+	return checksums, nil
+}
@@ -0,0 +1,77 @@
+package idndiff
+
+import "fmt"
+
+// The canonical combining class of viramas, which the CONTEXTJ rules of
+// RFC 5892 for U+200C ZERO WIDTH NON-JOINER and U+200D ZERO WIDTH JOINER
+// test the code point before them for
+const viramaCombiningClass = "9"
+
+// Assigned code points whose Canonical_Combining_Class changed, if requested
+type CombiningClassChanges struct {
+	Changes []CombiningClassChange `json:"changes"`
+	// Number of changes to or from Virama (9)
+	Virama int `json:"virama"`
+}
+
+// A change of Canonical_Combining_Class, with the derived property values of
+// the code point. Virama is set for a change to or from Virama (9).
+type CombiningClassChange struct {
+	CodePoint   string `json:"code_point"`
+	Old         string `json:"old"`
+	New         string `json:"new"`
+	Virama      bool   `json:"virama"`
+	OldProperty string `json:"old_property"`
+	NewProperty string `json:"new_property"`
+	Name        string `json:"name"`
+}
+
+// Returns the Canonical_Combining_Class of a code point from
+// DerivedCombiningClass.txt. Code points that are not listed have the
+// default, 0 (Not_Reordered).
+func combiningClassOf(combiningClasses map[string]string, codepoint string) string {
+	if combiningClass, ok := combiningClasses[codepoint]; ok {
+		return combiningClass
+	}
+	return "0"
+}
+
+// Finds the code points assigned in both versions whose
+// Canonical_Combining_Class changed. A change affects normalization, and a
+// change to or from Virama also which labels the CONTEXTJ rules allow ZWNJ
+// and ZWJ in.
+func compareCombiningClasses(codepoints []int, properties1, properties2, codePointNames2 map[string]string, combiningClasses1, combiningClasses2 map[string]string) *CombiningClassChanges {
+	changes := &CombiningClassChanges{}
+	for _, codepointInt := range codepoints {
+		codepoint := codepointKey(codepointInt)
+		oldProperty, existedBefore := properties1[codepoint]
+		newProperty := properties2[codepoint]
+		if !existedBefore || oldProperty == "UNASSIGNED" || newProperty == "UNASSIGNED" {
+			continue
+		}
+		old, new := combiningClassOf(combiningClasses1, codepoint), combiningClassOf(combiningClasses2, codepoint)
+		if old == new {
+			continue
+		}
+		virama := old == viramaCombiningClass || new == viramaCombiningClass
+		if virama {
+			changes.Virama++
+		}
+		changes.Changes = append(changes.Changes, CombiningClassChange{codepoint, old, new, virama, oldProperty, newProperty, codePointNames2[codepoint]})
+	}
+	return changes
+}
+
+// Reads DerivedCombiningClass.txt of both versions and compares the
+// Canonical_Combining_Class
+func combiningClassSection(loader *Loader, version1, version2 string, codepoints []int, properties1, properties2, codePointNames2 map[string]string) (*CombiningClassChanges, error) {
+	combiningClasses1, err := loader.PropertyFile(version1, "DerivedCombiningClass.txt")
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version1, "DerivedCombiningClass.txt"), err)
+	}
+	combiningClasses2, err := loader.PropertyFile(version2, "DerivedCombiningClass.txt")
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version2, "DerivedCombiningClass.txt"), err)
+	}
+	return compareCombiningClasses(codepoints, properties1, properties2, codePointNames2, combiningClasses1, combiningClasses2), nil
+}
@@ -0,0 +1,790 @@
+// Package idndiff compares two versions of Unicode for internationalized
+// domain names: the derived property values of IDNA2008 (RFC 5892), and the
+// changes that need review before the tables of a new version are published.
+package idndiff
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Options that select optional parts of the comparison. The zero value
+// compares the appendices A-F only.
+type Options struct {
+	Exceptions    bool     // Compare Exceptions (F) with RFC 5892
+	ExcludeFile   string   // Ranges and scripts excluded from review
+	Overrides     string   // Outcomes of the review of candidates for Appendix E
+	NFKHazards    bool     // Report normalizations that now include other derived property values
+	NFKCCaseFold  bool     // Compare the NFKC_Casefold mappings
+	CaseFolding   bool     // Compare the full case folding of CaseFolding.txt
+	CasePairs     bool     // Check the derived property values of newly assigned case pairs
+	Categories    bool     // Annotate Appendix F with the categories of RFC 5892
+	Strict        bool     // Fail on violations of the Unicode stability policies
+	UTS46         bool     // Compare with the UTS #46 IDNA Mapping Table
+	Frequencies   bool     // Count the code points per derived property value
+	NameAliases   string   // Alias types from NameAliases.txt to name code points by, in order of precedence
+	Bidi          bool     // Report code points that became valid and matter for the Bidi Rule
+	CrossCheck    string   // Published review document to compare the appendices with
+	Snapshots     bool     // Include the properties of each code point in the appendices A-E
+	Detectors     string   // Additional change detectors to run
+	Homoglyphs    bool     // Score the code points that became PVALID as homoglyphs
+	Informational bool     // List the code points whose derived property value held despite related changes
+	FailFast      bool     // Fail when a requested section cannot be computed, rather than leaving it out
+	Registry      string   // File or URL of the IANA registry of derived property values to compare Appendix E with
+	Timings       *Timings // Records how long each phase took, if set
+	Only          string   // Compare only the General Category ("gc") or the NFK normalization ("nfk"), without allcodepoints.txt
+	Narrative     bool     // Write a narrative of the changes, as a starting point for the introduction of a review
+	NarrativeFile string   // Template of the narrative, instead of the default one
+	ScriptPolicy  string   // Scripts that a policy restricts, to list the code points that became PVALID in them
+	Decomposition bool     // Compare the decomposition types in UnicodeData.txt
+	DecompMapping bool     // Compare the full decomposition mappings in UnicodeData.txt
+	GroupByScript bool     // Group Appendix C by script
+	MarkRendering bool     // Annotate Appendix C with how the marks are likely rendered
+	Derive        bool     // Compute the derived property values from the UCD files instead of reading allcodepoints.txt
+	NewAssignment bool     // List all newly assigned code points with their derived property values
+	RootCause     bool     // Explain the changes in Appendix A by the rules of RFC 5892 and the UCD properties behind them
+	BidiClass     bool     // Compare the Bidi_Class of assigned code points
+	JoiningType   bool     // Compare the Joining_Type of assigned code points
+	ScriptChange  bool     // Compare the Script of assigned code points
+	CCC           bool     // Compare the Canonical_Combining_Class of assigned code points
+	GCConsistency bool     // List the code points with a General_Category but no derived property value, and the other way around
+	FillMissing   bool     // Compare the code points with a General_Category but no derived property value as UNASSIGNED
+	Age           bool     // Add the version each code point in the appendices A-E was first assigned in
+	FStyle        string   // Style of Appendix F: "compact" (the default) or "iana", as the IANA registry
+}
+
+// Reads code point properties from allcodepoints.txt. A line is either for a
+// code point, as in "0061;PVALID;Ll;LATIN SMALL LETTER A", or for a range of
+// code points with the same value, as in "0061..007A;PVALID;Ll;LATIN SMALL
+// LETTER A..LATIN SMALL LETTER Z", in which case each code point of the range
+// is named by the last field.
+func readCodepointProperties(r io.Reader, dups *duplicates) (map[string]string, map[string]string, error) {
+	properties := make(map[string]string)
+	codePointNames := make(map[string]string)
+	// The names follow the same policy, with the duplicates recorded once
+	var nameDups *duplicates
+	if dups != nil {
+		nameDups = &duplicates{policy: dups.policy}
+	}
+
+	scanner := newLineScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		fields := strings.Split(line, ";")
+		if len(fields) < 2 {
+			continue
+		}
+		codepoint, property, codePointName := fields[0], fields[1], ""
+		if len(fields) > 3 {
+			codePointName = fields[3]
+		}
+		if first, last, isRange := strings.Cut(codepoint, ".."); isRange {
+			start, ok1 := parseHex(first)
+			end, ok2 := parseHex(last)
+			if !ok1 || !ok2 || start > end {
+				return nil, nil, fmt.Errorf("line %d: invalid code point range %q", lineNumber, codepoint)
+			}
+			for i := start; i <= end; i++ {
+				if err := setEntry(properties, codepointKey(int(i)), property, dups); err != nil {
+					return nil, nil, fmt.Errorf("line %d: %w", lineNumber, err)
+				}
+				setEntry(codePointNames, codepointKey(int(i)), codePointName, nameDups)
+			}
+			continue
+		}
+		if err := setEntry(properties, codepoint, property, dups); err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		setEntry(codePointNames, codepoint, codePointName, nameDups)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return properties, codePointNames, nil
+}
+
+// hexToInt converts a hexadecimal string (like "0041") to an integer
+func hexToInt(hexStr string) int {
+	value, ok := parseHex(hexStr)
+	if !ok {
+		panic(fmt.Sprintf("Invalid hex string: %s", hexStr))
+	}
+	return value
+}
+
+// Reads the property value per code point from a UCD file with lines on the
+// form "0041..005A ; value # comment", such as DerivedGeneralCategory.txt or
+// Scripts.txt
+func readPropertyFile(r io.Reader, dups *duplicates) (map[string]string, error) {
+	categories := make(map[string]string)
+
+	scanner := newLineScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		// Comments may have semicolons too, as in "# Format: code point ; value"
+		line := strings.Split(scanner.Text(), "#")[0]
+		fields := strings.Split(line, ";")
+		if len(fields) < 2 {
+			continue
+		}
+		codepointRange, category := strings.TrimSpace(fields[0]), strings.TrimSpace(fields[1])
+		if strings.Contains(codepointRange, "..") {
+			rangeParts := strings.Split(codepointRange, "..")
+			start, ok1 := parseHex(rangeParts[0])
+			end, ok2 := parseHex(rangeParts[1])
+			if !ok1 || !ok2 {
+				continue
+			}
+			for i := start; i <= end; i++ {
+				if err := setEntry(categories, codepointKey(int(i)), category, dups); err != nil {
+					return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+				}
+			}
+		} else {
+			if err := setEntry(categories, codepointRange, category, dups); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// Compares the appendices A-F of two versions of Unicode, with the data files
+// in one subdirectory per version of dataDir, which can also be an http(s)
+// URL. Use Compare for the optional parts.
+func CompareVersions(dataDir, version1, version2 string) (*Report, error) {
+	return Compare(NewLoader(dataDir), version1, version2, Options{})
+}
+
+// Compares two versions of Unicode, reading the data files with loader. The
+// Report is the result of the comparison that all output formats, and other
+// commands such as tickets, are made from.
+func Compare(loader *Loader, version1, version2 string, opts Options) (*Report, error) {
+	if err := checkFStyle(opts.FStyle); err != nil {
+		return nil, err
+	}
+	if opts.Only != "" {
+		return compareOnly(loader, version1, version2, opts.Only)
+	}
+	report := &Report{Meta: Meta{SchemaVersion: ReportSchemaVersion, Version1: version1, Version2: version2}}
+
+	opts.Timings.start()
+
+	// Read the derived property values from allcodepoints.txt, or compute
+	// them from the UCD files if requested
+	readProperties := func(version string) (map[string]string, map[string]string, error) {
+		if !opts.Derive || version == RFC5892Baseline {
+			properties, names, err := loader.CodepointProperties(version)
+			if err != nil {
+				return nil, nil, fmt.Errorf("reading %s: %w", loader.Path(version, "allcodepoints.txt"), err)
+			}
+			return properties, names, nil
+		}
+		properties, names, err := loader.DerivedProperties(version)
+		if err != nil {
+			return nil, nil, err
+		}
+		warning, err := derivationWarning(loader, version, properties)
+		if err != nil {
+			return nil, nil, err
+		}
+		if warning != "" {
+			report.Warnings = append(report.Warnings, warning)
+		}
+		return properties, names, nil
+	}
+
+	// Read properties for the first version
+	properties1, codePointNames1, err := readProperties(version1)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.Timings.mark("parse " + version1)
+
+	// Read properties for the second version
+	properties2, codePointNames2, err := readProperties(version2)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.Timings.mark("parse " + version2)
+
+	// Check that allcodepoints.txt and DerivedGeneralCategory.txt list the
+	// same code points, if requested, and compare the code points without a
+	// derived property value as UNASSIGNED rather than leaving them out of
+	// every appendix
+	if opts.GCConsistency || opts.FillMissing {
+		consistency := &GCConsistency{}
+		for _, version := range []struct {
+			name       string
+			properties *map[string]string
+		}{{version1, &properties1}, {version2, &properties2}} {
+			generalCategory, err := loader.PropertyFile(version.name, "DerivedGeneralCategory.txt")
+			if err != nil {
+				err = fmt.Errorf("reading %s: %w", loader.Path(version.name, "DerivedGeneralCategory.txt"), err)
+				if err := report.fail("gc-consistency", err, opts.FailFast); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			var result GCConsistencyVersion
+			result, *version.properties = checkGCConsistency(version.name, *version.properties, generalCategory, opts.FillMissing)
+			if result.Synthesized {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("%d code points with a General_Category but no derived property value in version %s are compared as UNASSIGNED", result.MissingPropertyCount, version.name))
+			}
+			consistency.Versions = append(consistency.Versions, result)
+		}
+		if opts.GCConsistency {
+			report.GCConsistency = consistency
+		}
+		opts.Timings.mark("gc-consistency")
+	}
+
+	// Name code points by their aliases, if requested
+	if opts.NameAliases != "" {
+		types, err := parseAliasTypes(opts.NameAliases)
+		if err != nil {
+			return nil, err
+		}
+		aliases, err := loader.NameAliases(version2)
+		if err != nil {
+			err = fmt.Errorf("reading %s: %w", loader.Path(version2, "NameAliases.txt"), err)
+			if err := report.fail("name-aliases", err, opts.FailFast); err != nil {
+				return nil, err
+			}
+		} else {
+			codePointNames2 = applyNameAliases(codePointNames2, aliases, types)
+		}
+	}
+
+	if opts.Frequencies {
+		report.Frequencies = countProperties(properties1, properties2)
+	}
+
+	// Create a slice to hold the codepoints as integers
+	var codepoints []int
+
+	// Populate the slice with the keys (codepoints)
+	for codepoint := range properties2 {
+		codepoints = append(codepoints, hexToInt(codepoint))
+	}
+
+	// Sort the slice of codepoints
+	sort.Ints(codepoints)
+
+	// Count the code points per change of derived property value
+	changeCounts := make(map[ChangeCount]int)
+	for _, codepointInt := range codepoints {
+		codepoint := codepointKey(codepointInt) // Convert back to hex
+		oldProperty, existedBefore := properties1[codepoint]
+		newProperty := properties2[codepoint]
+		if existedBefore && oldProperty != newProperty {
+			changeCounts[ChangeCount{Old: oldProperty, New: newProperty}]++
+		}
+	}
+	report.Delta = deltaRanges(codepoints, properties1, properties2)
+	if opts.NewAssignment {
+		report.NewAssignments = findNewAssignments(report.Delta, codePointNames2)
+	}
+	report.ContextRules = findContextChanges(codepoints, properties1, properties2, codePointNames2)
+	for change, count := range changeCounts {
+		change.Count = count
+		report.ChangeCounts = append(report.ChangeCounts, change)
+	}
+	sort.Slice(report.ChangeCounts, func(i, j int) bool {
+		if report.ChangeCounts[i].Old != report.ChangeCounts[j].Old {
+			return report.ChangeCounts[i].Old < report.ChangeCounts[j].Old
+		}
+		return report.ChangeCounts[i].New < report.ChangeCounts[j].New
+	})
+
+	opts.Timings.mark("changes of derived property values")
+
+	// Read the General_Category property for the code points that changed.
+	// Without it for both versions, Appendix B and C are skipped, unless in
+	// strict mode that needs it.
+	generalCategory1, err := loader.PropertyFile(version1, "DerivedGeneralCategory.txt")
+	if err != nil {
+		if err := report.skip(err, loader.Path(version1, "DerivedGeneralCategory.txt"), opts.Strict, "B", "C"); err != nil {
+			return nil, err
+		}
+	}
+
+	generalCategory2, err := loader.PropertyFile(version2, "DerivedGeneralCategory.txt")
+	if err != nil {
+		if err := report.skip(err, loader.Path(version2, "DerivedGeneralCategory.txt"), opts.Strict, "B", "C"); err != nil {
+			return nil, err
+		}
+	}
+	if report.Skipped("B") {
+		// Nothing is found with empty tables
+		generalCategory1, generalCategory2 = nil, nil
+	}
+
+	// In strict mode, refuse to compare data that violates the stability policies
+	if opts.Strict {
+		unicodeData1, err := loader.UnicodeData(version1)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", loader.Path(version1, "UnicodeData.txt"), err)
+		}
+		unicodeData2, err := loader.UnicodeData(version2)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", loader.Path(version2, "UnicodeData.txt"), err)
+		}
+		if err := checkStability(codepoints, generalCategory1, generalCategory2, unicodeData1, unicodeData2); err != nil {
+			return nil, err
+		}
+	}
+
+	// Count the number of code points with General_Category Mn in the first version
+	for codepoint, property := range properties1 {
+		if property != "UNASSIGNED" && generalCategory1[codepoint] == "Mn" {
+			report.MnCount1++
+		}
+	}
+
+	// Count the number of code points with General_Category Mn in the second version
+	for codepoint, property := range properties2 {
+		if property != "UNASSIGNED" && generalCategory2[codepoint] == "Mn" {
+			report.MnCount2++
+		}
+	}
+
+	opts.Timings.mark("General Category")
+
+	// Read NFK data for both versions. Without it, Appendix D is skipped,
+	// unless the NFK hazards that need it are requested.
+	nfk1, err := loader.NFKData(version1)
+	if err != nil {
+		if err := report.skip(err, loader.Path(version1, "nfk.txt"), opts.NFKHazards, "D"); err != nil {
+			return nil, err
+		}
+	}
+
+	nfk2, err := loader.NFKData(version2)
+	if err != nil {
+		if err := report.skip(err, loader.Path(version2, "nfk.txt"), opts.NFKHazards, "D"); err != nil {
+			return nil, err
+		}
+	}
+	if report.Skipped("D") {
+		// Without mappings every code point maps to itself
+		nfk1, nfk2 = nil, nil
+	}
+
+	// Check if the NFK normalization changed for any assigned code point
+	for _, codepointInt := range codepoints {
+		codepoint := codepointKey(codepointInt) // Convert back to hex
+		oldProperty, existedBefore := properties1[codepoint]
+		newProperty := properties2[codepoint]
+		if !existedBefore || oldProperty == "UNASSIGNED" {
+			continue
+		}
+		oldNFK := nfk1.mapping(codepointInt)
+		newNFK := nfk2.mapping(codepointInt)
+		if !slices.Equal(oldNFK, newNFK) {
+			report.NFKChanges = append(report.NFKChanges, NFKChange{codepoint, formatNFK(oldNFK), formatNFK(newNFK), oldProperty, newProperty})
+		}
+	}
+
+	opts.Timings.mark("NFK")
+
+	// The appendices A-D, and the code points in A, C and D as candidates
+	// for Appendix E
+	data1 := &VersionData{version1, codepoints, properties1, codePointNames1, generalCategory1, nfk1, nil}
+	data2 := &VersionData{version2, codepoints, properties2, codePointNames2, generalCategory2, nfk2, nil}
+	for _, finding := range (propertyChanges{}).Detect(data1, data2) {
+		report.AppendixA = append(report.AppendixA, PropertyChange{finding.CodePoint, finding.Old, finding.New, finding.Name})
+		report.AppendixE = append(report.AppendixE, ExceptionCandidate{CodePoint: finding.CodePoint, Name: finding.Name, Property: finding.NewProperty, Source: "A"})
+	}
+	opts.Timings.mark("Appendix A")
+	for _, finding := range (categoryChanges{}).Detect(data1, data2) {
+		report.AppendixB = append(report.AppendixB, GCChange{finding.CodePoint, finding.Old, finding.New, finding.OldProperty, finding.NewProperty, finding.Name})
+	}
+	opts.Timings.mark("Appendix B")
+	for _, finding := range (newCombiningMarks{}).Detect(data1, data2) {
+		report.AppendixC = append(report.AppendixC, CodePoint{finding.CodePoint, finding.Name})
+		report.AppendixE = append(report.AppendixE, ExceptionCandidate{CodePoint: finding.CodePoint, Name: finding.Name, Property: finding.NewProperty, Source: "C"})
+	}
+	opts.Timings.mark("Appendix C")
+	for _, finding := range (newNFKNormalizations{}).Detect(data1, data2) {
+		report.AppendixD = append(report.AppendixD, NFKEntry{finding.CodePoint, finding.New, finding.Name})
+		report.AppendixE = append(report.AppendixE, ExceptionCandidate{CodePoint: finding.CodePoint, Name: finding.Name, Property: finding.NewProperty, Source: "D"})
+	}
+
+	opts.Timings.mark("Appendix D")
+
+	// Newly PVALID symbols or emoji indicate an error in the derivation, so
+	// they are always looked for, and fail the comparison in strict mode
+	data2.Emoji, err = loader.BinaryProperty(version2, "emoji-data.txt", "Emoji")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		// Symbols are still found by their General Category
+		err = fmt.Errorf("reading %s: %w", loader.Path(version2, "emoji-data.txt"), err)
+		if err := report.fail(newSymbols{}.Name(), err, opts.FailFast); err != nil {
+			return nil, err
+		}
+	}
+	if findings := (newSymbols{}).Detect(data1, data2); len(findings) > 0 {
+		if opts.Strict {
+			return nil, fmt.Errorf("%d code points that became PVALID are symbols or emoji, starting with U+%s %s", len(findings), findings[0].CodePoint, findings[0].Name)
+		}
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%d code points that became PVALID are symbols or emoji, which indicates an error in the data or the derivation", len(findings)))
+		report.Findings = append(report.Findings, DetectorFindings{newSymbols{}.Name(), findings})
+	}
+
+	// Hangul handling has been a recurring source of errors, so the Hangul
+	// code points of both versions are always checked
+	for _, version := range []struct {
+		name       string
+		properties map[string]string
+	}{{version1, properties1}, {version2, properties2}} {
+		warning, err := hangulWarning(loader, version.name, version.properties, opts.Strict)
+		if err != nil {
+			return nil, err
+		}
+		if warning != "" {
+			report.Warnings = append(report.Warnings, warning)
+		}
+	}
+
+	// The CONTEXTJ rules assume that Join_Control is U+200C and U+200D, so
+	// any change to it is always looked for, and fails the comparison in
+	// strict mode
+	report.JoinControl, err = checkJoinControl(loader, version1, version2, codePointNames1, codePointNames2)
+	if err := report.fail("join-control", err, opts.FailFast); err != nil {
+		return nil, err
+	}
+	if report.JoinControl != nil && opts.Strict {
+		return nil, fmt.Errorf("Join_Control is not only U+200C and U+200D, which the CONTEXTJ rules of RFC 5892 assume")
+	}
+
+	opts.Timings.mark("symbols, Hangul and Join_Control")
+
+	// Additional detectors, if requested
+	if opts.Detectors != "" {
+		selected, err := parseDetectors(opts.Detectors)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range selected {
+			report.Findings = append(report.Findings, DetectorFindings{d.Name(), d.Detect(data1, data2)})
+		}
+		opts.Timings.mark("detectors")
+	}
+
+	if opts.NFKHazards {
+		report.NFKHazards = &NFKHazards{findNFKHazards(codepoints, properties1, properties2, codePointNames2, nfk1, nfk2)}
+		opts.Timings.mark("nfk-hazards")
+	}
+
+	if opts.NFKCCaseFold {
+		report.NFKCCaseFold, err = nfkcCaseFoldSection(loader, version1, version2, codepoints, properties1, properties2)
+		if err := report.fail("nfkc-casefold", err, opts.FailFast); err != nil {
+			return nil, err
+		}
+		opts.Timings.mark("nfkc-casefold")
+	}
+
+	if opts.CaseFolding {
+		report.CaseFolding, err = caseFoldingSection(loader, version1, version2, codepoints, properties1, properties2)
+		if err := report.fail("case-folding", err, opts.FailFast); err != nil {
+			return nil, err
+		}
+		opts.Timings.mark("case-folding")
+	}
+
+	if opts.Decomposition {
+		report.DecompositionTypes, err = decompositionTypeSection(loader, version1, version2, codepoints, properties1, properties2, codePointNames2)
+		if err := report.fail("decomposition-types", err, opts.FailFast); err != nil {
+			return nil, err
+		}
+		opts.Timings.mark("decomposition-types")
+	}
+
+	if opts.DecompMapping {
+		report.DecompositionMappings, err = decompositionMappingSection(loader, version1, version2, codepoints, properties1, properties2, codePointNames2)
+		if err := report.fail("decomposition-mappings", err, opts.FailFast); err != nil {
+			return nil, err
+		}
+		opts.Timings.mark("decomposition-mappings")
+	}
+
+	if opts.BidiClass {
+		report.BidiClasses, err = bidiClassSection(loader, version1, version2, codepoints, properties1, properties2, codePointNames2)
+		if err := report.fail("bidi-class", err, opts.FailFast); err != nil {
+			return nil, err
+		}
+		opts.Timings.mark("bidi-class")
+	}
+
+	if opts.JoiningType {
+		report.JoiningTypes, err = joiningTypeSection(loader, version1, version2, codepoints, properties1, properties2, codePointNames2)
+		if err := report.fail("joining-type", err, opts.FailFast); err != nil {
+			return nil, err
+		}
+		opts.Timings.mark("joining-type")
+	}
+
+	if opts.ScriptChange {
+		report.ScriptChanges, err = scriptChangeSection(loader, version1, version2, codepoints, properties1, properties2, codePointNames2)
+		if err := report.fail("script-changes", err, opts.FailFast); err != nil {
+			return nil, err
+		}
+		opts.Timings.mark("script-changes")
+	}
+
+	if opts.CCC {
+		report.CombiningClasses, err = combiningClassSection(loader, version1, version2, codepoints, properties1, properties2, codePointNames2)
+		if err := report.fail("combining-class", err, opts.FailFast); err != nil {
+			return nil, err
+		}
+		opts.Timings.mark("combining-class")
+	}
+
+	if opts.GroupByScript && len(report.AppendixC) > 0 {
+		report.AppendixCScripts, err = scriptGroupSection(loader, version2, report.AppendixC)
+		if err := report.fail("group-by-script", err, opts.FailFast); err != nil {
+			return nil, err
+		}
+		opts.Timings.mark("group-by-script")
+	}
+
+	if opts.MarkRendering {
+		report.MarkRendering, err = markRenderingSection(loader, version2, report.AppendixC)
+		if err := report.fail("mark-rendering", err, opts.FailFast); err != nil {
+			return nil, err
+		}
+		opts.Timings.mark("mark-rendering")
+	}
+
+	if opts.Informational {
+		report.Informational = findUnchangedNotable(report, codePointNames2, loader.tables())
+		opts.Timings.mark("informational")
+	}
+
+	if opts.RootCause {
+		report.RootCauses, err = rootCauseSection(loader, version1, version2, report.AppendixA)
+		if err := report.fail("root-cause", err, opts.FailFast); err != nil {
+			return nil, err
+		}
+		opts.Timings.mark("root-cause")
+	}
+
+	if opts.CasePairs {
+		unicodeData2, err := loader.UnicodeData(version2)
+		if err != nil {
+			err = fmt.Errorf("reading %s: %w", loader.Path(version2, "UnicodeData.txt"), err)
+			if err := report.fail("case-pairs", err, opts.FailFast); err != nil {
+				return nil, err
+			}
+		} else {
+			checked, anomalies := findCaseAnomalies(codepoints, properties1, properties2, unicodeData2)
+			report.CaseConsistency = &CaseConsistency{checked, anomalies}
+		}
+		opts.Timings.mark("case-pairs")
+	}
+
+	if opts.Bidi {
+		report.BidiImpact, err = bidiSection(loader, version2, codepoints, properties1, properties2)
+		if err := report.fail("bidi", err, opts.FailFast); err != nil {
+			return nil, err
+		}
+		opts.Timings.mark("bidi")
+	}
+
+	if opts.UTS46 {
+		table, err := loader.IdnaMappingTable(version2)
+		if err != nil {
+			err = fmt.Errorf("reading %s: %w", loader.Path(version2, "IdnaMappingTable.txt"), err)
+			if err := report.fail("uts46", err, opts.FailFast); err != nil {
+				return nil, err
+			}
+		} else {
+			report.UTS46 = compareUTS46(codepoints, properties2, codePointNames2, table)
+		}
+		opts.Timings.mark("uts46")
+	}
+
+	if opts.Snapshots {
+		data, err := loader.snapshotData(version2, nfk2)
+		if err != nil {
+			if err := report.fail("snapshots", err, opts.FailFast); err != nil {
+				return nil, err
+			}
+		} else {
+			report.Snapshots = propertySnapshots(report, codePointNames2, data)
+		}
+		opts.Timings.mark("snapshots")
+	}
+
+	if opts.Homoglyphs {
+		report.Homoglyphs, err = homoglyphSection(loader, version2, codepoints, properties1, properties2, codePointNames2)
+		if err := report.fail("homoglyphs", err, opts.FailFast); err != nil {
+			return nil, err
+		}
+		opts.Timings.mark("homoglyphs")
+	}
+
+	if opts.ScriptPolicy != "" {
+		report.RestrictedScripts, err = restrictedScriptSection(loader, version2, opts.ScriptPolicy, codepoints, properties1, properties2, codePointNames2)
+		if err := report.fail("restricted-scripts", err, opts.FailFast); err != nil {
+			return nil, err
+		}
+		opts.Timings.mark("restricted-scripts")
+	}
+
+	// Sort the appendix by code point
+	sort.SliceStable(report.AppendixE, func(i, j int) bool {
+		return hexToInt(report.AppendixE[i].CodePoint) < hexToInt(report.AppendixE[j].CodePoint)
+	})
+
+	// Move the candidates that were already reviewed out of Appendix E
+	if opts.Overrides != "" {
+		overrides, err := readOverrides(opts.Overrides)
+		if err != nil {
+			// Without the outcomes, all candidates stay in Appendix E
+			// The errors of readOverrides name the file
+			err = fmt.Errorf("reading the overrides: %w", err)
+			if err := report.fail("overrides", err, opts.FailFast); err != nil {
+				return nil, err
+			}
+		}
+		if unused := applyOverrides(report, overrides); len(unused) > 0 {
+			var stale []string
+			for _, o := range unused {
+				stale = append(stale, o.String())
+			}
+			report.Warnings = append(report.Warnings, fmt.Sprintf("%s has outcomes for code points that are not candidates for Appendix E: %s", opts.Overrides, strings.Join(stale, ", ")))
+		}
+	}
+
+	// Read the ranges and scripts that are excluded from review, if any
+	var exclusions []exclusion
+	var scripts2 *codePointScripts
+	if opts.ExcludeFile != "" {
+		exclusions, scripts2, err = exclusionSection(loader, version2, opts.ExcludeFile)
+		if err := report.fail("exclude", err, opts.FailFast); err != nil {
+			return nil, err
+		}
+	}
+
+	// The derived property values are changed to UNDER REVIEW for Appendix F,
+	// so work on a copy rather than on the loader's cached table
+	properties2 = maps.Clone(properties2)
+	for i, entry := range report.AppendixE {
+		// Code points excluded from review are listed, but keep their derived property value
+		if reason, excluded := excludedFromReview(hexToInt(entry.CodePoint), exclusions, scripts2); excluded {
+			report.AppendixE[i].Excluded = true
+			report.AppendixE[i].ExclusionReason = reason
+			continue
+		}
+		properties2[entry.CodePoint] = "UNDER REVIEW"
+	}
+	for _, entry := range report.Resolved {
+		properties2[entry.CodePoint] = entry.Outcome
+	}
+
+	opts.Timings.mark("Appendix E")
+
+	// Read what the RFC 5892 categories are computed from, if Appendix F is
+	// to be annotated with them
+	var derivation2 *derivationData
+	if opts.Categories {
+		derivation2, err = loader.DerivationData(version2)
+		if err := report.fail("categories", err, opts.FailFast); err != nil {
+			return nil, err
+		}
+	}
+
+	// Collect the derived property values in the ranges of Appendix F. The
+	// ranges of the IANA registry are never split by categories.
+	if opts.FStyle == "iana" {
+		report.AppendixF, report.AppendixFGaps = compressRanges(codepoints, properties2, nil)
+		describeRanges(report.AppendixF, properties2, codePointNames2)
+		report.AppendixFStyle = opts.FStyle
+	} else {
+		report.AppendixF, report.AppendixFGaps = compressRanges(codepoints, properties2, derivation2)
+	}
+
+	opts.Timings.mark("Appendix F")
+
+	// Blocks.txt is optional, and only gives the code charts to link to
+	blocks2, err := loader.PropertyFile(version2, "Blocks.txt")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version2, "Blocks.txt"), err)
+	}
+	report.ChartBlocks = chartBlocks(report, blocks2)
+	opts.Timings.mark("charts")
+
+	if opts.CrossCheck != "" {
+		published, err := readPublishedAppendices(opts.CrossCheck)
+		if err != nil {
+			err = fmt.Errorf("reading %s: %w", opts.CrossCheck, err)
+			if err := report.fail("cross-check", err, opts.FailFast); err != nil {
+				return nil, err
+			}
+		} else {
+			report.CrossCheck = crossCheck(report, published)
+			report.CrossCheck.Document = opts.CrossCheck
+		}
+		opts.Timings.mark("cross-check")
+	}
+
+	if opts.Registry != "" {
+		registry, err := readRegistry(opts.Registry)
+		if err != nil {
+			err = fmt.Errorf("reading %s: %w", opts.Registry, err)
+			if err := report.fail("iana-registry", err, opts.FailFast); err != nil {
+				return nil, err
+			}
+		} else {
+			compareRegistry(report.AppendixE, registry)
+			report.Registry = opts.Registry
+		}
+		opts.Timings.mark("iana-registry")
+	}
+
+	if opts.Exceptions {
+		report.Exceptions = compareExceptions(loader.tables().Exceptions, properties2, codePointNames2, report.AppendixE)
+		opts.Timings.mark("exceptions")
+	}
+
+	// The ages are those of the code points that the appendices A-E list
+	if opts.Age {
+		report.Ages, err = ageSection(loader, version2, report)
+		if err := report.fail("age", err, opts.FailFast); err != nil {
+			return nil, err
+		}
+		opts.Timings.mark("age")
+	}
+
+	// The narrative is made from the rest of the report
+	if opts.Narrative || opts.NarrativeFile != "" {
+		report.Narrative, err = writeNarrative(loader, report, opts.NarrativeFile)
+		if err := report.fail("narrative", err, opts.FailFast); err != nil {
+			return nil, err
+		}
+		opts.Timings.mark("narrative")
+	}
+
+	report.Warnings = append(report.Warnings, loader.DuplicateWarnings(version1, version2)...)
+
+	return report, nil
+}
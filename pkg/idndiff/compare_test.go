@@ -0,0 +1,535 @@
+package idndiff
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the expected outputs in testdata")
+
+// Known transitions between versions of Unicode, with trimmed data in
+// testdata/transitions, and how a code point is expected to be classified
+var transitions = []struct {
+	version1, version2 string
+	codepoint          string
+	appendices         string // Letters of the appendices (A-E) listing the code point
+	property           string // Value in Appendix F
+}{
+	// General Category changed from Nd to No, so PVALID became DISALLOWED (RFC 6452)
+	{"5.2.0", "6.0.0", "19DA", "ABE", "UNDER REVIEW"},
+	// New letter that looks like a composed character but has no decomposition
+	{"6.3.0", "7.0.0", "08A1", "", "PVALID"},
+	// Existing combining mark, not to be listed again
+	{"8.0.0", "9.0.0", "A8C4", "", "PVALID"},
+	// New combining mark
+	{"8.0.0", "9.0.0", "A8C5", "CE", "UNDER REVIEW"},
+	// General Category changed from Po to So, DISALLOWED in both versions
+	{"11.0.0", "12.0.0", "166D", "B", "DISALLOWED"},
+	// New combining mark
+	{"12.1.0", "13.0.0", "0B55", "CE", "UNDER REVIEW"},
+}
+
+// Returns the letters of the appendices A-E that list a code point
+func listedIn(r *Report, codepoint string) string {
+	var letters strings.Builder
+	for _, change := range r.AppendixA {
+		if change.CodePoint == codepoint {
+			letters.WriteString("A")
+			break
+		}
+	}
+	for _, change := range r.AppendixB {
+		if change.CodePoint == codepoint {
+			letters.WriteString("B")
+			break
+		}
+	}
+	for _, entry := range r.AppendixC {
+		if entry.CodePoint == codepoint {
+			letters.WriteString("C")
+			break
+		}
+	}
+	for _, entry := range r.AppendixD {
+		if entry.CodePoint == codepoint {
+			letters.WriteString("D")
+			break
+		}
+	}
+	for _, entry := range r.AppendixE {
+		if entry.CodePoint == codepoint {
+			letters.WriteString("E")
+			break
+		}
+	}
+	return letters.String()
+}
+
+// Returns the value of a code point in Appendix F
+func propertyInAppendixF(r *Report, codepoint string) string {
+	codepointInt := hexToInt(codepoint)
+	for _, entry := range r.AppendixF {
+		if hexToInt(entry.Start) <= codepointInt && codepointInt <= hexToInt(entry.End) {
+			return entry.Property
+		}
+	}
+	return ""
+}
+
+func TestTransitions(t *testing.T) {
+	loader := NewLoader(filepath.Join("testdata", "transitions"))
+	for _, tc := range transitions {
+		report, err := Compare(loader, tc.version1, tc.version2, Options{})
+		if err != nil {
+			t.Fatalf("%s to %s: %s", tc.version1, tc.version2, err)
+		}
+		if got := listedIn(report, tc.codepoint); got != tc.appendices {
+			t.Errorf("%s to %s: U+%s listed in appendices %q, want %q", tc.version1, tc.version2, tc.codepoint, got, tc.appendices)
+		}
+		if got := propertyInAppendixF(report, tc.codepoint); got != tc.property {
+			t.Errorf("%s to %s: U+%s is %s in Appendix F, want %s", tc.version1, tc.version2, tc.codepoint, got, tc.property)
+		}
+	}
+}
+
+// Compares the text report of each transition with the expected output in
+// testdata/transitions. Run with -update to write the expected outputs.
+func TestTransitionReports(t *testing.T) {
+	loader := NewLoader(filepath.Join("testdata", "transitions"))
+	done := make(map[string]bool)
+	for _, tc := range transitions {
+		name := tc.version1 + "-" + tc.version2
+		if done[name] {
+			continue
+		}
+		done[name] = true
+
+		report, err := Compare(loader, tc.version1, tc.version2, Options{})
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		var got strings.Builder
+		if err := RenderText(&got, report, RenderOptions{}); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+
+		expectedPath := filepath.Join("testdata", "transitions", name+".txt")
+		if *update {
+			if err := os.WriteFile(expectedPath, []byte(got.String()), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		expected, err := os.ReadFile(expectedPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != string(expected) {
+			t.Errorf("%s: report differs from %s:\n%s", name, expectedPath, got.String())
+		}
+	}
+}
+
+// Comparisons through one Loader and renderings with different options can
+// run at the same time, as in the serve command, without affecting each other
+func TestConcurrentRender(t *testing.T) {
+	loader := NewLoader(filepath.Join("testdata", "transitions"))
+	tc := transitions[0]
+	optionSets := []RenderOptions{{}, {ShowGlyphs: true}, {LineWidth: 40}, {MaxEntries: 1}}
+	render := func(opts RenderOptions) (string, error) {
+		report, err := Compare(loader, tc.version1, tc.version2, Options{})
+		if err != nil {
+			return "", err
+		}
+		var buffer strings.Builder
+		err = RenderText(&buffer, report, opts)
+		return buffer.String(), err
+	}
+	var expected []string
+	for _, opts := range optionSets {
+		text, err := render(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected = append(expected, text)
+	}
+
+	got := make([]string, 4*len(optionSets))
+	errs := make([]error, len(got))
+	var wg sync.WaitGroup
+	for i := range got {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got[i], errs[i] = render(optionSets[i%len(optionSets)])
+		}()
+	}
+	wg.Wait()
+	for i := range got {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		if got[i] != expected[i%len(optionSets)] {
+			t.Errorf("%+v: concurrent rendering differs:\n%s", optionSets[i%len(optionSets)], got[i])
+		}
+	}
+}
+
+// A requested section that cannot be computed is left out of the report,
+// unless FailFast is set
+func TestSectionErrors(t *testing.T) {
+	loader := NewLoader(filepath.Join("testdata", "transitions"))
+	tc := transitions[0]
+	opts := Options{UTS46: true, Overrides: filepath.Join("testdata", "missing.txt")}
+	report, err := Compare(loader, tc.version1, tc.version2, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sections []string
+	for _, e := range report.Errors {
+		sections = append(sections, e.Section)
+	}
+	if got := strings.Join(sections, ","); got != "uts46,overrides" {
+		t.Errorf("skipped sections %q, want \"uts46,overrides\"", got)
+	}
+	if report.UTS46 != nil {
+		t.Errorf("UTS #46 comparison computed without IdnaMappingTable.txt")
+	}
+
+	opts.FailFast = true
+	if _, err := Compare(loader, tc.version1, tc.version2, opts); err == nil {
+		t.Errorf("no error with FailFast")
+	}
+}
+
+// Comparing only the General Category lists the same code points in the
+// appendices B and C as the full comparison
+func TestOnlyGeneralCategory(t *testing.T) {
+	loader := NewLoader(filepath.Join("testdata", "transitions"))
+	keepBC := func(letters string) string {
+		return strings.Map(func(r rune) rune {
+			if r == 'B' || r == 'C' {
+				return r
+			}
+			return -1
+		}, letters)
+	}
+	for _, tc := range transitions {
+		report, err := Compare(loader, tc.version1, tc.version2, Options{Only: "gc"})
+		if err != nil {
+			t.Fatalf("%s to %s: %s", tc.version1, tc.version2, err)
+		}
+		if got, want := listedIn(report, tc.codepoint), keepBC(tc.appendices); got != want {
+			t.Errorf("%s to %s: U+%s listed in appendices %q, want %q", tc.version1, tc.version2, tc.codepoint, got, want)
+		}
+		if !report.Skipped("A") || !report.Skipped("F") {
+			t.Errorf("%s to %s: appendices A and F not skipped", tc.version1, tc.version2)
+		}
+	}
+}
+
+func TestTruncateEntries(t *testing.T) {
+	text := "\nAppendix C: New code points where General Category is Mn\n\n# Code point; Name\nU+0898; A\nU+0899; B\nU+089A; C\n# Total 3\n"
+	for _, test := range []struct {
+		opts      RenderOptions
+		want      string
+		truncated bool
+	}{
+		{RenderOptions{}, text, false},
+		{RenderOptions{MaxEntries: 3}, text, false},
+		{RenderOptions{MaxEntries: 2}, "\nAppendix C: New code points where General Category is Mn\n\n# Code point; Name\nU+0898; A\nU+0899; B\n# 1 more, all 3 entries are in appendix-C.txt\n# Total 3\n", true},
+		{RenderOptions{MaxEntries: 1, OverflowFile: func(letter string) string { return letter + "-full.txt" }},
+			"\nAppendix C: New code points where General Category is Mn\n\n# Code point; Name\nU+0898; A\n# 2 more, all 3 entries are in C-full.txt\n# Total 3\n", true},
+	} {
+		got, truncated := truncateEntries(text, "C", test.opts)
+		if got != test.want || truncated != test.truncated {
+			t.Errorf("with at most %d entries, truncated (%t) to %q, want %q", test.opts.MaxEntries, truncated, got, test.want)
+		}
+	}
+}
+
+// Lines longer than the width are folded at spaces, with continuation lines
+// starting with white space, in the text and xml2rfc reports
+func TestFoldLines(t *testing.T) {
+	for _, test := range []struct {
+		text  string
+		width int
+		want  string
+	}{
+		{"U+0B55; DISALLOWED; PVALID; ORIYA SIGN OVERLINE\n", 0, "U+0B55; DISALLOWED; PVALID; ORIYA SIGN OVERLINE\n"},
+		{"U+0B55; DISALLOWED; PVALID; ORIYA SIGN OVERLINE\n", 48, "U+0B55; DISALLOWED; PVALID; ORIYA SIGN OVERLINE\n"},
+		{"U+0B55; DISALLOWED; PVALID; ORIYA SIGN OVERLINE\n", 30, "U+0B55; DISALLOWED; PVALID;\n   ORIYA SIGN OVERLINE\n"},
+		{"U+0B55; DISALLOWED; PVALID; ORIYA SIGN OVERLINE\n", 20, "U+0B55; DISALLOWED;\n   PVALID; ORIYA\n   SIGN OVERLINE\n"},
+		{"0000-002C,DISALLOWED,NULL..COMMA\n", 10, "0000-002C,DISALLOWED,NULL..COMMA\n"},
+	} {
+		if got := foldLines(test.text, test.width); got != test.want {
+			t.Errorf("folded at %d to %q, want %q", test.width, got, test.want)
+		}
+	}
+
+	report := Report{AppendixA: []PropertyChange{{"0B55", "DISALLOWED", "PVALID", "ORIYA SIGN OVERLINE"}}}
+	for name, format := range Formats {
+		if name != "text" && name != "xml2rfc" {
+			continue
+		}
+		var buffer strings.Builder
+		if err := format.Render(&buffer, &report, RenderOptions{LineWidth: 30}); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buffer.String(), "U+0B55; DISALLOWED; PVALID;\n   ORIYA SIGN OVERLINE\n") {
+			t.Errorf("Appendix A of the %s report not folded at 30", name)
+		}
+	}
+}
+
+// With ShowGlyphs, the character is shown after the code point, unless it has
+// no glyph to show
+func TestCodePointLabel(t *testing.T) {
+	for _, test := range []struct {
+		codepoint string
+		glyphs    bool
+		want      string
+	}{
+		{"0061", false, "U+0061"},
+		{"0061", true, "U+0061 a"},
+		{"0300", true, "U+0300 \u25CC\u0300"},       // On a dotted circle
+		{"05D0", true, "U+05D0 \u2068\u05D0\u2069"}, // Isolated
+		{"0020", true, "U+0020"},
+		{"200C", true, "U+200C"},
+		{"0378", true, "U+0378"},
+	} {
+		if got := (RenderOptions{ShowGlyphs: test.glyphs}).codePointLabel(test.codepoint); got != test.want {
+			t.Errorf("U+%s with glyphs %t is %q, want %q", test.codepoint, test.glyphs, got, test.want)
+		}
+	}
+
+	report := Report{
+		AppendixC:  []CodePoint{{"0061", "LATIN SMALL LETTER A"}},
+		Exceptions: &ExceptionsComparison{Additions: []ExceptionValue{{"0062", "PVALID", "LATIN SMALL LETTER B"}}},
+	}
+	for name, want := range map[string][]string{
+		"text":     {"U+0061 a; LATIN SMALL LETTER A", "U+0062 b; PVALID"},
+		"markdown": {"| U+0061 a | LATIN SMALL LETTER A |"},
+		"html":     {"<summary>U&#43;0061 <bdi>a</bdi></summary>"},
+	} {
+		var buffer strings.Builder
+		if err := Formats[name].Render(&buffer, &report, RenderOptions{ShowGlyphs: true}); err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range want {
+			if !strings.Contains(buffer.String(), line) {
+				t.Errorf("no %q in the %s report", line, name)
+			}
+		}
+	}
+}
+
+// Text with characters whose Bidi_Class is right-to-left, or that control the
+// direction of text, is isolated in every format, and HTML isolates all names
+// and characters
+func TestIsolate(t *testing.T) {
+	for _, test := range []struct {
+		text     string
+		isolated bool
+	}{
+		{"LATIN SMALL LETTER A", false},
+		{"\u00E9", false},
+		{"\u05D0", true},     // Hebrew, R
+		{"\u0627", true},     // Arabic, AL
+		{"\U0001E900", true}, // Adlam, R
+		{"\U00010C80", true}, // Old Hungarian, R
+		{"\u0660", false},    // ARABIC-INDIC DIGIT ZERO, AN
+		{"\u200F", true},     // RIGHT-TO-LEFT MARK, R
+		{"\u202A", true},     // LEFT-TO-RIGHT EMBEDDING, a control
+		{"ALEF \u05D0 ALEF", true},
+	} {
+		want := test.text
+		if test.isolated {
+			want = "\u2068" + test.text + "\u2069"
+		}
+		if got := isolate(test.text); got != want {
+			t.Errorf("%q is isolated as %q, want %q", test.text, got, want)
+		}
+	}
+
+	report := Report{AppendixC: []CodePoint{{"05D0", "\u05D0\u05DC\u05E3"}}}
+	for name, want := range map[string]string{
+		"text":     "U+05D0 \u2068\u05D0\u2069; \u2068\u05D0\u05DC\u05E3\u2069\n",
+		"markdown": "| \u2068\u05D0\u05DC\u05E3\u2069 |",
+		"xml2rfc":  "U+05D0 \u2068\u05D0\u2069; \u2068\u05D0\u05DC\u05E3\u2069\n",
+		"html":     "<summary>U&#43;05D0 <bdi>\u05D0</bdi></summary><dl><dt>Name</dt><dd><bdi>\u05D0\u05DC\u05E3</bdi></dd>",
+	} {
+		var buffer strings.Builder
+		if err := Formats[name].Render(&buffer, &report, RenderOptions{ShowGlyphs: true}); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buffer.String(), want) {
+			t.Errorf("no %q in the %s report", want, name)
+		}
+	}
+}
+
+// Code points listed in only one of the files are found in ranges, and those
+// without a derived property value are added as UNASSIGNED to a copy only
+func TestCheckGCConsistency(t *testing.T) {
+	properties := map[string]string{"0041": "DISALLOWED", "0061": "PVALID", "0062": "PVALID"}
+	generalCategory := map[string]string{"0041": "Lu", "0042": "Lu", "0043": "Lu", "0061": "Ll"}
+	result, filled := checkGCConsistency("14.0.0", properties, generalCategory, true)
+	if result.MissingPropertyCount != 2 || len(result.MissingProperty) != 1 || result.MissingProperty[0] != (CodePointRange{"0042", "0043"}) {
+		t.Errorf("missing properties %v (%d), want 0042..0043", result.MissingProperty, result.MissingPropertyCount)
+	}
+	if result.MissingCategoryCount != 1 || result.MissingCategory[0] != (CodePointRange{"0062", "0062"}) {
+		t.Errorf("missing categories %v (%d), want 0062", result.MissingCategory, result.MissingCategoryCount)
+	}
+	if !result.Synthesized || filled["0042"] != "UNASSIGNED" || filled["0043"] != "UNASSIGNED" {
+		t.Errorf("0042 and 0043 not filled as UNASSIGNED")
+	}
+	if _, ok := properties["0042"]; ok {
+		t.Errorf("the shared properties were modified")
+	}
+}
+
+// With every optional section, the appendices run past Z and go on with AA
+func TestAppendixLetters(t *testing.T) {
+	var report Report
+	fill(reflect.ValueOf(&report).Elem())
+	appendices := reportAppendices(&report)
+	if len(appendices) <= 26 {
+		t.Fatalf("%d appendices with every section, want more than 26", len(appendices))
+	}
+	valid := regexp.MustCompile(`^[A-Z]{1,2}$`)
+	seen := make(map[string]bool)
+	for i, appendix := range appendices {
+		if !valid.MatchString(appendix.letter) || seen[appendix.letter] {
+			t.Errorf("appendix %d has letter %q", i, appendix.letter)
+		}
+		seen[appendix.letter] = true
+	}
+	if appendices[26].letter != "AA" || appendices[27].letter != "AB" {
+		t.Errorf("appendices after Z are %s and %s, want AA and AB", appendices[26].letter, appendices[27].letter)
+	}
+	var text strings.Builder
+	if err := RenderText(&text, &report, RenderOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text.String(), "\nAppendix AA: ") {
+		t.Errorf("no Appendix AA in the text report")
+	}
+}
+
+// With the ages, the tables of the appendices A-E have a column for them
+// after the code point in every format
+func TestAgeColumn(t *testing.T) {
+	report := Report{
+		AppendixA: []PropertyChange{{"0B55", "DISALLOWED", "PVALID", "ORIYA SIGN OVERLINE"}},
+		Ages:      []CodePointAge{{"0B55", "13.0"}},
+	}
+	var text, markdown, html strings.Builder
+	if err := RenderText(&text, &report, RenderOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text.String(), "# Code point; Age; Old; New; Name\nU+0B55; 13.0; DISALLOWED; PVALID; ORIYA SIGN OVERLINE\n") {
+		t.Errorf("no age in Appendix A of the text report")
+	}
+	if err := RenderMarkdown(&markdown, &report, RenderOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(markdown.String(), "| Code point | Age | Old | New | Name |") || !strings.Contains(markdown.String(), "| U+0B55 | 13.0 | DISALLOWED |") {
+		t.Errorf("no age in Appendix A of the Markdown report")
+	}
+	if err := RenderHTML(&html, &report, RenderOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html.String(), "<th>Age</th>") {
+		t.Errorf("no age column in Appendix A of the HTML report")
+	}
+	tables := AppendixTables(&report)
+	if got := tables[0].Rows[0]; got[0] != "U+0B55" || got[1] != "13.0" {
+		t.Errorf("Appendix A table row %v, want the age after the code point", got)
+	}
+}
+
+// A comparison of the whole code space from the files, as a run of the
+// program does it: half of it assigned, with a change of the derived property
+// value and of the General Category every few hundred code points
+func BenchmarkCompare(b *testing.B) {
+	dir := b.TempDir()
+	for _, version := range []string{"15.0.0", "16.0.0"} {
+		var properties, categories, nfk strings.Builder
+		for codepoint := range codeSpaceSize {
+			property, category := "UNASSIGNED", "Cn"
+			if codepoint%2 == 0 {
+				property, category = "PVALID", "Ll"
+			}
+			if version == "16.0.0" && codepoint%301 == 0 {
+				property, category = "DISALLOWED", "So"
+			}
+			fmt.Fprintf(&properties, "%04X;%s;%s;NAME %04X\n", codepoint, property, category, codepoint)
+			fmt.Fprintf(&categories, "%04X ; %s\n", codepoint, category)
+			fmt.Fprintf(&nfk, "U+%04X;%04X\n", codepoint, codepoint)
+		}
+		for name, content := range map[string]string{
+			"allcodepoints.txt":          properties.String(),
+			"DerivedGeneralCategory.txt": categories.String(),
+			"nfk.txt":                    nfk.String(),
+		} {
+			if err := os.MkdirAll(filepath.Join(dir, version), 0o755); err != nil {
+				b.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, version, name), []byte(content), 0o644); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := Compare(NewLoader(dir), "15.0.0", "16.0.0", Options{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// An overrides file is read with the outcome decided on for each code point
+// or range, which has to be a derived property value, and its errors name the
+// file and the line
+func TestReadOverrides(t *testing.T) {
+	for _, test := range []struct {
+		content string
+		want    []override
+		err     string
+	}{
+		{"U+166D ; DISALLOWED ; keep DISALLOWED, no exception needed\n", []override{{0x166D, 0x166D, "DISALLOWED", "keep DISALLOWED, no exception needed"}}, ""},
+		{"# Decided on 2026-10-01\n0B55..0B56;PVALID\n\n1E900 ; CONTEXTO # Adlam\n", []override{{0x0B55, 0x0B56, "PVALID", ""}, {0x1E900, 0x1E900, "CONTEXTO", ""}}, ""},
+		{"U+166D ; CONTEXTJ\nU+166E ; UNASSIGNED\n", []override{{0x166D, 0x166D, "CONTEXTJ", ""}, {0x166E, 0x166E, "UNASSIGNED", ""}}, ""},
+		{"U+166D ; DISALLOWED\nU+166E ; PVAILD\n", nil, `overrides.txt:2: "PVAILD" is not a derived property value`},
+		{"U+166D ; disallowed\n", nil, `overrides.txt:1: "disallowed" is not a derived property value`},
+		{"\nU+166D\n", nil, `overrides.txt:2: no derived property value for "U+166D"`},
+		{"U+166E..U+166D ; PVALID\n", nil, `overrides.txt:1: invalid code point range "U+166E..U+166D"`},
+	} {
+		path := filepath.Join(t.TempDir(), "overrides.txt")
+		if err := os.WriteFile(path, []byte(test.content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		overrides, err := readOverrides(path)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) || !strings.HasPrefix(err.Error(), path+":") {
+				t.Errorf("%q: got error %v, want %q", test.content, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %s", test.content, err)
+			continue
+		}
+		if !reflect.DeepEqual(overrides, test.want) {
+			t.Errorf("%q: got %v, want %v", test.content, overrides, test.want)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package idndiff
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Reads confusables.txt of UTS #39, with lines like
+// "0430 ;	0061 ;	MA	# ( а → a ) CYRILLIC SMALL LETTER A → LATIN SMALL LETTER A",
+// returning the prototype that each code point is confusable with, as space
+// separated code points
+func readConfusables(r io.Reader) (map[string]string, error) {
+	prototypes := make(map[string]string)
+
+	scanner := newLineScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		// The file starts with a byte order mark
+		line := strings.TrimPrefix(scanner.Text(), "\uFEFF")
+		fields := strings.Split(strings.Split(line, "#")[0], ";")
+		if len(fields) < 2 {
+			continue
+		}
+		source, err := parseCodepoint(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		var target []string
+		for _, part := range strings.Fields(fields[1]) {
+			codepoint, err := parseCodepoint(part)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			target = append(target, codepointKey(int(codepoint)))
+		}
+		prototypes[codepointKey(int(source))] = strings.Join(target, " ")
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return prototypes, nil
+}
+
+// Risk flags of homoglyphs, with the score they add
+var homoglyphFlags = []struct {
+	flag  string
+	score int
+}{
+	{"confusable with ASCII", 3},
+	{"confusable across scripts", 2},
+	{"confusable within its script", 1},
+}
+
+// Reads the confusables and scripts of the second version and scores the
+// code points that became PVALID
+func homoglyphSection(loader *Loader, version2 string, codepoints []int, properties1, properties2, codePointNames2 map[string]string) (*HomoglyphRisks, error) {
+	prototypes, err := loader.Confusables(version2)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version2, "confusables.txt"), err)
+	}
+	scripts2, err := loader.PropertyFile(version2, "Scripts.txt")
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version2, "Scripts.txt"), err)
+	}
+	risks := scoreHomoglyphs(codepoints, properties1, properties2, codePointNames2, scripts2, prototypes)
+	risks.NewScripts = findNewScriptConfusables(codepoints, properties1, properties2, scripts2, prototypes)
+	return risks, nil
+}
+
+// Scores the code points that became PVALID by how likely they are to be used
+// as homoglyphs: whether they are confusable, by UTS #39, with a prototype
+// that is ASCII, or that is in another script. The most risky come first.
+func scoreHomoglyphs(codepoints []int, properties1, properties2, codePointNames2, scripts2, prototypes map[string]string) *HomoglyphRisks {
+	risks := &HomoglyphRisks{}
+	for _, codepointInt := range codepoints {
+		codepoint := codepointKey(codepointInt)
+		if properties2[codepoint] != "PVALID" || properties1[codepoint] == "PVALID" {
+			continue
+		}
+		risks.Checked++
+		prototype, ok := prototypes[codepoint]
+		if !ok {
+			continue
+		}
+
+		script := scripts2[codepoint]
+		ascii, crossScript := true, false
+		var prototypeScripts []string
+		for _, target := range strings.Fields(prototype) {
+			if hexToInt(target) > 0x7F {
+				ascii = false
+			}
+			targetScript := scripts2[target]
+			// Common and Inherited characters are used with every script
+			if targetScript != script && targetScript != "Common" && targetScript != "Inherited" {
+				crossScript = true
+			}
+			if targetScript != "" && !slices.Contains(prototypeScripts, targetScript) {
+				prototypeScripts = append(prototypeScripts, targetScript)
+			}
+		}
+
+		risk := HomoglyphRisk{
+			CodePoint:        codepoint,
+			Script:           script,
+			Prototype:        prototype,
+			PrototypeScripts: prototypeScripts,
+			Name:             codePointNames2[codepoint],
+		}
+		for i, flagged := range []bool{ascii, crossScript, !ascii && !crossScript} {
+			if flagged {
+				risk.Flags = append(risk.Flags, homoglyphFlags[i].flag)
+				risk.Score += homoglyphFlags[i].score
+			}
+		}
+		risks.Entries = append(risks.Entries, risk)
+	}
+	sort.SliceStable(risks.Entries, func(i, j int) bool {
+		return risks.Entries[i].Score > risks.Entries[j].Score
+	})
+	return risks
+}
+
+// Finds the scripts whose code points were all unassigned in the first
+// version, and that have PVALID code points in the second, and checks whether
+// strings in each of them can be confused with strings in other scripts: the
+// new script is whole-script confusable with another script if some of its
+// PVALID code points are confusable with code points of that script only.
+func findNewScriptConfusables(codepoints []int, properties1, properties2, scripts2, prototypes map[string]string) []NewScript {
+	isNew := make(map[string]bool)
+	for _, codepointInt := range codepoints {
+		codepoint := codepointKey(codepointInt)
+		script := scripts2[codepoint]
+		if script == "" || script == "Common" || script == "Inherited" || script == "Unknown" {
+			continue
+		}
+		property, existedBefore := properties1[codepoint]
+		assignedBefore := existedBefore && property != "UNASSIGNED"
+		if _, seen := isNew[script]; !seen {
+			isNew[script] = !assignedBefore
+		} else if assignedBefore {
+			isNew[script] = false
+		}
+	}
+
+	byScript := make(map[string]*NewScript)
+	var scripts []*NewScript
+	for _, codepointInt := range codepoints {
+		codepoint := codepointKey(codepointInt)
+		script := scripts2[codepoint]
+		if !isNew[script] || properties2[codepoint] != "PVALID" {
+			continue
+		}
+		newScript, ok := byScript[script]
+		if !ok {
+			newScript = &NewScript{Script: script}
+			byScript[script] = newScript
+			scripts = append(scripts, newScript)
+		}
+		newScript.PVALID++
+
+		// Only code points confusable with code points of a single other,
+		// existing, script make strings in the scripts confusable
+		var other string
+		for _, target := range strings.Fields(prototypes[codepoint]) {
+			targetScript := scripts2[target]
+			if targetScript == "" || targetScript == "Common" || targetScript == "Inherited" || targetScript == script {
+				continue
+			}
+			if (other != "" && other != targetScript) || isNew[targetScript] {
+				other = ""
+				break
+			}
+			other = targetScript
+		}
+		if other == "" {
+			continue
+		}
+		index := slices.IndexFunc(newScript.ConfusableWith, func(c ScriptConfusables) bool { return c.Script == other })
+		if index < 0 {
+			newScript.ConfusableWith = append(newScript.ConfusableWith, ScriptConfusables{Script: other})
+			index = len(newScript.ConfusableWith) - 1
+		}
+		newScript.ConfusableWith[index].CodePoints = append(newScript.ConfusableWith[index].CodePoints, codepoint)
+	}
+
+	result := make([]NewScript, 0, len(scripts))
+	for _, newScript := range scripts {
+		sort.Slice(newScript.ConfusableWith, func(i, j int) bool {
+			return newScript.ConfusableWith[i].Script < newScript.ConfusableWith[j].Script
+		})
+		result = append(result, *newScript)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Script < result[j].Script })
+	return result
+}
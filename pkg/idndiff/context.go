@@ -0,0 +1,43 @@
+package idndiff
+
+// Counts the CONTEXTJ and CONTEXTO code points in both versions, and finds
+// the code points that became, or stopped being, one of them. There are so
+// few of them that every change needs to be reviewed.
+func findContextChanges(codepoints []int, properties1, properties2, codePointNames2 map[string]string) ContextRules {
+	var rules ContextRules
+	for _, property := range properties1 {
+		switch property {
+		case "CONTEXTJ":
+			rules.ContextJ1++
+		case "CONTEXTO":
+			rules.ContextO1++
+		}
+	}
+	for _, property := range properties2 {
+		switch property {
+		case "CONTEXTJ":
+			rules.ContextJ2++
+		case "CONTEXTO":
+			rules.ContextO2++
+		}
+	}
+
+	for _, codepointInt := range codepoints {
+		codepoint := codepointKey(codepointInt)
+		oldProperty, existedBefore := properties1[codepoint]
+		if !existedBefore {
+			oldProperty = "UNASSIGNED"
+		}
+		newProperty := properties2[codepoint]
+		if oldProperty == newProperty || !isContextRule(oldProperty) && !isContextRule(newProperty) {
+			continue
+		}
+		rules.Changes = append(rules.Changes, PropertyChange{codepoint, oldProperty, newProperty, codePointNames2[codepoint]})
+	}
+	return rules
+}
+
+// Reports whether a derived property value needs a contextual rule
+func isContextRule(property string) bool {
+	return property == "CONTEXTJ" || property == "CONTEXTO"
+}
@@ -0,0 +1,105 @@
+package idndiff
+
+import (
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Titles of the appendices A-E as written by this program, which is what the
+// review documents have used, and the letters of the appendices
+var appendixTitles = []struct {
+	letter string
+	title  string
+}{
+	{"A", "code points that changed derived property value"},
+	{"B", "changes in general category"},
+	{"C", "new code points where general category is mn"},
+	{"D", "new code points with nfk normalization"},
+	{"D", "new code points with length of nfk greater than one"},
+	{"E", "additions to exceptions"},
+	{"F", "derived property values"},
+}
+
+var (
+	// XML tags, for review documents in xml2rfc format
+	xmlTagRegex = regexp.MustCompile(`<[^>]+>`)
+	// A line listing a code point, such as "U+0B55; DISALLOWED; PVALID; ..."
+	listedCodepointRegex = regexp.MustCompile(`^\s*U\+([0-9A-Fa-f]{4,6})\b`)
+)
+
+// Reads the code points listed in each of the appendices A-E of a published
+// review document, in text or xml2rfc format. Appendices are found by their
+// titles, so that page breaks, numbering and markup do not matter.
+func readPublishedAppendices(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	appendices := make(map[string][]string)
+	current := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.Contains(line, "<") {
+			line = xmlTagRegex.ReplaceAllString(line, "")
+		}
+		lower := strings.ToLower(line)
+		if !listedCodepointRegex.MatchString(line) {
+			for _, appendix := range appendixTitles {
+				if strings.Contains(lower, appendix.title) {
+					current = appendix.letter
+					break
+				}
+			}
+			continue
+		}
+		if current == "" || current == "F" {
+			continue
+		}
+		codepoint := strings.ToUpper(listedCodepointRegex.FindStringSubmatch(line)[1])
+		if !slices.Contains(appendices[current], codepoint) {
+			appendices[current] = append(appendices[current], codepoint)
+		}
+	}
+	return appendices, nil
+}
+
+// Compares the code points in the appendices A-E of the report with those of
+// a published review document
+func crossCheck(r *Report, published map[string][]string) *CrossCheck {
+	computed := map[string][]string{}
+	for _, change := range r.AppendixA {
+		computed["A"] = append(computed["A"], change.CodePoint)
+	}
+	for _, change := range r.AppendixB {
+		computed["B"] = append(computed["B"], change.CodePoint)
+	}
+	for _, entry := range r.AppendixC {
+		computed["C"] = append(computed["C"], entry.CodePoint)
+	}
+	for _, entry := range r.AppendixD {
+		computed["D"] = append(computed["D"], entry.CodePoint)
+	}
+	for _, entry := range r.AppendixE {
+		if !slices.Contains(computed["E"], entry.CodePoint) {
+			computed["E"] = append(computed["E"], entry.CodePoint)
+		}
+	}
+
+	check := &CrossCheck{}
+	for _, letter := range []string{"A", "B", "C", "D", "E"} {
+		difference := CrossCheckAppendix{Appendix: letter, Published: len(published[letter]), Computed: len(computed[letter])}
+		for _, codepoint := range published[letter] {
+			if !slices.Contains(computed[letter], codepoint) {
+				difference.OnlyPublished = append(difference.OnlyPublished, codepoint)
+			}
+		}
+		for _, codepoint := range computed[letter] {
+			if !slices.Contains(published[letter], codepoint) {
+				difference.OnlyComputed = append(difference.OnlyComputed, codepoint)
+			}
+		}
+		check.Appendices = append(check.Appendices, difference)
+	}
+	return check
+}
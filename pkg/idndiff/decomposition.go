@@ -0,0 +1,178 @@
+package idndiff
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Returns the decomposition type of a decomposition from UnicodeData.txt:
+// "none", "canonical", or the tag of a compatibility decomposition, such as
+// "<font>" or "<compat>"
+func decompositionType(decomposition string) string {
+	switch {
+	case decomposition == "":
+		return "none"
+	case strings.HasPrefix(decomposition, "<"):
+		tag, _, _ := strings.Cut(decomposition, " ")
+		return tag
+	}
+	return "canonical"
+}
+
+// Parses a decomposition from UnicodeData.txt, such as "0041 0300" or
+// "<compat> 0020 0308", and returns the code points it maps to and whether it
+// is canonical
+func parseDecomposition(decomposition string) ([]rune, bool, error) {
+	fields := strings.Fields(decomposition)
+	canonical := !strings.HasPrefix(fields[0], "<")
+	if !canonical {
+		fields = fields[1:]
+	}
+	mapping := make([]rune, len(fields))
+	for i, field := range fields {
+		r, err := parseCodepoint(field)
+		if err != nil {
+			return nil, false, err
+		}
+		mapping[i] = r
+	}
+	return mapping, canonical, nil
+}
+
+// Finds the code points assigned in both versions whose decomposition type
+// changed, between canonical and compatibility or from one compatibility tag
+// to another. NFKC applies the compatibility decompositions, so such changes
+// can change the NFKC forms that Unstable (B) of RFC 5892 is computed from.
+func compareDecompositionTypes(codepoints []int, properties1, properties2, codePointNames2 map[string]string, unicodeData1, unicodeData2 map[string]unicodeDataEntry) []DecompositionChange {
+	var changes []DecompositionChange
+	for _, codepointInt := range codepoints {
+		codepoint := codepointKey(codepointInt)
+		entry1, assigned1 := unicodeData1[codepoint]
+		entry2, assigned2 := unicodeData2[codepoint]
+		if !assigned1 || !assigned2 {
+			continue
+		}
+		old, new := decompositionType(entry1.Decomposition), decompositionType(entry2.Decomposition)
+		if old == new {
+			continue
+		}
+		oldProperty, existedBefore := properties1[codepoint]
+		if !existedBefore {
+			oldProperty = "UNASSIGNED"
+		}
+		changes = append(changes, DecompositionChange{codepoint, old, new, entry1.Decomposition, entry2.Decomposition, oldProperty, properties2[codepoint], codePointNames2[codepoint]})
+	}
+	return changes
+}
+
+// Reads UnicodeData.txt of both versions and compares the decomposition types
+func decompositionTypeSection(loader *Loader, version1, version2 string, codepoints []int, properties1, properties2, codePointNames2 map[string]string) (*DecompositionChanges, error) {
+	unicodeData1, err := loader.UnicodeData(version1)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version1, "UnicodeData.txt"), err)
+	}
+	unicodeData2, err := loader.UnicodeData(version2)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version2, "UnicodeData.txt"), err)
+	}
+	return &DecompositionChanges{compareDecompositionTypes(codepoints, properties1, properties2, codePointNames2, unicodeData1, unicodeData2)}, nil
+}
+
+// Returns the full canonical and compatibility decomposition mappings of the
+// code points in UnicodeData.txt, with the mappings of one step applied until
+// nothing decomposes further, as in D68 and D65 of the Unicode Standard. The
+// compatibility decomposition applies the canonical mappings as well. Code
+// points that do not decompose are left out, and so are Hangul syllables,
+// whose decomposition is algorithmic and cannot change.
+func fullDecompositions(unicodeData map[string]unicodeDataEntry) (NFKData, NFKData, error) {
+	single := make(map[rune][]rune)
+	canonical := make(map[rune]bool)
+	for codepoint, entry := range unicodeData {
+		if entry.Decomposition == "" {
+			continue
+		}
+		mapping, isCanonical, err := parseDecomposition(entry.Decomposition)
+		if err != nil {
+			return nil, nil, fmt.Errorf("U+%s: %w", codepoint, err)
+		}
+		codepointInt := rune(hexToInt(codepoint))
+		single[codepointInt] = mapping
+		canonical[codepointInt] = isCanonical
+	}
+
+	var decompose func(r rune, compatibility bool) NFKMapping
+	decompose = func(r rune, compatibility bool) NFKMapping {
+		mapping, ok := single[r]
+		if !ok || !(compatibility || canonical[r]) {
+			return NFKMapping{r}
+		}
+		var full NFKMapping
+		for _, m := range mapping {
+			full = append(full, decompose(m, compatibility)...)
+		}
+		return full
+	}
+	canonicalData, compatibilityData := make(NFKData), make(NFKData)
+	for r := range single {
+		if canonical[r] {
+			canonicalData[codepointKey(int(r))] = decompose(r, false)
+		}
+		compatibilityData[codepointKey(int(r))] = decompose(r, true)
+	}
+	return canonicalData, compatibilityData, nil
+}
+
+// Finds the code points assigned in both versions whose full canonical or
+// compatibility decomposition changed, whether because their own mapping
+// changed or that of a code point they decompose to. NFC and NFKC are
+// computed from these, so any such change may change Unstable (B) of RFC 5892.
+func compareDecompositionMappings(codepoints []int, properties1, properties2, codePointNames2 map[string]string, unicodeData1, unicodeData2 map[string]unicodeDataEntry) ([]DecompositionMappingChange, error) {
+	canonical1, compatibility1, err := fullDecompositions(unicodeData1)
+	if err != nil {
+		return nil, err
+	}
+	canonical2, compatibility2, err := fullDecompositions(unicodeData2)
+	if err != nil {
+		return nil, err
+	}
+	var changes []DecompositionMappingChange
+	for _, codepointInt := range codepoints {
+		codepoint := codepointKey(codepointInt)
+		_, assigned1 := unicodeData1[codepoint]
+		_, assigned2 := unicodeData2[codepoint]
+		if !assigned1 || !assigned2 {
+			continue
+		}
+		oldCanonical, newCanonical := canonical1.mapping(codepointInt), canonical2.mapping(codepointInt)
+		oldCompatibility, newCompatibility := compatibility1.mapping(codepointInt), compatibility2.mapping(codepointInt)
+		if slices.Equal(oldCanonical, newCanonical) && slices.Equal(oldCompatibility, newCompatibility) {
+			continue
+		}
+		oldProperty, existedBefore := properties1[codepoint]
+		if !existedBefore {
+			oldProperty = "UNASSIGNED"
+		}
+		changes = append(changes, DecompositionMappingChange{codepoint, formatNFK(oldCanonical), formatNFK(newCanonical),
+			formatNFK(oldCompatibility), formatNFK(newCompatibility), oldProperty, properties2[codepoint], codePointNames2[codepoint]})
+	}
+	return changes, nil
+}
+
+// Reads UnicodeData.txt of both versions and compares the full decomposition
+// mappings
+func decompositionMappingSection(loader *Loader, version1, version2 string, codepoints []int, properties1, properties2, codePointNames2 map[string]string) (*DecompositionMappingChanges, error) {
+	unicodeData1, err := loader.UnicodeData(version1)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version1, "UnicodeData.txt"), err)
+	}
+	unicodeData2, err := loader.UnicodeData(version2)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version2, "UnicodeData.txt"), err)
+	}
+	changes, err := compareDecompositionMappings(codepoints, properties1, properties2, codePointNames2, unicodeData1, unicodeData2)
+	if err != nil {
+		return nil, err
+	}
+	return &DecompositionMappingChanges{changes}, nil
+}
@@ -0,0 +1,58 @@
+package idndiff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Collects the code points that changed derived property value in ranges of
+// consecutive code points with the same change. Code points missing in the
+// first version count as UNASSIGNED.
+func deltaRanges(codepoints []int, properties1, properties2 map[string]string) []DeltaRange {
+	var ranges []DeltaRange
+	previous := -2
+	for _, codepointInt := range codepoints {
+		codepoint := codepointKey(codepointInt)
+		oldProperty, existedBefore := properties1[codepoint]
+		if !existedBefore {
+			oldProperty = "UNASSIGNED"
+		}
+		newProperty := properties2[codepoint]
+		if oldProperty == newProperty {
+			continue
+		}
+		last := len(ranges) - 1
+		if last >= 0 && previous == codepointInt-1 && ranges[last].Old == oldProperty && ranges[last].New == newProperty {
+			ranges[last].End = codepoint
+		} else {
+			ranges = append(ranges, DeltaRange{codepoint, codepoint, oldProperty, newProperty})
+		}
+		previous = codepointInt
+	}
+	return ranges
+}
+
+// Renders the delta table: one line per range of code points that changed
+// derived property value, on the form
+//
+//	<first>[..<last>] ; <old value> ; <new value>
+//
+// with code points as four to six hexadecimal digits, as in the UCD files.
+// Lines starting with # are comments. The values are the derived property
+// values computed for each version, i.e. without UNDER REVIEW.
+func RenderDelta(w io.Writer, r *Report) error {
+	var buffer strings.Builder
+	fmt.Fprintf(&buffer, "# IDNA2008 derived property values changed from Unicode %s to Unicode %s\n", r.Version1, r.Version2)
+	fmt.Fprintf(&buffer, "# Format: <first>[..<last>] ; <old value> ; <new value>\n")
+	for _, entry := range r.Delta {
+		codepoints := entry.Start
+		if entry.Start != entry.End {
+			codepoints += ".." + entry.End
+		}
+		fmt.Fprintf(&buffer, "%-12s ; %-10s ; %s\n", codepoints, entry.Old, entry.New)
+	}
+	fmt.Fprintf(&buffer, "# %d ranges\n", len(r.Delta))
+	_, err := io.WriteString(w, buffer.String())
+	return err
+}
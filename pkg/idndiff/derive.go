@@ -0,0 +1,108 @@
+package idndiff
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+)
+
+// Returns the derived property value of a code point, by the rules of RFC
+// 5892 section 3 applied to its categories in order
+func (d *derivationData) property(codepointInt int) string {
+	return d.ruleValue(d.rule(codepointInt), codepointKey(codepointInt))
+}
+
+// Returns the derived property value that the rule of a category gives a
+// code point, with 0 for the last rule, which makes it DISALLOWED
+func (d *derivationData) ruleValue(rule category, codepoint string) string {
+	switch rule {
+	case exceptions:
+		return d.tables.Exceptions[codepoint]
+	case backwardCompatible:
+		return d.tables.BackwardCompatible[codepoint]
+	case unassigned:
+		return "UNASSIGNED"
+	case ldh, letterDigits:
+		return "PVALID"
+	case joinControl:
+		return "CONTEXTJ"
+	}
+	return "DISALLOWED"
+}
+
+// Short names of the Hangul jamo, for the names of Hangul syllables
+var (
+	jamoL = []string{"G", "GG", "N", "D", "DD", "R", "M", "B", "BB", "S", "SS", "", "J", "JJ", "C", "K", "T", "P", "H"}
+	jamoV = []string{"A", "AE", "YA", "YAE", "EO", "E", "YEO", "YE", "O", "WA", "WAE", "OE", "YO", "U", "WEO", "WE", "WI", "YU", "EU", "YI", "I"}
+	jamoT = []string{"", "G", "GG", "GS", "N", "NJ", "NH", "D", "L", "LG", "LM", "LB", "LS", "LT", "LP", "LH", "M", "B", "BS", "S", "SS", "NG", "J", "C", "K", "T", "P", "H"}
+)
+
+// Returns the name of a code point. UnicodeData.txt only gives the ranges of
+// Hangul syllables and ideographs, whose names are derived from the code
+// point as in section 4.8 of the Unicode Standard.
+func codepointName(codepointInt int, entry unicodeDataEntry, assigned bool) string {
+	switch {
+	case !assigned:
+		return "<unassigned>"
+	case entry.Name == "<Hangul Syllable>":
+		index := codepointInt - 0xAC00
+		return "HANGUL SYLLABLE " + jamoL[index/(21*28)] + jamoV[index%(21*28)/28] + jamoT[index%28]
+	case strings.HasPrefix(entry.Name, "<CJK Ideograph"):
+		return fmt.Sprintf("CJK UNIFIED IDEOGRAPH-%04X", codepointInt)
+	case entry.Name == "<Tangut Ideograph>" || entry.Name == "<Tangut Ideograph Supplement>":
+		return fmt.Sprintf("TANGUT IDEOGRAPH-%04X", codepointInt)
+	}
+	return entry.Name
+}
+
+// Writes the derived property value, General Category and name of every code
+// point in the format of allcodepoints.txt, computed from the UCD files of a
+// version
+func WriteDerivedTable(w io.Writer, loader *Loader, version string) error {
+	properties, names, err := loader.DerivedProperties(version)
+	if err != nil {
+		return err
+	}
+	derivation, err := loader.DerivationData(version)
+	if err != nil {
+		return err
+	}
+
+	buffered := bufio.NewWriter(w)
+	for codepointInt := 0; codepointInt < codeSpaceSize; codepointInt++ {
+		codepoint := codepointKey(codepointInt)
+		generalCategory := derivation.generalCategory[codepoint]
+		if generalCategory == "" {
+			generalCategory = "Cn"
+		}
+		fmt.Fprintf(buffered, "%s;%s;%s;%s\n", codepoint, properties[codepoint], generalCategory, names[codepoint])
+	}
+	return buffered.Flush()
+}
+
+// Compares the derived property values computed from the UCD files with
+// allcodepoints.txt, if it is there, returning a warning if they differ
+func derivationWarning(loader *Loader, version string, derived map[string]string) (string, error) {
+	published, _, err := loader.CodepointProperties(version)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", loader.Path(version, "allcodepoints.txt"), err)
+	}
+	var differences []string
+	for codepointInt := 0; codepointInt < codeSpaceSize; codepointInt++ {
+		codepoint := codepointKey(codepointInt)
+		if value, ok := published[codepoint]; ok && value != derived[codepoint] {
+			differences = append(differences, fmt.Sprintf("U+%s is %s, derived %s", codepoint, value, derived[codepoint]))
+		}
+	}
+	if len(differences) == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("%d code points in %s differ from the derived property values computed from the UCD files: %s",
+		len(differences), loader.Path(version, "allcodepoints.txt"), strings.Join(differences[:min(len(differences), 5)], "; ")), nil
+}
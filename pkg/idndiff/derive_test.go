@@ -0,0 +1,66 @@
+package idndiff
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// The derived property values computed from a trimmed UCD in
+// testdata/derivation, with a code point for each rule of RFC 5892 section 3
+func TestDerivedProperties(t *testing.T) {
+	loader := NewLoader(filepath.Join("testdata", "derivation"))
+	for _, test := range []struct {
+		version, codepoint, property string
+		category                     category // Of the rule deciding the value, 0 for the last rule
+	}{
+		{"6.0.0", "00DF", "PVALID", exceptions},              // Listed in Exceptions (F), although Unstable (B)
+		{"6.0.0", "0061", "PVALID", ldh},                     // LATIN SMALL LETTER A
+		{"6.0.0", "002D", "PVALID", ldh},                     // HYPHEN-MINUS
+		{"6.0.0", "0041", "DISALLOWED", unstable},            // LATIN CAPITAL LETTER A
+		{"6.0.0", "200C", "CONTEXTJ", joinControl},           // ZERO WIDTH NON-JOINER, also Default_Ignorable_Code_Point
+		{"6.0.0", "00AD", "DISALLOWED", unstable},            // SOFT HYPHEN, also Default_Ignorable_Code_Point
+		{"6.0.0", "20D0", "DISALLOWED", ignorableBlocks},     // Mn in Combining Diacritical Marks for Symbols
+		{"6.0.0", "1100", "DISALLOWED", oldHangulJamo},       // HANGUL CHOSEONG KIYEOK
+		{"6.0.0", "FDD0", "DISALLOWED", ignorableProperties}, // A noncharacter is Cn but not unassigned
+		{"6.0.0", "0378", "UNASSIGNED", unassigned},          // Cn
+		{"6.0.0", "E0080", "UNASSIGNED", unassigned},         // Not listed at all
+		{"5.2.0", "19DA", "PVALID", letterDigits},            // Nd
+		{"6.0.0", "19DA", "DISALLOWED", 0},                   // No since 6.0.0
+	} {
+		properties, _, err := loader.DerivedProperties(test.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := properties[test.codepoint]; got != test.property {
+			t.Errorf("%s: U+%s is %s, want %s", test.version, test.codepoint, got, test.property)
+		}
+		d, err := loader.DerivationData(test.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := d.categories(hexToInt(test.codepoint))
+		if test.category == 0 && c != 0 || c&test.category != test.category {
+			t.Errorf("%s: U+%s is in %q, want %q", test.version, test.codepoint, formatCategories(c), formatCategories(test.category))
+		}
+	}
+
+	_, names, err := loader.DerivedProperties("6.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names["00DF"] != "LATIN SMALL LETTER SHARP S" || names["0378"] != "<unassigned>" {
+		t.Errorf("U+00DF is named %q and U+0378 %q", names["00DF"], names["0378"])
+	}
+
+	// By the literal definition of Unstable (B), the soft hyphen is stable,
+	// as NFKC leaves it as it is, and the data cached with the other
+	// definition is not used for it
+	loader.LiteralUnstable = true
+	d, err := loader.DerivationData("6.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := d.categories(0x00AD); c&unstable != 0 || c&ignorableProperties == 0 {
+		t.Errorf("with LiteralUnstable U+00AD is in %q", formatCategories(c))
+	}
+}
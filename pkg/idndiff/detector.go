@@ -0,0 +1,212 @@
+package idndiff
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// The data of one version of Unicode that changes are detected in. Tables
+// for files that are missing are nil.
+type VersionData struct {
+	Version string
+	// Code points of the second version, in order
+	Codepoints      []int
+	Properties      map[string]string // Derived property values, from allcodepoints.txt
+	Names           map[string]string // Names, from allcodepoints.txt
+	GeneralCategory map[string]string // From DerivedGeneralCategory.txt
+	NFK             NFKData           // From nfk.txt
+	Emoji           map[string]bool   // From emoji-data.txt, of the second version only
+}
+
+// Something a ChangeDetector found about a code point. Old and New are the
+// values that changed, such as General Category, and OldProperty and
+// NewProperty the derived property values in each version.
+type Finding struct {
+	CodePoint   string `json:"code_point"`
+	Old         string `json:"old"`
+	New         string `json:"new"`
+	OldProperty string `json:"old_property"`
+	NewProperty string `json:"new_property"`
+	Name        string `json:"name"`
+}
+
+// Finds the code points that changed in some way between two versions. The
+// appendices A-D are computed by detectors, and additional ones are selected
+// with -detectors.
+type ChangeDetector interface {
+	Name() string
+	Detect(old, new *VersionData) []Finding
+}
+
+// The detectors that can be selected with -detectors, by name
+var detectors = map[string]ChangeDetector{}
+
+// Makes a detector available to -detectors. It panics if the name is taken.
+func RegisterDetector(d ChangeDetector) {
+	if _, ok := detectors[d.Name()]; ok {
+		panic("detector " + d.Name() + " registered twice")
+	}
+	detectors[d.Name()] = d
+}
+
+func init() {
+	RegisterDetector(nameChanges{})
+}
+
+// Returns the names of the detectors that can be selected, sorted
+func DetectorNames() []string {
+	return slices.Sorted(maps.Keys(detectors))
+}
+
+// Parses a comma separated list of detector names
+func parseDetectors(spec string) ([]ChangeDetector, error) {
+	var selected []ChangeDetector
+	for _, name := range strings.Split(spec, ",") {
+		d, ok := detectors[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown detector %q, expected one of %s", name, strings.Join(DetectorNames(), ", "))
+		}
+		selected = append(selected, d)
+	}
+	return selected, nil
+}
+
+// Appendix A: code points that changed derived property value, except those
+// that were UNASSIGNED in the first version
+type propertyChanges struct{}
+
+func (propertyChanges) Name() string { return "property-changes" }
+
+func (propertyChanges) Detect(old, new *VersionData) []Finding {
+	var findings []Finding
+	for _, codepointInt := range new.Codepoints {
+		codepoint := codepointKey(codepointInt)
+		oldProperty, existedBefore := old.Properties[codepoint]
+		newProperty := new.Properties[codepoint]
+		if existedBefore && oldProperty != newProperty && oldProperty != "UNASSIGNED" {
+			findings = append(findings, Finding{codepoint, oldProperty, newProperty, oldProperty, newProperty, new.Names[codepoint]})
+		}
+	}
+	return findings
+}
+
+// Appendix B: code points that changed General Category, unless UNASSIGNED in
+// either version
+type categoryChanges struct{}
+
+func (categoryChanges) Name() string { return "category-changes" }
+
+func (categoryChanges) Detect(old, new *VersionData) []Finding {
+	var findings []Finding
+	for _, codepointInt := range new.Codepoints {
+		codepoint := codepointKey(codepointInt)
+		oldProperty, existedBefore := old.Properties[codepoint]
+		newProperty := new.Properties[codepoint]
+		if !existedBefore || oldProperty == "UNASSIGNED" || newProperty == "UNASSIGNED" {
+			continue
+		}
+		oldCategory := old.GeneralCategory[codepoint]
+		newCategory := new.GeneralCategory[codepoint]
+		if oldCategory != newCategory {
+			findings = append(findings, Finding{codepoint, oldCategory, newCategory, oldProperty, newProperty, new.Names[codepoint]})
+		}
+	}
+	return findings
+}
+
+// Appendix C: assigned code points that got General Category Mn
+type newCombiningMarks struct{}
+
+func (newCombiningMarks) Name() string { return "new-combining-marks" }
+
+func (newCombiningMarks) Detect(old, new *VersionData) []Finding {
+	var findings []Finding
+	for _, codepointInt := range new.Codepoints {
+		codepoint := codepointKey(codepointInt)
+		property := new.Properties[codepoint]
+		// Skip code points that already had General Category Mn
+		if property != "UNASSIGNED" && new.GeneralCategory[codepoint] == "Mn" && old.GeneralCategory[codepoint] != "Mn" {
+			findings = append(findings, Finding{codepoint, old.GeneralCategory[codepoint], "Mn", old.Properties[codepoint], property, new.Names[codepoint]})
+		}
+	}
+	return findings
+}
+
+// Appendix D: code points that changed from UNASSIGNED to PVALID and have an
+// NFK normalization
+type newNFKNormalizations struct{}
+
+func (newNFKNormalizations) Name() string { return "new-nfk-normalizations" }
+
+func (newNFKNormalizations) Detect(old, new *VersionData) []Finding {
+	var findings []Finding
+	for _, codepointInt := range new.Codepoints {
+		codepoint := codepointKey(codepointInt)
+		oldProperty, existedBefore := old.Properties[codepoint]
+		newProperty := new.Properties[codepoint]
+		if !existedBefore || oldProperty != "UNASSIGNED" || newProperty != "PVALID" {
+			continue
+		}
+		if nfk := new.NFK.mapping(codepointInt); !nfk.mapsTo(codepointInt) {
+			findings = append(findings, Finding{codepoint, "", formatNFK(nfk), oldProperty, newProperty, new.Names[codepoint]})
+		}
+	}
+	return findings
+}
+
+// Code points that were assigned in both versions and changed name, which
+// the stability policies do not allow. Placeholders like <control> are
+// skipped, as they are replaced by -name-aliases.
+type nameChanges struct{}
+
+func (nameChanges) Name() string { return "name-changes" }
+
+func (nameChanges) Detect(old, new *VersionData) []Finding {
+	var findings []Finding
+	for _, codepointInt := range new.Codepoints {
+		codepoint := codepointKey(codepointInt)
+		oldProperty := old.Properties[codepoint]
+		newProperty := new.Properties[codepoint]
+		if oldProperty == "" || oldProperty == "UNASSIGNED" || newProperty == "UNASSIGNED" {
+			continue
+		}
+		oldName, newName := old.Names[codepoint], new.Names[codepoint]
+		if oldName != newName && !strings.HasPrefix(oldName, "<") && !strings.HasPrefix(newName, "<") {
+			findings = append(findings, Finding{codepoint, oldName, newName, oldProperty, newProperty, newName})
+		}
+	}
+	return findings
+}
+
+// Code points that became PVALID and are symbols (General Category So, Sk or
+// Sm) or emoji, which the derivation should never make PVALID. Finding any
+// indicates an error in the data or the derivation, so this detector always
+// runs.
+type newSymbols struct{}
+
+func (newSymbols) Name() string { return "new-pvalid-symbols" }
+
+func (newSymbols) Detect(old, new *VersionData) []Finding {
+	var findings []Finding
+	for _, codepointInt := range new.Codepoints {
+		codepoint := codepointKey(codepointInt)
+		oldProperty := old.Properties[codepoint]
+		newProperty := new.Properties[codepoint]
+		if newProperty != "PVALID" || oldProperty == "PVALID" {
+			continue
+		}
+		category := new.GeneralCategory[codepoint]
+		symbol := category == "So" || category == "Sk" || category == "Sm"
+		if !symbol && !new.Emoji[codepoint] {
+			continue
+		}
+		what := category
+		if new.Emoji[codepoint] {
+			what = strings.TrimPrefix(what+", Emoji", ", ")
+		}
+		findings = append(findings, Finding{codepoint, old.GeneralCategory[codepoint], what, oldProperty, newProperty, new.Names[codepoint]})
+	}
+	return findings
+}
@@ -0,0 +1,88 @@
+package idndiff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The code points an input file lists more than once, in the order found,
+// and what to do with them, as in Loader.DuplicatePolicy
+type duplicates struct {
+	policy     string
+	codepoints []string
+}
+
+// Returns the duplicates of a file read by the Loader
+func (l *Loader) newDuplicates() duplicates {
+	return duplicates{policy: l.duplicatePolicy()}
+}
+
+// Returns Loader.DuplicatePolicy, which is "last" if not set
+func (l *Loader) duplicatePolicy() string {
+	if l.DuplicatePolicy == "" {
+		return "last"
+	}
+	return l.DuplicatePolicy
+}
+
+// Stores the value of a code point in a table, following the policy of dups
+// if the table already has the code point, which is then recorded in dups.
+// With dups nil the last entry is used.
+func setEntry[V any](table map[string]V, codepoint string, value V, dups *duplicates) error {
+	if _, ok := table[codepoint]; ok && dups != nil {
+		if dups.policy == "error" {
+			return fmt.Errorf("U+%s is listed more than once, see -duplicates", codepoint)
+		}
+		dups.codepoints = append(dups.codepoints, codepoint)
+		if dups.policy == "first" {
+			return nil
+		}
+	}
+	table[codepoint] = value
+	return nil
+}
+
+// Records the code points a file of a version lists more than once
+func (l *Loader) recordDuplicates(version, name string, dups duplicates) {
+	if len(dups.codepoints) == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.duplicates == nil {
+		l.duplicates = make(map[string]map[string][]string)
+	}
+	if l.duplicates[version] == nil {
+		l.duplicates[version] = make(map[string][]string)
+	}
+	l.duplicates[version][name] = dups.codepoints
+}
+
+// Returns a warning per file of the versions, read so far, that lists code
+// points more than once
+func (l *Loader) DuplicateWarnings(versions ...string) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var warnings []string
+	for _, version := range versions {
+		names := make([]string, 0, len(l.duplicates[version]))
+		for name := range l.duplicates[version] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			dups := l.duplicates[version][name]
+			listed := dups
+			if len(listed) > 10 {
+				listed = listed[:10]
+			}
+			warning := fmt.Sprintf("%s has %d duplicate entries, the %s entry of each code point is used: U+%s", l.Path(version, name), len(dups), l.duplicatePolicy(), strings.Join(listed, ", U+"))
+			if len(listed) < len(dups) {
+				warning += ", ..."
+			}
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,20 @@
+package idndiff
+
+// Interpretations of RFC 5892 other than the current one, to reproduce tables
+// computed with them. Each is selected with a field of Loader, set from a
+// flag in errataFlags.
+
+// Returns Unstable (B) by the literal definition in RFC 5892. NFKC_Casefold,
+// which Changes_When_NFKC_Casefolded is defined by, also removes default
+// ignorable code points, while toNFKC and toCaseFold do not. Those of them
+// that NFKC leaves as they are (NFKC_Quick_Check is not No) are therefore
+// stable by the literal definition. None of them have a case folding.
+func literalUnstableCodePoints(changesWhenCasefolded, defaultIgnorable, nfkcChanges map[string]bool) map[string]bool {
+	unstable := make(map[string]bool, len(changesWhenCasefolded))
+	for codepoint := range changesWhenCasefolded {
+		if !defaultIgnorable[codepoint] || nfkcChanges[codepoint] {
+			unstable[codepoint] = true
+		}
+	}
+	return unstable
+}
@@ -0,0 +1,95 @@
+package idndiff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Compares the published Exceptions (F) with the exceptions proposed by this
+// comparison, i.e. the published ones plus the additions in Appendix E, using
+// the derived property values as they end up in Appendix F.
+func compareExceptions(publishedExceptions, properties2, codePointNames2 map[string]string, appendixE []ExceptionCandidate) *ExceptionsComparison {
+	comparison := &ExceptionsComparison{}
+
+	var published []int
+	for codepoint := range publishedExceptions {
+		published = append(published, hexToInt(codepoint))
+	}
+	sort.Ints(published)
+
+	// Additions: code points in Appendix E that are not already exceptions
+	seen := make(map[string]bool)
+	for _, entry := range appendixE {
+		codepoint := entry.CodePoint
+		if seen[codepoint] {
+			continue
+		}
+		seen[codepoint] = true
+		if _, isException := publishedExceptions[codepoint]; isException {
+			continue
+		}
+		// Skip code points excluded from review
+		if entry.Excluded {
+			continue
+		}
+		comparison.Additions = append(comparison.Additions, ExceptionValue{codepoint, properties2[codepoint], codePointNames2[codepoint]})
+	}
+
+	// Removals: published exceptions without a derived property value in the second version
+	for _, codepointInt := range published {
+		codepoint := codepointKey(codepointInt)
+		if property, exists := properties2[codepoint]; !exists || property == "UNASSIGNED" {
+			comparison.Removals = append(comparison.Removals, ExceptionValue{codepoint, publishedExceptions[codepoint], codePointNames2[codepoint]})
+		}
+	}
+
+	// Value changes: published value differs from the proposed value
+	for _, codepointInt := range published {
+		codepoint := codepointKey(codepointInt)
+		property, exists := properties2[codepoint]
+		if !exists || property == "UNASSIGNED" || property == publishedExceptions[codepoint] {
+			continue
+		}
+		comparison.ValueChanges = append(comparison.ValueChanges, ExceptionValueChange{codepoint, publishedExceptions[codepoint], property, codePointNames2[codepoint]})
+	}
+
+	return comparison
+}
+
+// Writes the code points in Appendix E that are not excluded from review in
+// the syntax of the Exceptions (F) table of RFC 5892 section 2.6, grouped by
+// the derived property value they would otherwise have. The value to give
+// them is to be decided in the review, so it is left as a placeholder.
+func WriteExceptionAdditions(w io.Writer, r *Report) error {
+	const placeholder = "TBD"
+	var values []string
+	byValue := make(map[string][]ExceptionCandidate)
+	seen := make(map[string]bool)
+	for _, entry := range r.AppendixE {
+		if entry.Excluded || seen[entry.CodePoint] {
+			continue
+		}
+		seen[entry.CodePoint] = true
+		if _, ok := byValue[entry.Property]; !ok {
+			values = append(values, entry.Property)
+		}
+		byValue[entry.Property] = append(byValue[entry.Property], entry)
+	}
+	sort.Strings(values)
+
+	buffered := bufio.NewWriter(w)
+	fmt.Fprintf(buffered, "   Proposed additions to Exceptions (F) for Unicode %s, compared with\n", r.Version2)
+	fmt.Fprintf(buffered, "   Unicode %s. Replace %s with PVALID, CONTEXTO or DISALLOWED.\n", r.Version1, placeholder)
+	for _, value := range values {
+		fmt.Fprintf(buffered, "\n   %s -- Would otherwise have been %s\n\n", placeholder, value)
+		for _, entry := range byValue[value] {
+			fmt.Fprintf(buffered, "   %s; %-11s# %s\n", entry.CodePoint, placeholder, entry.Name)
+		}
+	}
+	if len(values) == 0 {
+		fmt.Fprintf(buffered, "\n   No additions\n")
+	}
+	return buffered.Flush()
+}
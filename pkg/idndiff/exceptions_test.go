@@ -0,0 +1,68 @@
+package idndiff
+
+import (
+	"reflect"
+	"testing"
+)
+
+// The proposed exceptions are the published ones and the candidates of
+// Appendix E, compared with the published ones by the derived property values
+// of the second version
+func TestCompareExceptions(t *testing.T) {
+	names := map[string]string{"00DF": "LATIN SMALL LETTER SHARP S", "0B55": "ORIYA SIGN OVERLINE", "0B56": "ORIYA AI LENGTH MARK", "3007": "IDEOGRAPHIC NUMBER ZERO", "302E": "HANGUL SINGLE DOT TONE MARK"}
+	for _, test := range []struct {
+		name       string
+		published  map[string]string
+		properties map[string]string
+		appendixE  []ExceptionCandidate
+		want       ExceptionsComparison
+	}{
+		{
+			name:       "no changes",
+			published:  map[string]string{"00DF": "PVALID"},
+			properties: map[string]string{"00DF": "PVALID"},
+			want:       ExceptionsComparison{},
+		},
+		{
+			name:       "additions",
+			published:  map[string]string{"00DF": "PVALID"},
+			properties: map[string]string{"00DF": "PVALID", "0B55": "PVALID", "0B56": "DISALLOWED"},
+			appendixE: []ExceptionCandidate{
+				{CodePoint: "0B55", Property: "PVALID", Source: "A"},
+				{CodePoint: "0B55", Property: "PVALID", Source: "C"},
+				{CodePoint: "0B56", Property: "DISALLOWED", Source: "A"},
+			},
+			want: ExceptionsComparison{Additions: []ExceptionValue{{"0B55", "PVALID", names["0B55"]}, {"0B56", "DISALLOWED", names["0B56"]}}},
+		},
+		{
+			name:       "candidates that are excluded or already exceptions",
+			published:  map[string]string{"00DF": "PVALID"},
+			properties: map[string]string{"00DF": "PVALID", "0B55": "PVALID"},
+			appendixE: []ExceptionCandidate{
+				{CodePoint: "00DF", Property: "PVALID", Source: "A"},
+				{CodePoint: "0B55", Property: "PVALID", Source: "A", Excluded: true, ExclusionReason: "historic script"},
+			},
+			want: ExceptionsComparison{},
+		},
+		{
+			name:       "removals",
+			published:  map[string]string{"00DF": "PVALID", "3007": "PVALID", "302E": "DISALLOWED"},
+			properties: map[string]string{"00DF": "PVALID", "302E": "UNASSIGNED"},
+			want:       ExceptionsComparison{Removals: []ExceptionValue{{"3007", "PVALID", names["3007"]}, {"302E", "DISALLOWED", names["302E"]}}},
+		},
+		{
+			name:       "value changes",
+			published:  map[string]string{"00DF": "PVALID", "3007": "PVALID", "302E": "DISALLOWED"},
+			properties: map[string]string{"00DF": "DISALLOWED", "3007": "PVALID", "302E": "CONTEXTO"},
+			want: ExceptionsComparison{ValueChanges: []ExceptionValueChange{
+				{"00DF", "PVALID", "DISALLOWED", names["00DF"]},
+				{"302E", "DISALLOWED", "CONTEXTO", names["302E"]},
+			}},
+		},
+	} {
+		got := compareExceptions(test.published, test.properties, names, test.appendixE)
+		if !reflect.DeepEqual(*got, test.want) {
+			t.Errorf("%s: got %+v, want %+v", test.name, *got, test.want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package idndiff
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A range of code points, or a script, that prior review decisions have
+// deemed out of scope (for example historic scripts)
+type exclusion struct {
+	start  int
+	end    int
+	script string // Script property value, empty for a code point range
+	reason string
+}
+
+// Reads an exclusion file. Each line is a code point, a range or a script
+// followed by the reason, for example:
+//
+//	10570..105BF ; historic script
+//	Script=Vithkuqi ; historic script
+func readExclusions(filePath string) ([]exclusion, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var exclusions []exclusion
+	scanner := newLineScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(strings.Split(scanner.Text(), "#")[0])
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, ";", 2)
+		what := strings.TrimSpace(fields[0])
+		reason := "excluded"
+		if len(fields) == 2 && strings.TrimSpace(fields[1]) != "" {
+			reason = strings.TrimSpace(fields[1])
+		}
+		if script, isScript := strings.CutPrefix(what, "Script="); isScript {
+			exclusions = append(exclusions, exclusion{script: strings.TrimSpace(script), reason: reason})
+			continue
+		}
+		first, last, isRange := strings.Cut(what, "..")
+		if !isRange {
+			last = first
+		}
+		start, err1 := strconv.ParseInt(strings.TrimPrefix(first, "U+"), 16, 32)
+		end, err2 := strconv.ParseInt(strings.TrimPrefix(last, "U+"), 16, 32)
+		if err1 != nil || err2 != nil || start > end {
+			return nil, fmt.Errorf("line %d: invalid code point range %q", lineNumber, what)
+		}
+		exclusions = append(exclusions, exclusion{start: int(start), end: int(end), reason: reason})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return exclusions, nil
+}
+
+// Reads an exclusion file, and the scripts of the second version if any of
+// the exclusions refer to a script. Nothing is excluded if either fails.
+func exclusionSection(loader *Loader, version2, filePath string) ([]exclusion, *codePointScripts, error) {
+	exclusions, err := readExclusions(filePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading %s: %w", filePath, err)
+	}
+	if !needsScripts(exclusions) {
+		return exclusions, nil, nil
+	}
+	scripts2, err := loader.Scripts(version2)
+	if err != nil {
+		return nil, nil, err
+	}
+	return exclusions, scripts2, nil
+}
+
+// Reports whether any of the exclusions refer to a script, in which case
+// Scripts.txt is needed
+func needsScripts(exclusions []exclusion) bool {
+	for _, e := range exclusions {
+		if e.script != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the reason a code point is excluded from review, if it is. A code
+// point used with several scripts, by its Script_Extensions, is only
+// excluded by script if all of them are excluded, as it still needs review
+// for the others.
+func excludedFromReview(codepointInt int, exclusions []exclusion, scripts *codePointScripts) (string, bool) {
+	var codePointScripts []string
+	if scripts != nil {
+		codePointScripts = scripts.of(codepointKey(codepointInt))
+	}
+	allScriptsExcluded := !slices.ContainsFunc(codePointScripts, func(script string) bool {
+		return !slices.ContainsFunc(exclusions, func(e exclusion) bool { return e.script == script })
+	})
+	for _, e := range exclusions {
+		if e.script != "" {
+			if allScriptsExcluded && slices.Contains(codePointScripts, e.script) {
+				return e.reason, true
+			}
+		} else if codepointInt >= e.start && codepointInt <= e.end {
+			return e.reason, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,56 @@
+package idndiff
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Code points are excluded from review by range, or by script when all the
+// scripts they are used with are excluded
+func TestExcludedFromReview(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "exclusions.txt")
+	content := "# Decided on by the review team\n10570..105BF ; historic script\nU+0B55\nScript=Linear_B ; historic script\nScript=Cypriot ; historic script\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	exclusions, err := readExclusions(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !needsScripts(exclusions) {
+		t.Errorf("Scripts.txt is not needed for the exclusions by script")
+	}
+	scripts := &codePointScripts{
+		scripts:    map[string]string{"10000": "Linear_B", "10800": "Cypriot", "10107": "Common", "0061": "Latin"},
+		extensions: map[string][]string{"10107": {"Cypriot", "Linear_A", "Linear_B"}, "10100": {"Cypriot", "Linear_B"}},
+	}
+	for _, test := range []struct {
+		codepoint int
+		reason    string // "" if not excluded
+	}{
+		{0x10570, "historic script"},
+		{0x105BF, "historic script"},
+		{0x105C0, ""},
+		{0x0B55, "excluded"},
+		{0x10000, "historic script"},
+		{0x10800, "historic script"},
+		{0x10100, "historic script"}, // Cypriot and Linear_B, both excluded
+		{0x10107, ""},                // Also Linear_A, which is not
+		{0x0061, ""},
+	} {
+		reason, excluded := excludedFromReview(test.codepoint, exclusions, scripts)
+		if excluded != (test.reason != "") || reason != test.reason {
+			t.Errorf("U+%04X: excluded %t for %q, want %q", test.codepoint, excluded, reason, test.reason)
+		}
+	}
+
+	for _, invalid := range []string{"10570..1056F ; reversed\n", "U+XYZ ; historic script\n"} {
+		if err := os.WriteFile(path, []byte(invalid), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := readExclusions(path); err == nil {
+			t.Errorf("%q: read without an error", invalid)
+		}
+	}
+}
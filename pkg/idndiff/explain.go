@@ -0,0 +1,169 @@
+package idndiff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+)
+
+// How the derived property value of a code point is derived in a version,
+// rule by rule in the order of RFC 5892 section 3, up to the first rule that
+// applies
+type Explanation struct {
+	CodePoint string            `json:"code_point"`
+	Version   string            `json:"version"`
+	Name      string            `json:"name"`
+	Steps     []ExplanationStep `json:"steps"`
+	Property  string            `json:"property"`
+	// The value in allcodepoints.txt, if it is there
+	Table string `json:"table,omitempty"`
+}
+
+// A rule tested in the derivation, with what the UCD files say about the
+// code point. The value is the one the rule gives, if it applies.
+type ExplanationStep struct {
+	Rule     string `json:"rule"`
+	Applies  bool   `json:"applies"`
+	Evidence string `json:"evidence"`
+	Value    string `json:"value,omitempty"`
+}
+
+// Returns "Yes" or "No", as the UCD files write binary properties
+func yesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
+
+// Returns what the UCD files say about a code point for the rule of a
+// category
+func (d *derivationData) evidence(rule category, codepointInt int) string {
+	codepoint := codepointKey(codepointInt)
+	generalCategory := d.generalCategory[codepoint]
+	if generalCategory == "" {
+		generalCategory = "Cn"
+	}
+	switch rule {
+	case exceptions:
+		if value, ok := d.tables.Exceptions[codepoint]; ok {
+			return "listed as " + value
+		}
+		return "not listed"
+	case backwardCompatible:
+		if value, ok := d.tables.BackwardCompatible[codepoint]; ok {
+			return "listed as " + value
+		}
+		return "not listed"
+	case unassigned:
+		return fmt.Sprintf("General_Category is %s, Noncharacter_Code_Point is %s", generalCategory, yesNo(d.noncharacter[codepoint]))
+	case ldh:
+		if d.categories(codepointInt)&ldh != 0 {
+			return "the hyphen-minus, a digit or a lowercase letter a-z"
+		}
+		return "not the hyphen-minus, a digit or a lowercase letter a-z"
+	case joinControl:
+		return "Join_Control is " + yesNo(d.joinControl[codepoint])
+	case unstable:
+		if d.literalUnstable {
+			return fmt.Sprintf("toNFKC(toCaseFold(toNFKC(cp))) != cp is %s (-literal-unstable)", yesNo(d.unstable[codepoint]))
+		}
+		return "Changes_When_NFKC_Casefolded is " + yesNo(d.unstable[codepoint])
+	case ignorableProperties:
+		return fmt.Sprintf("Default_Ignorable_Code_Point is %s, White_Space is %s, Noncharacter_Code_Point is %s",
+			yesNo(d.defaultIgnorable[codepoint]), yesNo(d.whiteSpace[codepoint]), yesNo(d.noncharacter[codepoint]))
+	case ignorableBlocks:
+		block := d.blocks[codepoint]
+		if block == "" {
+			block = "No_Block"
+		}
+		return "Block is " + block
+	case oldHangulJamo:
+		hst := d.hangulSyllableType[codepoint]
+		if hst == "" {
+			hst = "NA"
+		}
+		return "Hangul_Syllable_Type is " + hst
+	case letterDigits:
+		return fmt.Sprintf("General_Category is %s, LetterDigits are Ll, Lu, Lo, Nd, Lm, Mn and Mc", generalCategory)
+	}
+	return "no rule applies"
+}
+
+// Explains the derived property value of a code point in a version, from
+// the files that generate needs. Names are from UnicodeData.txt, which is
+// optional.
+func Explain(loader *Loader, version string, codepointInt int) (*Explanation, error) {
+	d, err := loader.DerivationData(version)
+	if err != nil {
+		return nil, err
+	}
+	codepoint := codepointKey(codepointInt)
+	e := &Explanation{CodePoint: codepoint, Version: version}
+
+	unicodeData, err := loader.UnicodeData(version)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version, "UnicodeData.txt"), err)
+	}
+	entry, assigned := unicodeData[codepoint]
+	e.Name = codepointName(codepointInt, entry, assigned)
+
+	c := d.categories(codepointInt)
+	decided := false
+	for _, rule := range ruleOrder {
+		step := ExplanationStep{Rule: rule.label(), Applies: c&rule != 0, Evidence: d.evidence(rule, codepointInt)}
+		if step.Applies {
+			step.Value = d.ruleValue(rule, codepoint)
+		}
+		e.Steps = append(e.Steps, step)
+		if step.Applies {
+			decided = true
+			break
+		}
+	}
+	if !decided {
+		e.Steps = append(e.Steps, ExplanationStep{Rule: "Otherwise", Applies: true, Evidence: d.evidence(0, codepointInt), Value: "DISALLOWED"})
+	}
+	e.Property = d.property(codepointInt)
+
+	// allcodepoints.txt is optional, and only compared with
+	table, _, err := loader.CodepointProperties(version)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version, "allcodepoints.txt"), err)
+	}
+	e.Table = table[codepoint]
+	return e, nil
+}
+
+// Writes an explanation as text, a line per rule tested
+func WriteExplanationText(w io.Writer, e *Explanation) error {
+	var buffer strings.Builder
+	fmt.Fprintf(&buffer, "U+%s %s in Unicode %s\n\n", e.CodePoint, e.Name, e.Version)
+	for i, step := range e.Steps {
+		outcome := "does not apply"
+		if step.Applies {
+			outcome = "applies, " + step.Value
+		}
+		fmt.Fprintf(&buffer, "%d. %s: %s (%s)\n", i+1, step.Rule, outcome, step.Evidence)
+	}
+	fmt.Fprintf(&buffer, "\nDerived property value: %s\n", e.Property)
+	switch {
+	case e.Table == "":
+	case e.Table == e.Property:
+		fmt.Fprintf(&buffer, "allcodepoints.txt agrees\n")
+	default:
+		fmt.Fprintf(&buffer, "WARNING: allcodepoints.txt has %s\n", e.Table)
+	}
+	_, err := io.WriteString(w, buffer.String())
+	return err
+}
+
+// Writes an explanation as indented JSON
+func WriteExplanationJSON(w io.Writer, e *Explanation) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(e)
+}
@@ -0,0 +1,43 @@
+package idndiff
+
+import (
+	"slices"
+	"sort"
+)
+
+// Number of code points in the Unicode code space, U+0000..U+10FFFF
+const codeSpaceSize = 0x110000
+
+// The order in which derived property values are listed
+var propertyOrder = []string{"PVALID", "CONTEXTJ", "CONTEXTO", "DISALLOWED", "UNASSIGNED"}
+
+// Counts the code points per derived property value in both versions
+func countProperties(properties1, properties2 map[string]string) *PropertyFrequencies {
+	counts1 := make(map[string]int)
+	for _, property := range properties1 {
+		counts1[property]++
+	}
+	counts2 := make(map[string]int)
+	for _, property := range properties2 {
+		counts2[property]++
+	}
+
+	// The known values first, then any others in alphabetical order
+	values := append([]string(nil), propertyOrder...)
+	var others []string
+	for _, counts := range []map[string]int{counts1, counts2} {
+		for property := range counts {
+			if !slices.Contains(values, property) && !slices.Contains(others, property) {
+				others = append(others, property)
+			}
+		}
+	}
+	sort.Strings(others)
+	values = append(values, others...)
+
+	frequencies := &PropertyFrequencies{Total1: len(properties1), Total2: len(properties2)}
+	for _, property := range values {
+		frequencies.Values = append(frequencies.Values, PropertyFrequency{property, counts1[property], counts2[property]})
+	}
+	return frequencies
+}
@@ -0,0 +1,53 @@
+package idndiff
+
+import (
+	"fmt"
+	"slices"
+)
+
+// The styles of Appendix F: "compact", the ranges of this program, or
+// "iana", the ranges as in the registry of derived property values published
+// by IANA, so that they can be compared with it byte for byte
+var fStyles = []string{"compact", "iana"}
+
+// The header line of the CSV format of the IANA registry
+var ianaHeader = []string{"Codepoint", "Property", "Description"}
+
+// Returns the name of a code point as the IANA registry, like Appendix B of
+// RFC 5892, writes it: unassigned code points are "<reserved>"
+func ianaName(codePointNames map[string]string, properties map[string]string, codepoint string) string {
+	if properties[codepoint] == "UNASSIGNED" {
+		return "<reserved>"
+	}
+	return codePointNames[codepoint]
+}
+
+// Describes each range of Appendix F as the IANA registry does, by the names
+// of its first and last code points, as in "NULL..COMMA"
+func describeRanges(ranges []PropertyRange, properties, codePointNames map[string]string) {
+	for i, r := range ranges {
+		description := ianaName(codePointNames, properties, r.Start)
+		if r.End != r.Start {
+			description += ".." + ianaName(codePointNames, properties, r.End)
+		}
+		ranges[i].Description = description
+	}
+}
+
+// Returns a range of Appendix F as a record of the IANA registry, with the
+// code points as in "0000-002C"
+func ianaRecord(r PropertyRange) []string {
+	codepoints := r.Start
+	if r.End != r.Start {
+		codepoints += "-" + r.End
+	}
+	return []string{codepoints, r.Property, r.Description}
+}
+
+// Checks the style of Appendix F
+func checkFStyle(style string) error {
+	if style != "" && !slices.Contains(fStyles, style) {
+		return fmt.Errorf("unknown style %q for Appendix F, expected compact or iana", style)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package idndiff
+
+import "maps"
+
+// The code points of each version that are listed in only one of
+// allcodepoints.txt and DerivedGeneralCategory.txt
+type GCConsistency struct {
+	Versions []GCConsistencyVersion `json:"versions"`
+}
+
+// The code points of one version that have a General_Category but no derived
+// property value, or the other way around
+type GCConsistencyVersion struct {
+	Version string `json:"version"`
+	// Code points with a General_Category but no derived property value
+	MissingProperty      []CodePointRange `json:"missing_property"`
+	MissingPropertyCount int              `json:"missing_property_count"`
+	// Code points with a derived property value but no General_Category
+	MissingCategory      []CodePointRange `json:"missing_category"`
+	MissingCategoryCount int              `json:"missing_category_count"`
+	// Whether the code points without a derived property value were compared
+	// as UNASSIGNED
+	Synthesized bool `json:"synthesized"`
+}
+
+// Finds the code points of a version that are listed in only one of the
+// derived property values and the General_Category. With synthesize, the
+// code points without a derived property value are added as UNASSIGNED to a
+// copy of the properties, which is returned instead of the shared map.
+func checkGCConsistency(version string, properties, generalCategory map[string]string, synthesize bool) (GCConsistencyVersion, map[string]string) {
+	result := GCConsistencyVersion{Version: version}
+	var missingProperty, missingCategory []int
+	var key [8]byte
+	for codepointInt := 0; codepointInt < codeSpaceSize; codepointInt++ {
+		codepoint := appendHexKey(key[:0], codepointInt)
+		_, hasProperty := properties[string(codepoint)]
+		_, hasCategory := generalCategory[string(codepoint)]
+		if hasCategory && !hasProperty {
+			missingProperty = append(missingProperty, codepointInt)
+		}
+		if hasProperty && !hasCategory {
+			missingCategory = append(missingCategory, codepointInt)
+		}
+	}
+	result.MissingProperty, result.MissingPropertyCount = codePointRanges(missingProperty), len(missingProperty)
+	result.MissingCategory, result.MissingCategoryCount = codePointRanges(missingCategory), len(missingCategory)
+
+	if synthesize && len(missingProperty) > 0 {
+		properties = maps.Clone(properties)
+		for _, codepointInt := range missingProperty {
+			properties[codepointKey(codepointInt)] = "UNASSIGNED"
+		}
+		result.Synthesized = true
+	}
+	return result, properties
+}
+
+// Collects sorted code points in ranges of consecutive code points
+func codePointRanges(codepoints []int) []CodePointRange {
+	var ranges []CodePointRange
+	for i := 0; i < len(codepoints); {
+		j := i
+		for j+1 < len(codepoints) && codepoints[j+1] == codepoints[j]+1 {
+			j++
+		}
+		ranges = append(ranges, CodePointRange{codepointKey(codepoints[i]), codepointKey(codepoints[j])})
+		i = j + 1
+	}
+	return ranges
+}
@@ -0,0 +1,62 @@
+package idndiff
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// How derived property values and other identifiers are written in the prose
+// of each format, such as "PROTOCOL VALID" for PVALID, by the name of the
+// format as in Formats. Appendices and tables always use the identifiers.
+type Glossary map[string]map[string]string
+
+// Reads a glossary with lines like "PVALID ; PROTOCOL VALID", mapping an
+// identifier to how it is written in the prose of every format, or like
+// "PVALID ; PROTOCOL VALID ; text, html" for only some formats
+func ReadGlossaryFile(path string) (Glossary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	glossary := make(Glossary)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(strings.Split(line, "#")[0])
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, ";")
+		for j := range fields {
+			fields[j] = strings.TrimSpace(fields[j])
+		}
+		if len(fields) < 2 || len(fields) > 3 || fields[0] == "" || fields[1] == "" {
+			return nil, fmt.Errorf("line %d: expected an identifier and how to write it, and optionally the formats, separated by \";\"", i+1)
+		}
+		formats := slices.Sorted(maps.Keys(Formats))
+		if len(fields) == 3 {
+			formats = strings.Split(fields[2], ",")
+		}
+		for _, format := range formats {
+			format = strings.TrimSpace(format)
+			if _, ok := Formats[format]; !ok {
+				return nil, fmt.Errorf("line %d: unknown format %q", i+1, format)
+			}
+			if glossary[format] == nil {
+				glossary[format] = make(map[string]string)
+			}
+			glossary[format][fields[0]] = fields[1]
+		}
+	}
+	return glossary, nil
+}
+
+// Returns how an identifier is written in prose with the terms of a format
+// from a Glossary
+func prose(terms map[string]string, identifier string) string {
+	if display, found := terms[identifier]; found {
+		return display
+	}
+	return identifier
+}
@@ -0,0 +1,56 @@
+package idndiff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The terms of a glossary are used in the prose of the formats they are for,
+// while the appendices keep the identifiers
+func TestGlossary(t *testing.T) {
+	report := Report{
+		Meta:      Meta{Version1: "13.0.0", Version2: "14.0.0"},
+		AppendixA: []PropertyChange{{"0B55", "DISALLOWED", "PVALID", "ORIYA SIGN OVERLINE"}},
+	}
+	for _, test := range []struct {
+		glossary string
+		format   string
+		prose    string // The line of the summary on U+0B55, "" for an invalid glossary
+	}{
+		{"PVALID ; PROTOCOL VALID\n", "text", "0B55 changed from DISALLOWED to PROTOCOL VALID\n"},
+		{"PVALID ; PROTOCOL VALID\n", "html", "0B55 changed from DISALLOWED to PROTOCOL VALID\n"},
+		{"# Terms of RFC 5892\nPVALID ; PROTOCOL VALID # Section 2\nDISALLOWED;Disallowed\n", "text", "0B55 changed from Disallowed to PROTOCOL VALID\n"},
+		{"PVALID ; PROTOCOL VALID ; html\n", "text", "0B55 changed from DISALLOWED to PVALID\n"},
+		{"PVALID ; PROTOCOL VALID ; text, html\n", "html", "0B55 changed from DISALLOWED to PROTOCOL VALID\n"},
+		{"PVALID PROTOCOL VALID\n", "text", ""},
+		{"PVALID ; PROTOCOL VALID ; pdf\n", "text", ""},
+	} {
+		path := filepath.Join(t.TempDir(), "glossary.txt")
+		if err := os.WriteFile(path, []byte(test.glossary), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		glossary, err := ReadGlossaryFile(path)
+		if test.prose == "" {
+			if err == nil {
+				t.Errorf("%q: read without an error", test.glossary)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %s", test.glossary, err)
+			continue
+		}
+		var buffer strings.Builder
+		if err := Formats[test.format].Render(&buffer, &report, RenderOptions{Glossary: glossary}); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buffer.String(), test.prose) {
+			t.Errorf("%q: no %q in the %s report", test.glossary, test.prose, test.format)
+		}
+		if test.format == "text" && !strings.Contains(buffer.String(), "U+0B55; DISALLOWED; PVALID; ORIYA SIGN OVERLINE\n") {
+			t.Errorf("%q: Appendix A of the text report not written with the identifiers", test.glossary)
+		}
+	}
+}
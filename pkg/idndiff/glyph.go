@@ -0,0 +1,38 @@
+package idndiff
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// Returns the character of a code point, to show it in a report, or "" if it
+// has no glyph to show, such as controls, format characters, white space and
+// code points that are unassigned in the Unicode version that Go knows.
+// Combining marks are shown on a dotted circle.
+func glyph(codepointInt int) string {
+	r := rune(codepointInt)
+	if !unicode.IsGraphic(r) || unicode.IsSpace(r) || unicode.Is(unicode.Cf, r) || unicode.Is(unicode.Other_Default_Ignorable_Code_Point, r) {
+		return ""
+	}
+	if unicode.Is(unicode.M, r) {
+		return "\u25CC" + string(r)
+	}
+	return string(r)
+}
+
+// Returns the character of a code point with ShowGlyphs, or ""
+func (opts RenderOptions) glyph(codepoint string) string {
+	if !opts.ShowGlyphs {
+		return ""
+	}
+	return glyph(hexToInt(codepoint))
+}
+
+// Formats a code point as U+XXXX, followed by the character with ShowGlyphs,
+// isolated if it is written from right to left
+func (opts RenderOptions) codePointLabel(codepoint string) string {
+	if g := opts.glyph(codepoint); g != "" {
+		return fmt.Sprintf("U+%s %s", codepoint, isolate(g))
+	}
+	return "U+" + codepoint
+}
@@ -0,0 +1,87 @@
+package idndiff
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// The precomposed Hangul syllables, which the stability policies keep in
+// this range
+const (
+	hangulSyllablesStart = 0xAC00
+	hangulSyllablesEnd   = 0xD7A3
+)
+
+// A Hangul code point with a derived property value other than RFC 5892 gives it
+type hangulDrift struct {
+	codepoint string
+	kind      string // Hangul_Syllable_Type, or "syllable" without HangulSyllableType.txt
+	property  string
+	expected  string
+}
+
+// Checks the derived property values of the Hangul code points of a version:
+// the precomposed syllables must be PVALID, and the conjoining jamo, with
+// Hangul_Syllable_Type L, V or T, DISALLOWED as OldHangulJamo (I), unless
+// Exceptions (F) or BackwardCompatible (G) say otherwise. Without
+// hangulSyllableType only the syllables are checked.
+func checkHangul(properties, hangulSyllableType map[string]string, tables RFC5892Tables) []hangulDrift {
+	var drifts []hangulDrift
+	check := func(codepoint, kind, expected string) {
+		if value, ok := tables.Exceptions[codepoint]; ok {
+			expected = value
+		}
+		if value, ok := tables.BackwardCompatible[codepoint]; ok {
+			expected = value
+		}
+		if property, ok := properties[codepoint]; ok && property != expected {
+			drifts = append(drifts, hangulDrift{codepoint, kind, property, expected})
+		}
+	}
+
+	for codepointInt := hangulSyllablesStart; codepointInt <= hangulSyllablesEnd; codepointInt++ {
+		codepoint := codepointKey(codepointInt)
+		kind := hangulSyllableType[codepoint]
+		if kind == "" {
+			kind = "syllable"
+		}
+		check(codepoint, kind, "PVALID")
+	}
+	for codepoint, kind := range hangulSyllableType {
+		switch kind {
+		case "L", "V", "T":
+			check(codepoint, kind, "DISALLOWED")
+		}
+	}
+	sort.Slice(drifts, func(i, j int) bool { return hexToInt(drifts[i].codepoint) < hexToInt(drifts[j].codepoint) })
+	return drifts
+}
+
+// Checks the Hangul code points of a version, returning a warning if any
+// have unexpected derived property values, or an error in strict mode
+func hangulWarning(loader *Loader, version string, properties map[string]string, strict bool) (string, error) {
+	hangulSyllableType, err := loader.PropertyFile(version, "HangulSyllableType.txt")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("reading %s: %w", loader.Path(version, "HangulSyllableType.txt"), err)
+	}
+	drifts := checkHangul(properties, hangulSyllableType, loader.tables())
+	if len(drifts) == 0 {
+		return "", nil
+	}
+
+	var examples []string
+	for _, drift := range drifts[:min(len(drifts), 5)] {
+		examples = append(examples, fmt.Sprintf("U+%s (%s) is %s, expected %s", drift.codepoint, drift.kind, drift.property, drift.expected))
+	}
+	message := fmt.Sprintf("%d Hangul code points in Unicode %s do not have the derived property values of RFC 5892: %s", len(drifts), version, strings.Join(examples, "; "))
+	if len(drifts) > len(examples) {
+		message += "; ..."
+	}
+	if strict {
+		return "", errors.New(message)
+	}
+	return message, nil
+}
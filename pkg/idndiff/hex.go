@@ -0,0 +1,67 @@
+package idndiff
+
+import "sync"
+
+// The hexadecimal digits of the keys of the property tables
+const hexDigits = "0123456789ABCDEF"
+
+// The keys of the code points of the BMP, four digits each, in one string
+// that the keys are sliced from, so that formatting them does not allocate
+var bmpKeys = sync.OnceValue(func() string {
+	buf := make([]byte, 0, 4*0x10000)
+	for codepoint := 0; codepoint < 0x10000; codepoint++ {
+		buf = appendHexKey(buf, codepoint)
+	}
+	return string(buf)
+})
+
+// Returns a code point in the format of the keys of the property tables, as
+// fmt.Sprintf("%04X", codepoint) does. This runs for every code point in the
+// code space in most sections, where fmt allocated for each one.
+func codepointKey(codepoint int) string {
+	if codepoint >= 0 && codepoint < 0x10000 {
+		return bmpKeys()[4*codepoint : 4*codepoint+4]
+	}
+	return string(appendHexKey(make([]byte, 0, 6), codepoint))
+}
+
+// Appends a code point in the format of the keys of the property tables, at
+// least four uppercase hexadecimal digits as with "%04X", without allocating
+func appendHexKey(buf []byte, codepoint int) []byte {
+	n := 4
+	for c := codepoint >> 16; c > 0; c >>= 4 {
+		n++
+	}
+	for i := n - 1; i >= 0; i-- {
+		buf = append(buf, hexDigits[(codepoint>>(4*i))&0xF])
+	}
+	return buf
+}
+
+// Parses a hexadecimal number of up to eight digits, in upper or lower
+// case, without a prefix or sign, as strconv.ParseInt(s, 16, 32) does for
+// code points but without its allocations on errors
+func parseHex(s string) (int, bool) {
+	if len(s) == 0 || len(s) > 8 {
+		return 0, false
+	}
+	value := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= '0' && c <= '9':
+			c -= '0'
+		case c >= 'A' && c <= 'F':
+			c -= 'A' - 10
+		case c >= 'a' && c <= 'f':
+			c -= 'a' - 10
+		default:
+			return 0, false
+		}
+		value = value<<4 | int(c)
+	}
+	if value > 0x7FFFFFFF {
+		return 0, false
+	}
+	return value, true
+}
@@ -0,0 +1,98 @@
+package idndiff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestAppendHexKey(t *testing.T) {
+	for _, codepoint := range []int{0, 0x41, 0xFFFF, 0x10000, 0xE0001, 0x10FFFF} {
+		if got, want := string(appendHexKey(nil, codepoint)), fmt.Sprintf("%04X", codepoint); got != want {
+			t.Errorf("appendHexKey(%X) = %q, want %q", codepoint, got, want)
+		}
+	}
+}
+
+// The keys of the whole code space are those of fmt, and parse back
+func TestCodepointKey(t *testing.T) {
+	for codepoint := range codeSpaceSize {
+		key := codepointKey(codepoint)
+		if want := fmt.Sprintf("%04X", codepoint); key != want {
+			t.Fatalf("codepointKey(%X) = %q, want %q", codepoint, key, want)
+		}
+		if value, ok := parseHex(key); !ok || value != codepoint {
+			t.Fatalf("parseHex(%q) = %X, %v", key, value, ok)
+		}
+	}
+}
+
+// parseHex accepts what strconv.ParseInt(s, 16, 32) accepts, apart from a
+// sign, and nothing else
+func TestParseHex(t *testing.T) {
+	for _, s := range []string{"", "0", "41", "00e9", "10FFFF", "7FFFFFFF", "80000000", "123456789", "00G1", "+41", "-1", " 41", "0x41", "U+0041"} {
+		value, ok := parseHex(s)
+		want, err := strconv.ParseInt(s, 16, 32)
+		if strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-") {
+			err = strconv.ErrSyntax
+		}
+		if ok != (err == nil) || ok && int64(value) != want {
+			t.Errorf("parseHex(%q) = %X, %v, want %X, %v", s, value, ok, want, err)
+		}
+	}
+}
+
+func BenchmarkCodepointKey(b *testing.B) {
+	b.Run("codepointKey", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			for codepoint := range codeSpaceSize {
+				codepointKey(codepoint)
+			}
+		}
+	})
+	b.Run("fmt", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			for codepoint := range codeSpaceSize {
+				_ = fmt.Sprintf("%04X", codepoint)
+			}
+		}
+	})
+}
+
+func BenchmarkParseHex(b *testing.B) {
+	keys := make([]string, codeSpaceSize)
+	for codepoint := range codeSpaceSize {
+		keys[codepoint] = codepointKey(codepoint)
+	}
+	b.Run("parseHex", func(b *testing.B) {
+		for b.Loop() {
+			for _, key := range keys {
+				parseHex(key)
+			}
+		}
+	})
+	b.Run("strconv", func(b *testing.B) {
+		for b.Loop() {
+			for _, key := range keys {
+				strconv.ParseInt(key, 16, 32)
+			}
+		}
+	})
+}
+
+// An allcodepoints.txt of the whole code space, a line per code point
+func BenchmarkReadCodepointProperties(b *testing.B) {
+	var file strings.Builder
+	for codepoint := range codeSpaceSize {
+		fmt.Fprintf(&file, "%04X;PVALID;;NAME %04X\n", codepoint, codepoint)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, _, err := readCodepointProperties(strings.NewReader(file.String()), nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
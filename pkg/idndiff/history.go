@@ -0,0 +1,163 @@
+package idndiff
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// A change to a code point between two versions of Unicode. A sequence of
+// them, across many versions, is the changelog of each code point.
+type ChangeEvent struct {
+	CodePoint string `json:"code_point"`
+	// The version in which the change was made
+	Version string `json:"version"`
+	// What changed: derived_property, general_category, nfk or exception
+	Field  string `json:"field"`
+	Old    string `json:"old"`
+	New    string `json:"new"`
+	Reason string `json:"reason"`
+}
+
+// Reasons for code points to be added to Appendix E, by the appendix they
+// were found in
+var candidateReasons = map[string]string{
+	"A": "derived property value changed (Appendix A)",
+	"C": "new code point with General Category Mn (Appendix C)",
+	"D": "new code point with NFK normalization (Appendix D)",
+}
+
+// Returns the changes between the two versions of a report, in code point order
+func ChangeEvents(r *Report) []ChangeEvent {
+	var events []ChangeEvent
+	for _, delta := range r.Delta {
+		reason := "derived property value changed"
+		if delta.Old == "UNASSIGNED" {
+			reason = "newly assigned"
+		}
+		for codepoint := hexToInt(delta.Start); codepoint <= hexToInt(delta.End); codepoint++ {
+			events = append(events, ChangeEvent{codepointKey(codepoint), r.Version2, "derived_property", delta.Old, delta.New, reason})
+		}
+	}
+	for _, change := range r.AppendixB {
+		events = append(events, ChangeEvent{change.CodePoint, r.Version2, "general_category", change.Old, change.New, "General Category changed (Appendix B)"})
+	}
+	for _, change := range r.NFKChanges {
+		events = append(events, ChangeEvent{change.CodePoint, r.Version2, "nfk", change.Old, change.New, "NFK normalization changed"})
+	}
+	for _, entry := range r.AppendixE {
+		if entry.Excluded {
+			continue
+		}
+		events = append(events, ChangeEvent{entry.CodePoint, r.Version2, "exception", "", "UNDER REVIEW", candidateReasons[entry.Source]})
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return hexToInt(events[i].CodePoint) < hexToInt(events[j].CodePoint)
+	})
+	return events
+}
+
+// Returns the changes between each pair of consecutive versions, as a
+// changelog per code point: in code point order, and then in version order.
+// With a checkpoint directory, the changes of each pair are saved there as
+// soon as they are computed, and pairs saved by an earlier run are not
+// compared again. A pair that takes longer than the timeout, if not zero,
+// fails the run.
+func History(loader *Loader, versions []string, opts Options, checkpointDir string, timeout time.Duration) ([]ChangeEvent, error) {
+	var events []ChangeEvent
+	for i := 0; i+1 < len(versions); i++ {
+		pairEvents, err := historyPair(loader, versions[i], versions[i+1], opts, checkpointDir, timeout)
+		if err != nil {
+			return nil, fmt.Errorf("comparing %s and %s: %w", versions[i], versions[i+1], err)
+		}
+		events = append(events, pairEvents...)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return hexToInt(events[i].CodePoint) < hexToInt(events[j].CodePoint)
+	})
+	return events, nil
+}
+
+// Returns the changes between two versions, from the checkpoint directory if
+// they were saved there
+func historyPair(loader *Loader, version1, version2 string, opts Options, checkpointDir string, timeout time.Duration) ([]ChangeEvent, error) {
+	var checkpoint string
+	if checkpointDir != "" {
+		checkpoint = filepath.Join(checkpointDir, version1+"-"+version2+".json.gz")
+		artifact, err := ReadResults(checkpoint)
+		if err == nil {
+			return artifact.Events, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	report, err := compareWithTimeout(loader, version1, version2, opts, timeout)
+	if err != nil {
+		return nil, err
+	}
+	events := ChangeEvents(report)
+
+	if checkpoint != "" {
+		if err := os.MkdirAll(checkpointDir, 0o755); err != nil {
+			return nil, err
+		}
+		// Write to a temporary file first, so that an interrupted run never
+		// leaves a partial checkpoint behind
+		if err := WriteResults(checkpoint+".tmp", []string{version1, version2}, events); err != nil {
+			return nil, err
+		}
+		if err := os.Rename(checkpoint+".tmp", checkpoint); err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
+
+// Compares two versions, giving up after the timeout if it is not zero
+func compareWithTimeout(loader *Loader, version1, version2 string, opts Options, timeout time.Duration) (*Report, error) {
+	if timeout <= 0 {
+		return Compare(loader, version1, version2, opts)
+	}
+	type result struct {
+		report *Report
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		report, err := Compare(loader, version1, version2, opts)
+		done <- result{report, err}
+	}()
+	select {
+	case result := <-done:
+		return result.report, result.err
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("timed out after %s", timeout)
+	}
+}
+
+// Writes change events as indented JSON
+func WriteEventsJSON(w io.Writer, events []ChangeEvent) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(events)
+}
+
+// Writes change events as CSV, with a header line
+func WriteEventsCSV(w io.Writer, events []ChangeEvent) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"code_point", "version", "field", "old", "new", "reason"})
+	for _, event := range events {
+		writer.Write([]string{event.CodePoint, event.Version, event.Field, event.Old, event.New, event.Reason})
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,169 @@
+package idndiff
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// The template of the HTML report
+//
+//go:embed data/report.html.tmpl
+var htmlTemplate string
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(htmlTemplate))
+
+// What the HTML report is made from
+type htmlReport struct {
+	Report     *Report
+	Summary    string
+	Appendices []htmlAppendix
+}
+
+// An appendix of the HTML report, with its tables
+type htmlAppendix struct {
+	Letter  string
+	Title   string
+	Skipped bool
+	Entries int
+	Tables  []htmlTable
+}
+
+// A table of an appendix, or what it means that it has no rows
+type htmlTable struct {
+	Caption string
+	Label   string // What the filter of the table is labelled with
+	Columns []string
+	Rows    [][]htmlCell
+	Empty   string
+}
+
+// A cell of a table. A cell with a code point has its character with
+// ShowGlyphs, and the details of the code point, shown when it is expanded.
+// Names and characters are isolated in <bdi>, so that those written from
+// right to left do not reorder the text around them.
+type htmlCell struct {
+	Text    string
+	Glyph   string
+	Isolate bool
+	Details []htmlDetail
+	Chart   string
+}
+
+// A detail of a code point
+type htmlDetail struct {
+	Label   string
+	Value   string
+	Isolate bool
+}
+
+// Renders the report as a standalone HTML page, for reviewers who would
+// rather browse the changes than read the text report: the summary, and the
+// tables of each appendix, which can be sorted by clicking a column and
+// filtered by text. Each code point expands to what the report has on it, with a link to
+// its code chart when known.
+func RenderHTML(w io.Writer, r *Report, opts RenderOptions) error {
+	var summary strings.Builder
+	renderSummary(&summary, r, opts.Glossary["html"])
+	page := htmlReport{Report: r, Summary: summary.String()}
+	details := htmlDetails(r)
+
+	for _, appendix := range reportAppendices(r) {
+		a := htmlAppendix{Letter: appendix.letter, Title: appendix.heading(), Skipped: r.Skipped(appendix.letter), Entries: appendix.entries()}
+		for _, table := range appendix.tables() {
+			if len(table.rows) == 0 && table.empty == "" {
+				continue
+			}
+			t := htmlTable{Caption: table.caption, Label: a.Title, Columns: table.columns, Empty: table.empty}
+			if table.caption != "" {
+				t.Label += ", " + table.caption
+			}
+			for _, row := range table.rows {
+				cells := make([]htmlCell, len(row.cells))
+				for i, cell := range row.cells {
+					cells[i] = htmlCell{Text: cell, Isolate: isNameColumn(table.columns[i])}
+				}
+				if row.codepoint != "" {
+					cells[0] = htmlCell{Text: "U+" + row.codepoint, Glyph: opts.glyph(row.codepoint), Details: details[row.codepoint], Chart: ChartURL(r, row.codepoint)}
+				}
+				t.Rows = append(t.Rows, cells)
+			}
+			a.Tables = append(a.Tables, t)
+		}
+		page.Appendices = append(page.Appendices, a)
+	}
+	return htmlReportTemplate.Execute(w, page)
+}
+
+// Returns what the report has on each code point in the appendices A-E
+func htmlDetails(r *Report) map[string][]htmlDetail {
+	details := make(map[string][]htmlDetail)
+	add := func(codepoint, label, value string) {
+		details[codepoint] = append(details[codepoint], htmlDetail{label, value, label == "Name"})
+	}
+	names := make(map[string]string)
+	for _, change := range r.AppendixA {
+		names[change.CodePoint] = change.Name
+	}
+	for _, change := range r.AppendixB {
+		names[change.CodePoint] = change.Name
+	}
+	for _, entry := range r.AppendixC {
+		names[entry.CodePoint] = entry.Name
+	}
+	for _, entry := range r.AppendixD {
+		names[entry.CodePoint] = entry.Name
+	}
+	listed := listedCodePoints(r)
+	for _, codepoint := range listed {
+		add(codepoint, "Name", names[codepoint])
+	}
+
+	for _, delta := range r.Delta {
+		for codepointInt := hexToInt(delta.Start); codepointInt <= hexToInt(delta.End); codepointInt++ {
+			codepoint := codepointKey(codepointInt)
+			if _, ok := names[codepoint]; ok {
+				add(codepoint, "Derived property value", delta.Old+" to "+delta.New)
+			}
+		}
+	}
+	for _, change := range r.AppendixB {
+		add(change.CodePoint, "General Category", change.Old+" to "+change.New)
+	}
+	for _, entry := range r.AppendixD {
+		add(entry.CodePoint, "NFK normalization", entry.NFK)
+	}
+	appendices := make(map[string][]string)
+	listedIn := func(letter string, codepoint string) {
+		if letters := appendices[codepoint]; len(letters) == 0 || letters[len(letters)-1] != letter {
+			appendices[codepoint] = append(letters, letter)
+		}
+	}
+	for _, change := range r.AppendixA {
+		listedIn("A", change.CodePoint)
+	}
+	for _, change := range r.AppendixB {
+		listedIn("B", change.CodePoint)
+	}
+	for _, entry := range r.AppendixC {
+		listedIn("C", entry.CodePoint)
+	}
+	for _, entry := range r.AppendixD {
+		listedIn("D", entry.CodePoint)
+	}
+	for _, entry := range r.AppendixE {
+		listedIn("E", entry.CodePoint)
+	}
+	for _, codepoint := range listed {
+		add(codepoint, "Appendices", strings.Join(appendices[codepoint], ", "))
+	}
+	for _, s := range r.Snapshots {
+		add(s.CodePoint, "Script", s.Script)
+		add(s.CodePoint, "Bidi_Class", s.BidiClass)
+		add(s.CodePoint, "Canonical_Combining_Class", s.CombiningClass)
+		add(s.CodePoint, "Age", s.Age)
+		add(s.CodePoint, "Block", s.Block)
+	}
+	return details
+}
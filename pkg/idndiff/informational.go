@@ -0,0 +1,87 @@
+package idndiff
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Code points whose derived property value did not change although other
+// properties that it is derived from did, with the reason it held
+type Informational struct {
+	Entries []NotableCodePoint `json:"entries"`
+}
+
+// A code point whose derived property value held despite a related change
+type NotableCodePoint struct {
+	CodePoint string `json:"code_point"`
+	// What changed: general_category or nfk, as in the change events of history
+	Change   string `json:"change"`
+	Old      string `json:"old"`
+	New      string `json:"new"`
+	Property string `json:"property"`
+	Reason   string `json:"reason"`
+	Name     string `json:"name"`
+}
+
+// Reports whether a General Category is one of those of LetterDigits (A)
+func isLetterDigit(category string) bool {
+	switch category {
+	case "Ll", "Lu", "Lo", "Nd", "Lm", "Mn", "Mc":
+		return true
+	}
+	return false
+}
+
+// Returns why the derived property value of a code point held, if Exceptions
+// (F) or BackwardCompatible (G) fix it
+func fixedValueReason(tables RFC5892Tables, codepoint string) (string, bool) {
+	if value, ok := tables.BackwardCompatible[codepoint]; ok {
+		return fmt.Sprintf("BackwardCompatible (G) keeps it %s", value), true
+	}
+	if value, ok := tables.Exceptions[codepoint]; ok {
+		return fmt.Sprintf("Exceptions (F) gives it %s", value), true
+	}
+	return "", false
+}
+
+// Finds the code points in Appendix B and with NFK changes whose derived
+// property value did not change, and explains why
+func findUnchangedNotable(r *Report, codePointNames map[string]string, tables RFC5892Tables) *Informational {
+	informational := &Informational{}
+	for _, change := range r.AppendixB {
+		if change.OldProperty != change.NewProperty {
+			continue
+		}
+		reason, fixed := fixedValueReason(tables, change.CodePoint)
+		switch {
+		case fixed:
+		case isLetterDigit(change.Old) && isLetterDigit(change.New):
+			reason = fmt.Sprintf("both %s and %s are in LetterDigits (A)", change.Old, change.New)
+		case !isLetterDigit(change.Old) && !isLetterDigit(change.New):
+			reason = fmt.Sprintf("neither %s nor %s is in LetterDigits (A)", change.Old, change.New)
+		default:
+			reason = "a rule before LetterDigits (A) decides its derived property value"
+		}
+		informational.Entries = append(informational.Entries, NotableCodePoint{change.CodePoint, "general_category", change.Old, change.New, change.NewProperty, reason, change.Name})
+	}
+
+	for _, change := range r.NFKChanges {
+		if change.OldProperty != change.NewProperty {
+			continue
+		}
+		reason, fixed := fixedValueReason(tables, change.CodePoint)
+		switch {
+		case fixed:
+		case change.Old != change.CodePoint && change.New != change.CodePoint:
+			reason = "it has an NFK normalization in both versions"
+		default:
+			reason = "its derived property value does not depend on the normalization"
+		}
+		informational.Entries = append(informational.Entries, NotableCodePoint{change.CodePoint, "nfk", change.Old, change.New, change.NewProperty, reason, codePointNames[change.CodePoint]})
+	}
+
+	sort.SliceStable(informational.Entries, func(i, j int) bool {
+		return hexToInt(informational.Entries[i].CodePoint) < hexToInt(informational.Entries[j].CodePoint)
+	})
+	return informational
+}
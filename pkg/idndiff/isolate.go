@@ -0,0 +1,38 @@
+package idndiff
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/bidi"
+)
+
+// Reports whether text needs to be isolated: it has characters with a
+// right-to-left Bidi_Class, which written next to the left-to-right text of
+// a report reorder the text around them when shown, or pasted into a
+// document (UAX #9 section 2.7), or characters that control the direction of
+// text
+func needsIsolation(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool {
+		properties, _ := bidi.LookupRune(r)
+		if class := properties.Class(); class == bidi.R || class == bidi.AL {
+			return true
+		}
+		return unicode.Is(unicode.Bidi_Control, r)
+	}) >= 0
+}
+
+// Isolates text that needs it between FIRST STRONG ISOLATE and POP
+// DIRECTIONAL ISOLATE, for plain text and Markdown. HTML uses <bdi> instead.
+func isolate(s string) string {
+	if !needsIsolation(s) {
+		return s
+	}
+	return "\u2068" + s + "\u2069"
+}
+
+// Reports whether a column of the tables of the appendices has the names of
+// code points, which are isolated
+func isNameColumn(column string) bool {
+	return column == "Name" || column == "Names"
+}
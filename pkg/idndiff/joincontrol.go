@@ -0,0 +1,48 @@
+package idndiff
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"sort"
+)
+
+// The Join_Control code points, ZERO WIDTH NON-JOINER and ZERO WIDTH JOINER,
+// that the CONTEXTJ rules of RFC 5892 Appendix A.1 and A.2 are written for
+var expectedJoinControl = []string{"200C", "200D"}
+
+// Checks that the Join_Control code points of both versions are exactly
+// expectedJoinControl, returning them if they are not. Nothing is checked
+// without PropList.txt for both versions.
+func checkJoinControl(loader *Loader, version1, version2 string, codePointNames1, codePointNames2 map[string]string) (*JoinControlAlert, error) {
+	var sets [2][]CodePoint
+	for i, version := range []string{version1, version2} {
+		joinControl, err := loader.BinaryProperty(version, "PropList.txt", "Join_Control")
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", loader.Path(version, "PropList.txt"), err)
+		}
+		names := []map[string]string{codePointNames1, codePointNames2}[i]
+		for codepoint := range joinControl {
+			sets[i] = append(sets[i], CodePoint{codepoint, names[codepoint]})
+		}
+		sort.Slice(sets[i], func(a, b int) bool {
+			return hexToInt(sets[i][a].CodePoint) < hexToInt(sets[i][b].CodePoint)
+		})
+	}
+
+	codepoints := func(set []CodePoint) []string {
+		var list []string
+		for _, entry := range set {
+			list = append(list, entry.CodePoint)
+		}
+		return list
+	}
+	if slices.Equal(codepoints(sets[0]), expectedJoinControl) && slices.Equal(codepoints(sets[1]), expectedJoinControl) {
+		return nil, nil
+	}
+	return &JoinControlAlert{sets[0], sets[1]}, nil
+}
@@ -0,0 +1,63 @@
+package idndiff
+
+import "fmt"
+
+// Assigned code points whose Joining_Type changed, if requested
+type JoiningTypeChanges struct {
+	Changes []JoiningTypeChange `json:"changes"`
+}
+
+// A change of Joining_Type, with the derived property values of the code
+// point
+type JoiningTypeChange struct {
+	CodePoint   string `json:"code_point"`
+	Old         string `json:"old"`
+	New         string `json:"new"`
+	OldProperty string `json:"old_property"`
+	NewProperty string `json:"new_property"`
+	Name        string `json:"name"`
+}
+
+// Returns the Joining_Type of a code point from DerivedJoiningType.txt.
+// Code points that are not listed have the default, U (Non_Joining).
+func joiningTypeOf(joiningTypes map[string]string, codepoint string) string {
+	if joiningType, ok := joiningTypes[codepoint]; ok {
+		return joiningType
+	}
+	return "U"
+}
+
+// Finds the code points assigned in both versions whose Joining_Type
+// changed. The CONTEXTJ rule for U+200C ZERO WIDTH NON-JOINER in Appendix A.1
+// of RFC 5892 is defined by Joining_Type, so such changes can make labels
+// with it valid or invalid.
+func compareJoiningTypes(codepoints []int, properties1, properties2, codePointNames2 map[string]string, joiningTypes1, joiningTypes2 map[string]string) []JoiningTypeChange {
+	var changes []JoiningTypeChange
+	for _, codepointInt := range codepoints {
+		codepoint := codepointKey(codepointInt)
+		oldProperty, existedBefore := properties1[codepoint]
+		newProperty := properties2[codepoint]
+		if !existedBefore || oldProperty == "UNASSIGNED" || newProperty == "UNASSIGNED" {
+			continue
+		}
+		old, new := joiningTypeOf(joiningTypes1, codepoint), joiningTypeOf(joiningTypes2, codepoint)
+		if old != new {
+			changes = append(changes, JoiningTypeChange{codepoint, old, new, oldProperty, newProperty, codePointNames2[codepoint]})
+		}
+	}
+	return changes
+}
+
+// Reads DerivedJoiningType.txt of both versions and compares the
+// Joining_Type
+func joiningTypeSection(loader *Loader, version1, version2 string, codepoints []int, properties1, properties2, codePointNames2 map[string]string) (*JoiningTypeChanges, error) {
+	joiningTypes1, err := loader.PropertyFile(version1, "DerivedJoiningType.txt")
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version1, "DerivedJoiningType.txt"), err)
+	}
+	joiningTypes2, err := loader.PropertyFile(version2, "DerivedJoiningType.txt")
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version2, "DerivedJoiningType.txt"), err)
+	}
+	return &JoiningTypeChanges{compareJoiningTypes(codepoints, properties1, properties2, codePointNames2, joiningTypes1, joiningTypes2)}, nil
+}
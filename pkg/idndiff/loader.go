@@ -0,0 +1,513 @@
+package idndiff
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Loader reads the data files for each version of Unicode from a base
+// directory with one subdirectory, or one zip archive such as UCD.zip named
+// <version>.zip, per version. The base directory is read through FS when set,
+// and can also be an http(s) URL, in which case the files are fetched with
+// Client. Parsed files are cached, and a Loader is safe for concurrent use.
+type Loader struct {
+	BaseDir string
+	FS      fs.FS        // nil means the local directory BaseDir
+	Client  *http.Client // nil means http.DefaultClient
+	// Unstable (B) as the literal toNFKC(toCaseFold(toNFKC(cp))) != cp of
+	// RFC 5892 section 2.2, rather than Changes_When_NFKC_Casefolded
+	LiteralUnstable bool
+	// What to do with a code point that an input file lists more than once,
+	// as concatenated files do: "last" uses the last entry, the default,
+	// "first" the first one, and "error" fails. It is to be set before the
+	// Loader reads any file, as the files are parsed once.
+	DuplicatePolicy string
+	// The longest line allowed in the data files, or 0 for
+	// DefaultMaxLineSize
+	MaxLineSize int
+	// Exceptions (F), BackwardCompatible (G) and IgnorableBlocks (D) to
+	// derive the property values with, the embedded ones by default
+	Tables RFC5892Tables
+
+	mu    sync.Mutex
+	cache map[string]*cacheEntry
+	// Code points listed more than once, by version and file
+	duplicates map[string]map[string][]string
+	// The files read, by path
+	inputs map[string]InputFile
+}
+
+// A data file that was read, with its SHA-256 checksum
+type InputFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// A parsed file, loaded at most once
+type cacheEntry struct {
+	once  sync.Once
+	value any
+	err   error
+}
+
+// Creates a Loader reading from baseDir
+func NewLoader(baseDir string) *Loader {
+	return &Loader{BaseDir: baseDir}
+}
+
+// Creates a Loader reading from a file system, such as an embedded one or
+// fstest.MapFS
+func NewLoaderFS(fsys fs.FS) *Loader {
+	return &Loader{BaseDir: ".", FS: fsys}
+}
+
+// Returns the location of a file for a version, for use in messages
+func (l *Loader) Path(version, name string) string {
+	version = dataVersion(version)
+	if l.FS == nil && l.isRemote() {
+		return strings.TrimSuffix(l.BaseDir, "/") + "/" + version + "/" + name
+	}
+	return filepath.Join(l.BaseDir, version, name)
+}
+
+func (l *Loader) isRemote() bool {
+	return strings.HasPrefix(l.BaseDir, "http://") || strings.HasPrefix(l.BaseDir, "https://")
+}
+
+// Returns the file system with the files for a version: the zip archive
+// <version>.zip if there is one, otherwise the subdirectory <version>
+func (l *Loader) versionFS(version string) (fs.FS, error) {
+	fsys := l.FS
+	if fsys == nil {
+		fsys = os.DirFS(l.BaseDir)
+	}
+	if _, err := fs.Stat(fsys, version+".zip"); err != nil {
+		return fs.Sub(fsys, version)
+	}
+	value, err := l.cached(version+".zip", func() (any, error) {
+		data, err := fs.ReadFile(fsys, version+".zip")
+		if err != nil {
+			return nil, err
+		}
+		return zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*zip.Reader), nil
+}
+
+// Opens a file for a version. Files that are not found are also looked for in
+// the subdirectories extracted/ and emoji/, which is where UCD.zip keeps the
+// files derived from UnicodeData.txt and the emoji properties.
+func (l *Loader) open(version, name string) (io.ReadCloser, error) {
+	r, err := l.openFile(version, name)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.New()
+	return &checksummedFile{Reader: io.TeeReader(r, hash), file: r, hash: hash, path: l.Path(version, name), loader: l}, nil
+}
+
+// Opens a file for a version without recording it
+func (l *Loader) openFile(version, name string) (io.ReadCloser, error) {
+	version = dataVersion(version)
+	if l.FS != nil || !l.isRemote() {
+		fsys, err := l.versionFS(version)
+		if err != nil {
+			return nil, err
+		}
+		file, err := fsys.Open(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			for _, dir := range []string{"extracted/", "emoji/"} {
+				if subdirFile, err := fsys.Open(dir + name); err == nil {
+					return subdirFile, nil
+				}
+			}
+		}
+		return file, err
+	}
+
+	client := l.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Get(l.Path(version, name))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected HTTP status %s: %w", resp.Status, fs.ErrNotExist)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected HTTP status %s", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// A data file being read, whose checksum the Loader records when it is
+// closed
+type checksummedFile struct {
+	io.Reader
+	file   io.ReadCloser
+	hash   hash.Hash
+	size   int64
+	path   string
+	loader *Loader
+}
+
+// Returns the longest line allowed in the data files
+func (l *Loader) maxLineSize() int {
+	if l.MaxLineSize > 0 {
+		return l.MaxLineSize
+	}
+	return DefaultMaxLineSize
+}
+
+func (f *checksummedFile) maxLineSize() int {
+	return f.loader.maxLineSize()
+}
+
+func (f *checksummedFile) Read(p []byte) (int, error) {
+	n, err := f.Reader.Read(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Reads the rest of the file, so that the checksum is of all of it, records
+// it and closes the file
+func (f *checksummedFile) Close() error {
+	if _, err := io.Copy(io.Discard, f); err == nil {
+		f.loader.mu.Lock()
+		if f.loader.inputs == nil {
+			f.loader.inputs = make(map[string]InputFile)
+		}
+		f.loader.inputs[f.path] = InputFile{f.path, f.size, hex.EncodeToString(f.hash.Sum(nil))}
+		f.loader.mu.Unlock()
+	}
+	return f.file.Close()
+}
+
+// Returns the files read so far, sorted by path
+func (l *Loader) Inputs() []InputFile {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	inputs := make([]InputFile, 0, len(l.inputs))
+	for _, input := range l.inputs {
+		inputs = append(inputs, input)
+	}
+	sort.Slice(inputs, func(i, j int) bool { return inputs[i].Path < inputs[j].Path })
+	return inputs
+}
+
+// Returns the cached value for key, calling load to produce it the first time
+func (l *Loader) cached(key string, load func() (any, error)) (any, error) {
+	l.mu.Lock()
+	if l.cache == nil {
+		l.cache = make(map[string]*cacheEntry)
+	}
+	entry, ok := l.cache[key]
+	if !ok {
+		entry = &cacheEntry{}
+		l.cache[key] = entry
+	}
+	l.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.value, entry.err = load()
+	})
+	return entry.value, entry.err
+}
+
+// Reads and caches a file for a version using parse
+func (l *Loader) load(version, name string, parse func(io.Reader) (any, error)) (any, error) {
+	return l.cached(version+"/"+name, func() (any, error) {
+		r, err := l.open(version, name)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		value, err := parse(r)
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("line longer than %d bytes: %w", l.maxLineSize(), err)
+		}
+		return value, err
+	})
+}
+
+// Returns the derived property values and the names of all code points in
+// allcodepoints.txt. The returned maps are shared and must not be modified.
+func (l *Loader) CodepointProperties(version string) (map[string]string, map[string]string, error) {
+	if version == RFC5892Baseline {
+		return l.rfc5892Table()
+	}
+	type result struct{ properties, names map[string]string }
+	value, err := l.load(version, "allcodepoints.txt", func(r io.Reader) (any, error) {
+		dups := l.newDuplicates()
+		properties, names, err := readCodepointProperties(r, &dups)
+		l.recordDuplicates(version, "allcodepoints.txt", dups)
+		return result{properties, names}, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return value.(result).properties, value.(result).names, nil
+}
+
+// Returns the property value per code point from a UCD property file such as
+// DerivedGeneralCategory.txt. The returned map is shared and must not be
+// modified.
+func (l *Loader) PropertyFile(version, name string) (map[string]string, error) {
+	value, err := l.load(version, name, func(r io.Reader) (any, error) {
+		dups := l.newDuplicates()
+		properties, err := readPropertyFile(r, &dups)
+		l.recordDuplicates(version, name, dups)
+		return properties, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(map[string]string), nil
+}
+
+// Returns the NFK data from nfk.txt. The returned map is shared and must not
+// be modified.
+func (l *Loader) NFKData(version string) (NFKData, error) {
+	value, err := l.load(version, "nfk.txt", func(r io.Reader) (any, error) {
+		dups := l.newDuplicates()
+		data, err := readNFKData(r, &dups)
+		l.recordDuplicates(version, "nfk.txt", dups)
+		return data, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(NFKData), nil
+}
+
+// Returns the entries of UnicodeData.txt. The returned map is shared and must
+// not be modified.
+func (l *Loader) UnicodeData(version string) (map[string]unicodeDataEntry, error) {
+	value, err := l.load(version, "UnicodeData.txt", func(r io.Reader) (any, error) {
+		return readUnicodeData(r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(map[string]unicodeDataEntry), nil
+}
+
+// Returns the code points with a binary property in a UCD file such as
+// PropList.txt, or with a value of a property, as in "NFKC_QC=N". The
+// returned map is shared and must not be modified.
+func (l *Loader) BinaryProperty(version, name, property string) (map[string]bool, error) {
+	value, err := l.cached(version+"/"+name+"#"+property, func() (any, error) {
+		r, err := l.open(version, name)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return readBinaryProperty(r, property)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(map[string]bool), nil
+}
+
+// Reports whether nfk.txt of a version was written by WriteNFKTable, from its
+// first line
+func (l *Loader) nfkGenerated(version string) (bool, error) {
+	r, err := l.openFile(version, "nfk.txt")
+	if err != nil {
+		return false, err
+	}
+	defer r.Close()
+	scanner := newLineScanner(r)
+	if !scanner.Scan() {
+		return false, scanner.Err()
+	}
+	return scanner.Text() == generatedNFKHeader, nil
+}
+
+// Returns the full case folding from CaseFolding.txt. The returned map is
+// shared and must not be modified.
+func (l *Loader) CaseFolding(version string) (NFKData, error) {
+	value, err := l.load(version, "CaseFolding.txt", func(r io.Reader) (any, error) {
+		return readCaseFolding(r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(NFKData), nil
+}
+
+// Returns the NFKC_Casefold mappings from DerivedNormalizationProps.txt. The
+// returned map is shared and must not be modified.
+func (l *Loader) NFKCCaseFold(version string) (NFKData, error) {
+	value, err := l.cached(version+"/DerivedNormalizationProps.txt#NFKC_CF", func() (any, error) {
+		r, err := l.open(version, "DerivedNormalizationProps.txt")
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return readNFKCCaseFold(r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(NFKData), nil
+}
+
+// Returns the part of the keys of derived data that tells apart how it is
+// derived: the interpretation of RFC 5892 and its tables
+func (l *Loader) derivationKey() string {
+	key := ""
+	if l.LiteralUnstable {
+		key += "#literal-unstable"
+	}
+	return key + l.Tables.cacheKey()
+}
+
+// Returns the tables of RFC 5892 the Loader derives the property values with
+func (l *Loader) tables() RFC5892Tables {
+	return l.Tables.withDefaults()
+}
+
+// Returns the properties that the categories of RFC 5892 are computed from
+func (l *Loader) DerivationData(version string) (*derivationData, error) {
+	key := version + "#derivation" + l.derivationKey()
+	value, err := l.cached(key, func() (any, error) {
+		d := derivationData{literalUnstable: l.LiteralUnstable, tables: l.tables()}
+		var err error
+		// Report the file that failed to load
+		wrap := func(name string, err error) error {
+			return fmt.Errorf("reading %s: %w", l.Path(version, name), err)
+		}
+		if d.generalCategory, err = l.PropertyFile(version, "DerivedGeneralCategory.txt"); err != nil {
+			return nil, wrap("DerivedGeneralCategory.txt", err)
+		}
+		if d.unstable, err = l.BinaryProperty(version, "DerivedNormalizationProps.txt", "Changes_When_NFKC_Casefolded"); err != nil {
+			return nil, wrap("DerivedNormalizationProps.txt", err)
+		}
+		// The short name of the property is used in the file
+		if len(d.unstable) == 0 {
+			if d.unstable, err = l.BinaryProperty(version, "DerivedNormalizationProps.txt", "CWKCF"); err != nil {
+				return nil, wrap("DerivedNormalizationProps.txt", err)
+			}
+		}
+		if d.defaultIgnorable, err = l.BinaryProperty(version, "DerivedCoreProperties.txt", "Default_Ignorable_Code_Point"); err != nil {
+			return nil, wrap("DerivedCoreProperties.txt", err)
+		}
+		if d.literalUnstable {
+			nfkcChanges, err := l.BinaryProperty(version, "DerivedNormalizationProps.txt", "NFKC_QC=N")
+			if err != nil {
+				return nil, wrap("DerivedNormalizationProps.txt", err)
+			}
+			d.unstable = literalUnstableCodePoints(d.unstable, d.defaultIgnorable, nfkcChanges)
+		}
+		if d.whiteSpace, err = l.BinaryProperty(version, "PropList.txt", "White_Space"); err != nil {
+			return nil, wrap("PropList.txt", err)
+		}
+		if d.noncharacter, err = l.BinaryProperty(version, "PropList.txt", "Noncharacter_Code_Point"); err != nil {
+			return nil, wrap("PropList.txt", err)
+		}
+		if d.joinControl, err = l.BinaryProperty(version, "PropList.txt", "Join_Control"); err != nil {
+			return nil, wrap("PropList.txt", err)
+		}
+		if d.blocks, err = l.PropertyFile(version, "Blocks.txt"); err != nil {
+			return nil, wrap("Blocks.txt", err)
+		}
+		if d.hangulSyllableType, err = l.PropertyFile(version, "HangulSyllableType.txt"); err != nil {
+			return nil, wrap("HangulSyllableType.txt", err)
+		}
+		return &d, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*derivationData), nil
+}
+
+// Returns the derived property values and the names of all code points,
+// computed from the UCD files by the rules of RFC 5892 as for
+// allcodepoints.txt. The returned maps are shared and must not be modified.
+func (l *Loader) DerivedProperties(version string) (map[string]string, map[string]string, error) {
+	type result struct{ properties, names map[string]string }
+	value, err := l.cached(version+"#derived"+l.derivationKey(), func() (any, error) {
+		derivation, err := l.DerivationData(version)
+		if err != nil {
+			return nil, err
+		}
+		unicodeData, err := l.UnicodeData(version)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", l.Path(version, "UnicodeData.txt"), err)
+		}
+		properties := make(map[string]string, codeSpaceSize)
+		names := make(map[string]string, codeSpaceSize)
+		for codepointInt := 0; codepointInt < codeSpaceSize; codepointInt++ {
+			codepoint := codepointKey(codepointInt)
+			entry, assigned := unicodeData[codepoint]
+			properties[codepoint] = derivation.property(codepointInt)
+			names[codepoint] = codepointName(codepointInt, entry, assigned)
+		}
+		return result{properties, names}, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return value.(result).properties, value.(result).names, nil
+}
+
+// Returns the aliases per code point from NameAliases.txt. The returned map
+// is shared and must not be modified.
+func (l *Loader) NameAliases(version string) (map[string][]nameAlias, error) {
+	value, err := l.load(version, "NameAliases.txt", func(r io.Reader) (any, error) {
+		return readNameAliases(r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(map[string][]nameAlias), nil
+}
+
+// Returns the UTS #46 status per code point from IdnaMappingTable.txt. The
+// returned map is shared and must not be modified.
+func (l *Loader) IdnaMappingTable(version string) (map[string]uts46Entry, error) {
+	value, err := l.load(version, "IdnaMappingTable.txt", func(r io.Reader) (any, error) {
+		return readIdnaMappingTable(r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(map[string]uts46Entry), nil
+}
+
+// Returns the prototype of each confusable code point from confusables.txt of
+// UTS #39. The returned map is shared and must not be modified.
+func (l *Loader) Confusables(version string) (map[string]string, error) {
+	value, err := l.load(version, "confusables.txt", func(r io.Reader) (any, error) {
+		return readConfusables(r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(map[string]string), nil
+}
@@ -0,0 +1,118 @@
+package idndiff
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// The properties of a code point that are tracked, in one version
+type CodePointProperties struct {
+	CodePoint       string `json:"code_point"`
+	Version         string `json:"version"`
+	Property        string `json:"property"`
+	GeneralCategory string `json:"general_category"`
+	NFK             string `json:"nfk"`
+	Name            string `json:"name"`
+}
+
+// Reads a list of code points, one per line, in hexadecimal after U+, as
+// in "U+00DF", or in decimal, as in "223". Text after "#" is a comment.
+func ReadCodePointList(r io.Reader) ([]int, error) {
+	var codepoints []int
+	scanner := newLineScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(strings.Split(scanner.Text(), "#")[0])
+		if line == "" {
+			continue
+		}
+		var codepoint int64
+		var err error
+		if hex, found := strings.CutPrefix(strings.ToUpper(line), "U+"); found {
+			codepoint, err = strconv.ParseInt(hex, 16, 32)
+		} else {
+			codepoint, err = strconv.ParseInt(line, 10, 32)
+		}
+		if err != nil || codepoint < 0 || codepoint > 0x10FFFF {
+			return nil, fmt.Errorf("line %d: invalid code point %q, expected U+ and hexadecimal digits, or a decimal number", lineNumber, line)
+		}
+		codepoints = append(codepoints, int(codepoint))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return codepoints, nil
+}
+
+// Looks up the tracked properties of code points in each of the versions,
+// with one entry per code point and version, in the order of the code points.
+// The General Category and NFK normalization are left empty for a version
+// without DerivedGeneralCategory.txt or nfk.txt.
+func Lookup(loader *Loader, versions []string, codepoints []int) ([]CodePointProperties, error) {
+	type versionTables struct {
+		properties, names, generalCategory map[string]string
+		nfk                                NFKData
+	}
+	tables := make([]versionTables, len(versions))
+	for i, version := range versions {
+		var err error
+		t := &tables[i]
+		t.properties, t.names, err = loader.CodepointProperties(version)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", loader.Path(version, "allcodepoints.txt"), err)
+		}
+		t.generalCategory, err = loader.PropertyFile(version, "DerivedGeneralCategory.txt")
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("reading %s: %w", loader.Path(version, "DerivedGeneralCategory.txt"), err)
+		}
+		t.nfk, err = loader.NFKData(version)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("reading %s: %w", loader.Path(version, "nfk.txt"), err)
+		}
+	}
+
+	var entries []CodePointProperties
+	for _, codepointInt := range codepoints {
+		codepoint := codepointKey(codepointInt)
+		for i, version := range versions {
+			t := tables[i]
+			entry := CodePointProperties{
+				CodePoint:       codepoint,
+				Version:         version,
+				Property:        t.properties[codepoint],
+				GeneralCategory: t.generalCategory[codepoint],
+				Name:            t.names[codepoint],
+			}
+			if t.nfk != nil {
+				entry.NFK = formatNFK(t.nfk.mapping(codepointInt))
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// Writes looked up properties as indented JSON
+func WriteLookupJSON(w io.Writer, entries []CodePointProperties) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(entries)
+}
+
+// Writes looked up properties as CSV, with a header line
+func WriteLookupCSV(w io.Writer, entries []CodePointProperties) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"code_point", "version", "property", "general_category", "nfk", "name"})
+	for _, entry := range entries {
+		writer.Write([]string{entry.CodePoint, entry.Version, entry.Property, entry.GeneralCategory, entry.NFK, entry.Name})
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,138 @@
+package idndiff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Renders the report as Markdown, for GitHub issues or kramdown-rfc drafts:
+// the summary, with tables of the counts, followed by a section per
+// appendix with its tables, each code point linked to its code chart when
+// known.
+func RenderMarkdown(w io.Writer, r *Report, opts RenderOptions) error {
+	var buffer strings.Builder
+	fmt.Fprintf(&buffer, "# Unicode %s compared with Unicode %s for IDNA2008\n\n", r.Version2, r.Version1)
+
+	fmt.Fprintf(&buffer, "## Summary\n\n")
+	for _, warning := range r.Warnings {
+		fmt.Fprintf(&buffer, "- **Warning:** %s\n", escapeMarkdown(warning))
+	}
+	for _, e := range r.Errors {
+		fmt.Fprintf(&buffer, "- **Error:** %s skipped: %s\n", e.Section, escapeMarkdown(e.Error))
+	}
+	if r.JoinControl != nil {
+		fmt.Fprintf(&buffer, "- **Alert:** Join_Control is not only U+200C and U+200D, which the CONTEXTJ rules of RFC 5892 assume\n")
+	}
+	if len(r.Warnings) > 0 || len(r.Errors) > 0 || r.JoinControl != nil {
+		fmt.Fprintf(&buffer, "\n")
+	}
+
+	if !r.Skipped("A") {
+		fmt.Fprintf(&buffer, "| Version | CONTEXTJ | CONTEXTO | General_Category Mn |\n|---|---|---|---|\n")
+		fmt.Fprintf(&buffer, "| %s | %d | %d | %d |\n", r.Version1, r.ContextRules.ContextJ1, r.ContextRules.ContextO1, r.MnCount1)
+		fmt.Fprintf(&buffer, "| %s | %d | %d | %d |\n\n", r.Version2, r.ContextRules.ContextJ2, r.ContextRules.ContextO2, r.MnCount2)
+	}
+
+	appendices := reportAppendices(r)
+	fmt.Fprintf(&buffer, "| Appendix | Entries | Contents |\n|---|---|---|\n")
+	for _, appendix := range appendices {
+		fmt.Fprintf(&buffer, "| %s | %s | %s |\n", appendix.letter, entries(r, appendix.letter, appendix.entries()), escapeMarkdown(appendix.title))
+	}
+	var counts strings.Builder
+	renderSectionCounts(&counts, r)
+	if counts.Len() > 0 {
+		fmt.Fprintf(&buffer, "\n")
+		for _, line := range strings.Split(strings.TrimSuffix(counts.String(), "\n"), "\n") {
+			fmt.Fprintf(&buffer, "- %s\n", escapeMarkdown(line))
+		}
+	}
+	if r.Narrative != nil {
+		fmt.Fprintf(&buffer, "\n## Changes in Unicode %s affecting IDNA\n\n%s\n", r.Version2, escapeMarkdown(r.Narrative.Text))
+	}
+
+	for _, appendix := range appendices {
+		fmt.Fprintf(&buffer, "\n## %s\n\n", escapeMarkdown(appendix.heading()))
+		if r.Skipped(appendix.letter) {
+			fmt.Fprintf(&buffer, "Skipped, see the warnings in the summary.\n")
+			continue
+		}
+		written := 0
+		for _, table := range appendix.tables() {
+			if len(table.rows) == 0 && table.empty == "" {
+				continue
+			}
+			if written > 0 {
+				fmt.Fprintf(&buffer, "\n")
+			}
+			written++
+			if table.caption != "" {
+				fmt.Fprintf(&buffer, "%s:\n\n", escapeMarkdown(table.caption))
+			}
+			t := &markdownTable{r: r, opts: opts, letter: appendix.letter, buffer: &buffer, columns: table.columns, limit: opts.MaxEntries}
+			if table.summary {
+				t.limit = 0
+			}
+			for _, row := range table.rows {
+				t.row(row)
+			}
+			t.end(table.empty)
+		}
+	}
+	_, err := io.WriteString(w, buffer.String())
+	return err
+}
+
+// A table of an appendix in Markdown, with at most limit rows unless it is 0
+type markdownTable struct {
+	r       *Report
+	opts    RenderOptions
+	letter  string
+	buffer  *strings.Builder
+	columns []string
+	limit   int
+	rows    int
+}
+
+// Writes a row of the table, unless there are limit rows already. The code
+// point is linked to its code chart, and the other cells are escaped.
+func (t *markdownTable) row(row appendixRow) {
+	t.rows++
+	if t.limit > 0 && t.rows > t.limit {
+		return
+	}
+	if t.rows == 1 {
+		fmt.Fprintf(t.buffer, "| %s |\n|%s\n", strings.Join(t.columns, " | "), strings.Repeat("---|", len(t.columns)))
+	}
+	cells := make([]string, len(row.cells))
+	for i, cell := range row.cells {
+		if isNameColumn(t.columns[i]) {
+			cell = isolate(cell)
+		}
+		cells[i] = escapeMarkdown(cell)
+	}
+	if row.codepoint != "" {
+		cells[0] = markdownCodePoint(t.r, t.opts, row.codepoint)
+	}
+	fmt.Fprintf(t.buffer, "| %s |\n", strings.Join(cells, " | "))
+}
+
+// Ends the table, with what it means if it is empty or a note if some rows
+// were left out
+func (t *markdownTable) end(empty string) {
+	switch {
+	case t.rows == 0:
+		fmt.Fprintf(t.buffer, "%s.\n", empty)
+	case t.limit > 0 && t.rows > t.limit:
+		fmt.Fprintf(t.buffer, "\n%d more, all %d entries are in %s.\n", t.rows-t.limit, t.rows, t.opts.OverflowFileName(t.letter))
+	}
+}
+
+// Returns a code point for a table, linked to its code chart when known
+func markdownCodePoint(r *Report, opts RenderOptions, codepoint string) string {
+	label := escapeMarkdown(opts.codePointLabel(codepoint))
+	if chart := ChartURL(r, codepoint); chart != "" {
+		return fmt.Sprintf("[%s](%s)", label, chart)
+	}
+	return label
+}
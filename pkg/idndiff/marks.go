@@ -0,0 +1,121 @@
+package idndiff
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// How a new code point with General Category Mn is likely rendered
+const (
+	// Takes up space beside its base, as its Indic_Positional_Category is
+	// left or right of it, so it may be mistaken for a letter
+	RenderingSpacing = "spacing"
+	// Sits above, below or over its base without taking up space
+	RenderingNonspacing = "nonspacing"
+	// Not shown at all, as an invisible stacker that only changes how the
+	// letters around it are shaped
+	RenderingInvisible = "invisible"
+	// Nothing in the data tells
+	RenderingUnknown = "unknown"
+)
+
+// How the new code points with General Category Mn in Appendix C are likely
+// rendered, for the recurring question in reviews of whether new marks can
+// be used for spoofing
+type MarkRendering struct {
+	Entries []MarkAnnotation `json:"entries"`
+}
+
+// A code point of Appendix C with what its rendering is guessed from
+type MarkAnnotation struct {
+	CodePoint string `json:"code_point"`
+	Rendering string `json:"rendering"`
+	// The Indic_Syllabic_Category and Indic_Positional_Category, Other and
+	// NA for code points the files do not list
+	SyllabicCategory   string `json:"indic_syllabic_category"`
+	PositionalCategory string `json:"indic_positional_category"`
+	CombiningClass     string `json:"combining_class"`
+	// The script, and the scripts the mark is also used with as listed in
+	// ScriptExtensions.txt, if the files are there
+	Script           string `json:"script,omitempty"`
+	ScriptExtensions string `json:"script_extensions,omitempty"`
+	Name             string `json:"name"`
+}
+
+// Guesses how a mark is rendered from its Indic_Syllabic_Category,
+// Indic_Positional_Category and Canonical_Combining_Class. A mark to the left
+// or right of its base, such as a dependent vowel, takes up space even though
+// its General Category is Mn.
+func markRendering(syllabic, positional, combiningClass string) string {
+	switch {
+	case syllabic == "Invisible_Stacker":
+		return RenderingInvisible
+	case strings.Contains(positional, "Left") || strings.Contains(positional, "Right"):
+		return RenderingSpacing
+	case positional == "Top" || positional == "Bottom" || positional == "Top_And_Bottom" || positional == "Overstruck":
+		return RenderingNonspacing
+	case combiningClass != "0" && combiningClass != "":
+		// Positioned relative to the base by its combining class
+		return RenderingNonspacing
+	}
+	return RenderingUnknown
+}
+
+// Reads the Indic categories, the combining classes and the scripts of the
+// second version, and annotates each code point of Appendix C with how it is
+// likely rendered. Scripts.txt and ScriptExtensions.txt are optional.
+func markRenderingSection(loader *Loader, version2 string, entries []CodePoint) (*MarkRendering, error) {
+	unicodeData2, err := loader.UnicodeData(version2)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version2, "UnicodeData.txt"), err)
+	}
+	tables := make(map[string]map[string]string)
+	for _, file := range []struct {
+		name     string
+		optional bool
+	}{
+		{"IndicSyllabicCategory.txt", false},
+		{"IndicPositionalCategory.txt", false},
+		{"Scripts.txt", true},
+		{"ScriptExtensions.txt", true},
+	} {
+		table, err := loader.PropertyFile(version2, file.name)
+		if err != nil && !(file.optional && errors.Is(err, fs.ErrNotExist)) {
+			return nil, fmt.Errorf("reading %s: %w", loader.Path(version2, file.name), err)
+		}
+		tables[file.name] = table
+	}
+
+	rendering := &MarkRendering{Entries: []MarkAnnotation{}}
+	for _, entry := range entries {
+		annotation := MarkAnnotation{
+			CodePoint:          entry.CodePoint,
+			SyllabicCategory:   tables["IndicSyllabicCategory.txt"][entry.CodePoint],
+			PositionalCategory: tables["IndicPositionalCategory.txt"][entry.CodePoint],
+			CombiningClass:     unicodeData2[entry.CodePoint].CombiningClass,
+			Script:             tables["Scripts.txt"][entry.CodePoint],
+			ScriptExtensions:   tables["ScriptExtensions.txt"][entry.CodePoint],
+			Name:               entry.Name,
+		}
+		if annotation.SyllabicCategory == "" {
+			annotation.SyllabicCategory = "Other"
+		}
+		if annotation.PositionalCategory == "" {
+			annotation.PositionalCategory = "NA"
+		}
+		annotation.Rendering = markRendering(annotation.SyllabicCategory, annotation.PositionalCategory, annotation.CombiningClass)
+		rendering.Entries = append(rendering.Entries, annotation)
+	}
+	return rendering, nil
+}
+
+// Returns the number of annotated code points with each rendering
+func (m *MarkRendering) counts() map[string]int {
+	counts := make(map[string]int)
+	for _, entry := range m.Entries {
+		counts[entry.Rendering]++
+	}
+	return counts
+}
@@ -0,0 +1,101 @@
+package idndiff
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+)
+
+// The template of the HTML matrix
+//
+//go:embed data/matrix.html.tmpl
+var matrixTemplate string
+
+var htmlMatrixTemplate = template.Must(template.New("matrix").Parse(matrixTemplate))
+
+// The number of code points whose derived property value changed between
+// each pair of versions, as listed in Appendix A of the report comparing
+// them. Code points that were not assigned in the older version are not
+// counted.
+type Matrix struct {
+	Versions []string `json:"versions"`
+	// Changes[i][j] is the number of changes between Versions[i] and
+	// Versions[j], the same both ways, and zero when i is j
+	Changes [][]int `json:"changes"`
+}
+
+// Compares every pair of versions, which are in order from the oldest, and
+// returns the number of changes between them
+func BuildMatrix(loader *Loader, versions []string, opts Options) (*Matrix, error) {
+	m := &Matrix{Versions: versions, Changes: make([][]int, len(versions))}
+	for i := range versions {
+		m.Changes[i] = make([]int, len(versions))
+	}
+	for i := range versions {
+		for j := i + 1; j < len(versions); j++ {
+			report, err := Compare(loader, versions[i], versions[j], opts)
+			if err != nil {
+				return nil, fmt.Errorf("comparing %s and %s: %w", versions[i], versions[j], err)
+			}
+			m.Changes[i][j] = len(report.AppendixA)
+			m.Changes[j][i] = len(report.AppendixA)
+		}
+	}
+	return m, nil
+}
+
+// Returns the largest number of changes in the matrix
+func (m *Matrix) max() int {
+	largest := 0
+	for _, row := range m.Changes {
+		for _, count := range row {
+			largest = max(largest, count)
+		}
+	}
+	return largest
+}
+
+// Writes the matrix as CSV, with the versions in the header line and the
+// first column
+func WriteMatrixCSV(w io.Writer, m *Matrix) error {
+	writer := csv.NewWriter(w)
+	writer.Write(append([]string{"version"}, m.Versions...))
+	for i, version := range m.Versions {
+		record := []string{version}
+		for _, count := range m.Changes[i] {
+			record = append(record, strconv.Itoa(count))
+		}
+		writer.Write(record)
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// A cell of the HTML matrix, shaded by its share of the largest count
+type htmlMatrixCell struct {
+	Count int
+	Shade template.CSS
+}
+
+// Writes the matrix as a standalone HTML page, with the cells shaded darker
+// the more changes there are, for presentations
+func WriteMatrixHTML(w io.Writer, m *Matrix) error {
+	largest := m.max()
+	rows := make([][]htmlMatrixCell, len(m.Versions))
+	for i, row := range m.Changes {
+		for _, count := range row {
+			share := 0.0
+			if largest > 0 {
+				share = float64(count) / float64(largest)
+			}
+			rows[i] = append(rows[i], htmlMatrixCell{count, template.CSS(fmt.Sprintf("background: rgba(204, 0, 0, %.2f)", share*0.6))})
+		}
+	}
+	return htmlMatrixTemplate.Execute(w, struct {
+		Versions []string
+		Rows     [][]htmlMatrixCell
+	}{m.Versions, rows})
+}
@@ -0,0 +1,94 @@
+package idndiff
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// The types of aliases in NameAliases.txt
+var aliasTypes = []string{"correction", "control", "alternate", "figment", "abbreviation"}
+
+// An alias of a code point from NameAliases.txt
+type nameAlias struct {
+	Alias string
+	Type  string
+}
+
+// Reads NameAliases.txt, with lines like "0000;NULL;control", returning the
+// aliases of each code point in the order of the file
+func readNameAliases(r io.Reader) (map[string][]nameAlias, error) {
+	aliases := make(map[string][]nameAlias)
+
+	scanner := newLineScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(strings.Split(scanner.Text(), "#")[0])
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, ";")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected 3 fields, found %d", lineNumber, len(fields))
+		}
+		codepoint, err := parseCodepoint(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		key := codepointKey(int(codepoint))
+		aliases[key] = append(aliases[key], nameAlias{strings.TrimSpace(fields[1]), strings.TrimSpace(fields[2])})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return aliases, nil
+}
+
+// Parses a comma separated list of alias types, in order of precedence
+func parseAliasTypes(spec string) ([]string, error) {
+	var types []string
+	for _, aliasType := range strings.Split(spec, ",") {
+		aliasType = strings.TrimSpace(aliasType)
+		if !slices.Contains(aliasTypes, aliasType) {
+			return nil, fmt.Errorf("unknown alias type %q, expected one of %s", aliasType, strings.Join(aliasTypes, ", "))
+		}
+		types = append(types, aliasType)
+	}
+	return types, nil
+}
+
+// Returns a copy of the names where placeholders such as "<control>" are
+// replaced by the first alias of the given types, in order of precedence.
+// Corrections, if among the types, also replace names that are not
+// placeholders, since they fix errors in the names that cannot be changed.
+func applyNameAliases(names map[string]string, aliases map[string][]nameAlias, types []string) map[string]string {
+	names = maps.Clone(names)
+	for codepoint, name := range names {
+		isPlaceholder := strings.HasPrefix(name, "<")
+		for _, aliasType := range types {
+			if !isPlaceholder && aliasType != "correction" {
+				continue
+			}
+			if alias, ok := findAlias(aliases[codepoint], aliasType); ok {
+				names[codepoint] = alias
+				break
+			}
+		}
+	}
+	return names
+}
+
+// Returns the first alias of a type
+func findAlias(aliases []nameAlias, aliasType string) (string, bool) {
+	for _, alias := range aliases {
+		if alias.Type == aliasType {
+			return alias.Alias, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,136 @@
+package idndiff
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// The default template of the narrative
+//
+//go:embed data/narrative.tmpl
+var narrativeTemplate string
+
+// What the narrative of a comparison is made from, the fields of the
+// template
+type NarrativeFacts struct {
+	Version1, Version2 string
+	Assigned           int      // Code points that were UNASSIGNED in the first version
+	NewScripts         []string // Scripts without code points in the first version, if Scripts.txt is there
+	NewPVALID          int      // Newly assigned code points that are PVALID
+	NewContext         int      // Newly assigned code points that are CONTEXTJ or CONTEXTO
+	NewDisallowed      int      // Newly assigned code points that are DISALLOWED
+	Changed            int      // Code points in Appendix A
+	FirstChange        string   // The first of them, as "U+0B55 from DISALLOWED to PVALID"
+	CategoryChanges    int      // Code points in Appendix B
+	NewMarks           int      // Code points in Appendix C
+	NewNormalized      int      // Code points in Appendix D
+	Candidates         int      // Code points in Appendix E
+}
+
+// Functions available in the template
+var narrativeFuncs = template.FuncMap{
+	// plural 2 "code point" "code points" gives "2 code points"
+	"plural": func(n int, singular, plural string) string {
+		if n == 1 {
+			return fmt.Sprintf("%d %s", n, singular)
+		}
+		return fmt.Sprintf("%d %s", n, plural)
+	},
+	// list gives "A", "A and B" or "A, B and C"
+	"list": func(items []string) string {
+		if len(items) < 2 {
+			return strings.Join(items, "")
+		}
+		return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+	},
+}
+
+// Writes the narrative of a comparison with the template in templatePath,
+// or the default one if it is empty
+func writeNarrative(loader *Loader, r *Report, templatePath string) (*Narrative, error) {
+	text := narrativeTemplate
+	if templatePath != "" {
+		data, err := os.ReadFile(templatePath)
+		if err != nil {
+			return nil, err
+		}
+		text = string(data)
+	}
+	tmpl, err := template.New("narrative").Funcs(narrativeFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	facts := NarrativeFacts{
+		Version1:        r.Version1,
+		Version2:        r.Version2,
+		Changed:         len(r.AppendixA),
+		CategoryChanges: len(r.AppendixB),
+		NewMarks:        len(r.AppendixC),
+		NewNormalized:   len(r.AppendixD),
+		Candidates:      len(r.AppendixE),
+	}
+	for _, change := range r.ChangeCounts {
+		if change.Old != "UNASSIGNED" {
+			continue
+		}
+		facts.Assigned += change.Count
+		switch change.New {
+		case "PVALID":
+			facts.NewPVALID += change.Count
+		case "CONTEXTJ", "CONTEXTO":
+			facts.NewContext += change.Count
+		case "DISALLOWED":
+			facts.NewDisallowed += change.Count
+		}
+	}
+	if len(r.AppendixA) > 0 {
+		change := r.AppendixA[0]
+		facts.FirstChange = fmt.Sprintf("U+%s from %s to %s", change.CodePoint, change.Old, change.New)
+	}
+	facts.NewScripts, err = newScripts(loader, r.Version1, r.Version2)
+	if err != nil {
+		return nil, err
+	}
+
+	var narrative strings.Builder
+	if err := tmpl.Execute(&narrative, facts); err != nil {
+		return nil, err
+	}
+	return &Narrative{facts.NewScripts, strings.TrimSpace(narrative.String())}, nil
+}
+
+// Returns the scripts with code points in the second version but not in the
+// first, or nothing without Scripts.txt for both versions
+func newScripts(loader *Loader, version1, version2 string) ([]string, error) {
+	var scripts [2]map[string]string
+	for i, version := range []string{version1, version2} {
+		var err error
+		scripts[i], err = loader.PropertyFile(version, "Scripts.txt")
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", loader.Path(version, "Scripts.txt"), err)
+		}
+	}
+	known := make(map[string]bool)
+	for _, script := range scripts[0] {
+		known[script] = true
+	}
+	var added []string
+	for _, script := range scripts[1] {
+		if !known[script] {
+			added = append(added, strings.ReplaceAll(script, "_", " "))
+			known[script] = true
+		}
+	}
+	slices.Sort(added)
+	return added, nil
+}
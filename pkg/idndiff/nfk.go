@@ -0,0 +1,146 @@
+package idndiff
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// The NFK normalization of a code point, as the code points it maps to
+type NFKMapping []rune
+
+// Reports whether the normalization of the code point is the code point itself
+func (m NFKMapping) mapsTo(codepointInt int) bool {
+	return len(m) == 1 && m[0] == rune(codepointInt)
+}
+
+// The NFK normalization per code point, as read from nfk.txt. Code points
+// that are not listed map to themselves.
+type NFKData map[string]NFKMapping
+
+// Returns the normalization of a code point. Code points that are not listed
+// map to themselves.
+func (d NFKData) mapping(codepointInt int) NFKMapping {
+	if m, ok := d[codepointKey(codepointInt)]; ok {
+		return m
+	}
+	return NFKMapping{rune(codepointInt)}
+}
+
+// Formats a normalization as space separated hexadecimal code points
+func formatNFK(m NFKMapping) string {
+	parts := make([]string, len(m))
+	for i, r := range m {
+		parts[i] = codepointKey(int(r))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Parses a hexadecimal code point, with or without U+ prefix
+func parseCodepoint(s string) (rune, error) {
+	value, ok := parseHex(strings.TrimPrefix(strings.TrimSpace(s), "U+"))
+	if !ok {
+		return 0, fmt.Errorf("invalid code point %q", s)
+	}
+	return rune(value), nil
+}
+
+// Reads NFK data from nfk.txt: the code point followed by the code points of
+// its normalization. The format of the common table generation scripts is
+// detected per line, so all of these are read the same way:
+//
+//	U+00BD;0031;2044;0032
+//	00BD ; 0031 2044 0032 # VULGAR FRACTION ONE HALF
+//	00BD	0031 2044 0032
+//	00BD>0031 2044 0032 (ICU gennorm2 files such as nfkc.txt)
+//
+// Lines with a canonical combining class in gennorm2 files ("0300:230"), and
+// header lines starting with "*", are skipped.
+func readNFKData(r io.Reader, dups *duplicates) (NFKData, error) {
+	data := make(NFKData)
+	scanner := newLineScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(strings.Split(scanner.Text(), "#")[0])
+		if line == "" || strings.HasPrefix(line, "*") {
+			continue
+		}
+		separator := strings.IndexAny(line, ";>=: \t")
+		if separator < 0 || line[separator] == ':' {
+			continue
+		}
+		codepoint, err := parseCodepoint(line[:separator])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		var mapping NFKMapping
+		for _, field := range strings.FieldsFunc(line[separator+1:], isNFKSeparator) {
+			if strings.HasPrefix(field, "<") {
+				// Decomposition tags such as <compat>
+				continue
+			}
+			target, err := parseCodepoint(field)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			mapping = append(mapping, target)
+		}
+		if len(mapping) == 0 {
+			return nil, fmt.Errorf("line %d: no normalization for U+%04X", lineNumber, codepoint)
+		}
+		if err := setEntry(data, codepointKey(int(codepoint)), mapping, dups); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Reports whether a character separates the code points of a normalization
+func isNFKSeparator(r rune) bool {
+	return r == ';' || r == '>' || r == '=' || r == ',' || unicode.IsSpace(r)
+}
+
+// Finds the code points that are PVALID in both versions whose normalization
+// changed such that it now includes code points with a derived property value
+// that was not part of the old normalization, for example DISALLOWED
+func findNFKHazards(codepoints []int, properties1, properties2, codePointNames2 map[string]string, nfk1, nfk2 NFKData) []NFKHazard {
+	var hazards []NFKHazard
+	for _, codepointInt := range codepoints {
+		codepoint := codepointKey(codepointInt)
+		if properties1[codepoint] != "PVALID" || properties2[codepoint] != "PVALID" {
+			continue
+		}
+		oldNFK := nfk1.mapping(codepointInt)
+		newNFK := nfk2.mapping(codepointInt)
+		if slices.Equal(oldNFK, newNFK) {
+			continue
+		}
+
+		// Derived property values in the old normalization
+		oldValues := make(map[string]bool)
+		for _, target := range oldNFK {
+			oldValues[properties1[codepointKey(int(target))]] = true
+		}
+
+		var targets []CodePointProperty
+		for _, target := range newNFK {
+			targetCodepoint := codepointKey(int(target))
+			property := properties2[targetCodepoint]
+			if property != "PVALID" && !oldValues[property] {
+				targets = append(targets, CodePointProperty{targetCodepoint, property})
+			}
+		}
+		if len(targets) > 0 {
+			hazards = append(hazards, NFKHazard{codepoint, formatNFK(oldNFK), formatNFK(newNFK), targets, codePointNames2[codepoint]})
+		}
+	}
+	return hazards
+}
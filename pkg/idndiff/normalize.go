@@ -0,0 +1,209 @@
+package idndiff
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// The constants of the algorithmic decomposition and composition of Hangul
+// syllables, from section 3.12 of the Unicode Standard
+const (
+	hangulSBase  = 0xAC00
+	hangulLBase  = 0x1100
+	hangulVBase  = 0x1161
+	hangulTBase  = 0x11A7
+	hangulLCount = 19
+	hangulVCount = 21
+	hangulTCount = 28
+	hangulNCount = hangulVCount * hangulTCount
+	hangulSCount = hangulLCount * hangulNCount
+)
+
+// Computes the NFKC normalization of code points from the decompositions and
+// canonical combining classes in UnicodeData.txt
+type normalizer struct {
+	decompositions map[rune][]rune // Full compatibility decomposition
+	combiningClass map[rune]int
+	compositions   map[[2]rune]rune // Primary composites by their two code points
+}
+
+// Creates a normalizer from UnicodeData.txt and the code points with
+// Full_Composition_Exclusion, which are never composed
+func newNormalizer(unicodeData map[string]unicodeDataEntry, exclusions map[string]bool) (*normalizer, error) {
+	n := &normalizer{make(map[rune][]rune), make(map[rune]int), make(map[[2]rune]rune)}
+	single := make(map[rune][]rune)
+	for codepoint, entry := range unicodeData {
+		codepointInt := rune(hexToInt(codepoint))
+		if entry.CombiningClass != "" && entry.CombiningClass != "0" {
+			ccc, err := strconv.Atoi(entry.CombiningClass)
+			if err != nil {
+				return nil, fmt.Errorf("U+%s: invalid canonical combining class %q", codepoint, entry.CombiningClass)
+			}
+			n.combiningClass[codepointInt] = ccc
+		}
+		if entry.Decomposition == "" {
+			continue
+		}
+		mapping, canonical, err := parseDecomposition(entry.Decomposition)
+		if err != nil {
+			return nil, fmt.Errorf("U+%s: %w", codepoint, err)
+		}
+		single[codepointInt] = mapping
+		if canonical && len(mapping) == 2 && !exclusions[codepoint] {
+			n.compositions[[2]rune{mapping[0], mapping[1]}] = codepointInt
+		}
+	}
+
+	// The decompositions in UnicodeData.txt are of one step only
+	var decompose func(r rune) []rune
+	decompose = func(r rune) []rune {
+		if full, ok := n.decompositions[r]; ok {
+			return full
+		}
+		mapping, ok := single[r]
+		if !ok {
+			return hangulDecomposition(r)
+		}
+		var full []rune
+		for _, m := range mapping {
+			full = append(full, decompose(m)...)
+		}
+		n.decompositions[r] = full
+		return full
+	}
+	for r := range single {
+		decompose(r)
+	}
+	return n, nil
+}
+
+// Returns the NFKC normalization of a code point
+func (n *normalizer) nfkc(r rune) NFKMapping {
+	full, ok := n.decompositions[r]
+	if !ok {
+		full = hangulDecomposition(r)
+		if len(full) == 1 {
+			return NFKMapping{r}
+		}
+	}
+	decomposed := append([]rune(nil), full...)
+
+	// Canonical ordering of each run of non-starters
+	for start := 0; start < len(decomposed); start++ {
+		if n.combiningClass[decomposed[start]] == 0 {
+			continue
+		}
+		end := start
+		for end < len(decomposed) && n.combiningClass[decomposed[end]] != 0 {
+			end++
+		}
+		run := decomposed[start:end]
+		sort.SliceStable(run, func(i, j int) bool { return n.combiningClass[run[i]] < n.combiningClass[run[j]] })
+		start = end
+	}
+
+	// Canonical composition, where a code point is blocked from the starter
+	// by one in between with the same or a higher combining class
+	composed := NFKMapping{decomposed[0]}
+	starter := 0
+	lastClass := n.combiningClass[decomposed[0]]
+	if lastClass != 0 {
+		starter = -1
+	}
+	for _, c := range decomposed[1:] {
+		class := n.combiningClass[c]
+		if starter >= 0 && (lastClass == 0 || lastClass < class) {
+			if composite, ok := n.compose(composed[starter], c); ok {
+				composed[starter] = composite
+				continue
+			}
+		}
+		if class == 0 {
+			starter = len(composed)
+		}
+		lastClass = class
+		composed = append(composed, c)
+	}
+	return composed
+}
+
+// Returns the jamo of a Hangul syllable, or the code point itself if it is
+// not one
+func hangulDecomposition(r rune) []rune {
+	if r < hangulSBase || r >= hangulSBase+hangulSCount {
+		return []rune{r}
+	}
+	s := r - hangulSBase
+	jamo := []rune{hangulLBase + s/hangulNCount, hangulVBase + (s%hangulNCount)/hangulTCount}
+	if t := s % hangulTCount; t != 0 {
+		jamo = append(jamo, hangulTBase+t)
+	}
+	return jamo
+}
+
+// Returns the primary composite of two code points, if there is one
+func (n *normalizer) compose(first, second rune) (rune, bool) {
+	if first >= hangulLBase && first < hangulLBase+hangulLCount && second >= hangulVBase && second < hangulVBase+hangulVCount {
+		return hangulSBase + ((first-hangulLBase)*hangulVCount+second-hangulVBase)*hangulTCount, true
+	}
+	if first >= hangulSBase && first < hangulSBase+hangulSCount && (first-hangulSBase)%hangulTCount == 0 && second > hangulTBase && second < hangulTBase+hangulTCount {
+		return first + second - hangulTBase, true
+	}
+	composite, ok := n.compositions[[2]rune{first, second}]
+	return composite, ok
+}
+
+// Creates the normalizer of a version from UnicodeData.txt and the
+// Full_Composition_Exclusion lines of DerivedNormalizationProps.txt
+func openNormalizer(loader *Loader, version string) (*normalizer, error) {
+	unicodeData, err := loader.UnicodeData(version)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version, "UnicodeData.txt"), err)
+	}
+	exclusions, err := loader.BinaryProperty(version, "DerivedNormalizationProps.txt", "Full_Composition_Exclusion")
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version, "DerivedNormalizationProps.txt"), err)
+	}
+	// Every version has composition exclusions, so none means the file is not
+	// the complete one
+	if len(exclusions) == 0 {
+		return nil, errors.New("no Full_Composition_Exclusion code points in " + loader.Path(version, "DerivedNormalizationProps.txt"))
+	}
+	n, err := newNormalizer(unicodeData, exclusions)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version, "UnicodeData.txt"), err)
+	}
+	return n, nil
+}
+
+// The first line of the nfk.txt that WriteNFKTable writes, by which the
+// normalization backends tell it from one made elsewhere
+const generatedNFKHeader = "# Generated by unicode-idn-diff from UnicodeData.txt and DerivedNormalizationProps.txt"
+
+// Writes nfk.txt for a version, with the NFKC normalization of every code
+// point computed from UnicodeData.txt and the Full_Composition_Exclusion
+// lines of DerivedNormalizationProps.txt, in the format of the common table
+// generation scripts, after a first line saying how it was made:
+//
+//	U+00BD;0031;2044;0032
+func WriteNFKTable(w io.Writer, loader *Loader, version string) error {
+	n, err := openNormalizer(loader, version)
+	if err != nil {
+		return err
+	}
+
+	buffered := bufio.NewWriter(w)
+	fmt.Fprintf(buffered, "%s\n", generatedNFKHeader)
+	for codepointInt := 0; codepointInt < codeSpaceSize; codepointInt++ {
+		fmt.Fprintf(buffered, "U+%04X", codepointInt)
+		for _, r := range n.nfkc(rune(codepointInt)) {
+			fmt.Fprintf(buffered, ";%04X", r)
+		}
+		buffered.WriteByte('\n')
+	}
+	return buffered.Flush()
+}
@@ -0,0 +1,103 @@
+package idndiff
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"sort"
+)
+
+// The analyses that can be run without allcodepoints.txt, with Options.Only
+var onlyAnalyses = []string{"gc", "nfk"}
+
+// The derived property value of assigned code points, when it is not known
+// because allcodepoints.txt was not read
+const unknownProperty = "UNKNOWN"
+
+// Compares only the General Category ("gc") or only the NFK normalization
+// ("nfk") of two versions, for a beta release that has the UCD files but no
+// allcodepoints.txt yet. Code points are assigned unless their General
+// Category is Cn, and their derived property values are UNKNOWN, so the
+// appendices that need the derived property values are skipped.
+func compareOnly(loader *Loader, version1, version2, only string) (*Report, error) {
+	if !slices.Contains(onlyAnalyses, only) {
+		return nil, fmt.Errorf("unknown analysis %q for -only, expected gc or nfk", only)
+	}
+	report := &Report{Meta: Meta{SchemaVersion: ReportSchemaVersion, Version1: version1, Version2: version2}}
+
+	var data [2]*VersionData
+	for i, version := range []string{version1, version2} {
+		generalCategory, err := loader.PropertyFile(version, "DerivedGeneralCategory.txt")
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", loader.Path(version, "DerivedGeneralCategory.txt"), err)
+		}
+		d := &VersionData{Version: version, Properties: make(map[string]string), Names: make(map[string]string), GeneralCategory: generalCategory}
+		for codepoint, category := range generalCategory {
+			d.Properties[codepoint] = unknownProperty
+			if category == "Cn" {
+				d.Properties[codepoint] = "UNASSIGNED"
+			}
+			d.Codepoints = append(d.Codepoints, hexToInt(codepoint))
+		}
+		sort.Ints(d.Codepoints)
+
+		// Names are optional
+		unicodeData, err := loader.UnicodeData(version)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("reading %s: %w", loader.Path(version, "UnicodeData.txt"), err)
+		}
+		for codepoint, entry := range unicodeData {
+			d.Names[codepoint] = entry.Name
+		}
+		data[i] = d
+	}
+	old, new := data[0], data[1]
+	for codepoint, property := range old.Properties {
+		if property != "UNASSIGNED" && old.GeneralCategory[codepoint] == "Mn" {
+			report.MnCount1++
+		}
+	}
+	for codepoint, property := range new.Properties {
+		if property != "UNASSIGNED" && new.GeneralCategory[codepoint] == "Mn" {
+			report.MnCount2++
+		}
+	}
+
+	switch only {
+	case "gc":
+		report.Warnings = append(report.Warnings, "only the General Category was compared (-only gc), the derived property values are UNKNOWN")
+		report.SkippedAppendices = []string{"A", "D", "E", "F"}
+		for _, finding := range (categoryChanges{}).Detect(old, new) {
+			report.AppendixB = append(report.AppendixB, GCChange{finding.CodePoint, finding.Old, finding.New, finding.OldProperty, finding.NewProperty, finding.Name})
+		}
+		for _, finding := range (newCombiningMarks{}).Detect(old, new) {
+			report.AppendixC = append(report.AppendixC, CodePoint{finding.CodePoint, finding.Name})
+		}
+	case "nfk":
+		report.Warnings = append(report.Warnings, "only the NFK normalization was compared (-only nfk), the derived property values are UNKNOWN")
+		report.SkippedAppendices = []string{"A", "B", "C", "D", "E", "F"}
+		for i, version := range []string{version1, version2} {
+			nfk, err := loader.NFKData(version)
+			if err != nil {
+				return nil, fmt.Errorf("reading %s: %w", loader.Path(version, "nfk.txt"), err)
+			}
+			data[i].NFK = nfk
+		}
+		for _, codepointInt := range new.Codepoints {
+			codepoint := codepointKey(codepointInt)
+			oldProperty, existedBefore := old.Properties[codepoint]
+			if !existedBefore || oldProperty == "UNASSIGNED" {
+				continue
+			}
+			oldNFK := old.NFK.mapping(codepointInt)
+			newNFK := new.NFK.mapping(codepointInt)
+			if !slices.Equal(oldNFK, newNFK) {
+				report.NFKChanges = append(report.NFKChanges, NFKChange{codepoint, formatNFK(oldNFK), formatNFK(newNFK), oldProperty, new.Properties[codepoint]})
+			}
+		}
+	}
+
+	report.Warnings = append(report.Warnings, loader.DuplicateWarnings(version1, version2)...)
+	return report, nil
+}
@@ -0,0 +1,80 @@
+package idndiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// An output format for reports
+type Format struct {
+	Extension string // Of the files written in the format, such as ".txt"
+	MediaType string // As the Content-Type of the format served over HTTP
+	Render    func(io.Writer, *Report, RenderOptions) error
+}
+
+// The output formats, by name
+var Formats = map[string]Format{
+	"text":      {".txt", "text/plain; charset=utf-8", RenderText},
+	"json":      {".json", "application/json", withoutOptions(RenderJSON)},
+	"delta":     {".delta", "text/plain; charset=utf-8", withoutOptions(RenderDelta)},
+	"html":      {".html", "text/html; charset=utf-8", RenderHTML},
+	"markdown":  {".md", "text/markdown; charset=utf-8", RenderMarkdown},
+	"porcelain": {".tsv", "text/tab-separated-values; charset=utf-8", withoutOptions(RenderPorcelain)},
+	"xml2rfc":   {".xml", "application/xml", RenderXML2RFC},
+}
+
+// How a report is rendered. The zero value renders every entry.
+type RenderOptions struct {
+	// The most entries of an appendix that the text, Markdown and xml2rfc
+	// reports list, or 0 for no limit. They point to the overflow file of an
+	// appendix with more entries, see OverflowSections.
+	MaxEntries int
+	// Returns the name of the overflow file of an appendix, or nil for
+	// "appendix-" followed by the letter and ".txt"
+	OverflowFile func(letter string) string
+	// The longest line of the text and xml2rfc reports, folded as by
+	// foldLines, or 0 for no limit
+	LineWidth int
+	// Whether to show the character itself next to each code point in the
+	// appendices and tickets
+	ShowGlyphs bool
+	// How identifiers are written in the prose of each format
+	Glossary Glossary
+}
+
+// Returns the name of the overflow file with all entries of an appendix, as
+// the reports point to it
+func (opts RenderOptions) OverflowFileName(letter string) string {
+	if opts.OverflowFile != nil {
+		return opts.OverflowFile(letter)
+	}
+	return "appendix-" + letter + ".txt"
+}
+
+// Adapts a renderer of a format without options to Format.Render
+func withoutOptions(render func(io.Writer, *Report) error) func(io.Writer, *Report, RenderOptions) error {
+	return func(w io.Writer, r *Report, _ RenderOptions) error {
+		return render(w, r)
+	}
+}
+
+// Renders the report as indented JSON
+func RenderJSON(w io.Writer, r *Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// Reads a report written by RenderJSON, which must have the schema version of
+// this package
+func ReadJSON(r io.Reader) (*Report, error) {
+	var report Report
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return nil, err
+	}
+	if report.SchemaVersion != ReportSchemaVersion {
+		return nil, fmt.Errorf("report has schema version %q, want %q", report.SchemaVersion, ReportSchemaVersion)
+	}
+	return &report, nil
+}
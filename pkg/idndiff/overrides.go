@@ -0,0 +1,119 @@
+package idndiff
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// The outcome of the review of a code point or a range, recorded by the
+// reviewers so that later runs do not list it as a candidate again
+type override struct {
+	start   int
+	end     int
+	outcome string // The derived property value decided on
+	note    string
+}
+
+// Reads an overrides file. Each line is a code point or a range, the derived
+// property value decided on, which has to be one of propertyOrder, and an
+// optional note, for example:
+//
+//	U+166D ; DISALLOWED ; keep DISALLOWED, no exception needed
+func readOverrides(filePath string) ([]override, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var overrides []override
+	scanner := newLineScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(strings.Split(scanner.Text(), "#")[0])
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, ";", 3)
+		if len(fields) < 2 || strings.TrimSpace(fields[1]) == "" {
+			return nil, fmt.Errorf("%s:%d: no derived property value for %q", filePath, lineNumber, strings.TrimSpace(fields[0]))
+		}
+		outcome := strings.TrimSpace(fields[1])
+		if !slices.Contains(propertyOrder, outcome) {
+			return nil, fmt.Errorf("%s:%d: %q is not a derived property value, expected one of %s", filePath, lineNumber, outcome, strings.Join(propertyOrder, ", "))
+		}
+		what := strings.TrimSpace(fields[0])
+		first, last, isRange := strings.Cut(what, "..")
+		if !isRange {
+			last = first
+		}
+		start, err1 := strconv.ParseInt(strings.TrimPrefix(first, "U+"), 16, 32)
+		end, err2 := strconv.ParseInt(strings.TrimPrefix(last, "U+"), 16, 32)
+		if err1 != nil || err2 != nil || start > end {
+			return nil, fmt.Errorf("%s:%d: invalid code point range %q", filePath, lineNumber, what)
+		}
+		o := override{start: int(start), end: int(end), outcome: outcome}
+		if len(fields) == 3 {
+			o.note = strings.TrimSpace(fields[2])
+		}
+		overrides = append(overrides, o)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return overrides, nil
+}
+
+// Moves the candidates of Appendix E that the overrides record the outcome
+// of to the resolved ones, and returns the overrides that matched no
+// candidate, which are likely stale
+func applyOverrides(r *Report, overrides []override) []override {
+	used := make([]bool, len(overrides))
+	var candidates []ExceptionCandidate
+	for _, entry := range r.AppendixE {
+		codepoint := hexToInt(entry.CodePoint)
+		index := -1
+		for i, o := range overrides {
+			if codepoint >= o.start && codepoint <= o.end {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			candidates = append(candidates, entry)
+			continue
+		}
+		used[index] = true
+		r.Resolved = append(r.Resolved, ResolvedCandidate{
+			CodePoint: entry.CodePoint,
+			Name:      entry.Name,
+			Property:  entry.Property,
+			Source:    entry.Source,
+			Outcome:   overrides[index].outcome,
+			Note:      overrides[index].note,
+		})
+	}
+	r.AppendixE = candidates
+
+	var unused []override
+	for i, o := range overrides {
+		if !used[i] {
+			unused = append(unused, o)
+		}
+	}
+	return unused
+}
+
+// Formats the code points of an override as in the overrides file
+func (o override) String() string {
+	if o.start == o.end {
+		return fmt.Sprintf("U+%04X", o.start)
+	}
+	return fmt.Sprintf("U+%04X..U+%04X", o.start, o.end)
+}
@@ -0,0 +1,166 @@
+package idndiff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The version of the porcelain format, the first line of each summary. It
+// changes only if lines are removed or change meaning; new kinds of lines may
+// be added within a version.
+const PorcelainVersion = 1
+
+// Renders the summary for scripts, one fact per line with tab separated
+// fields, the first of which says what the line is:
+//
+//	porcelain <version of the format>
+//	versions <version1> <version2>
+//	warning <text>
+//	error <section> <text>
+//	join-control <version> <code point>...
+//	context <version> <CONTEXTJ count> <CONTEXTO count>
+//	mn <version> <count>
+//	appendix <letter> <entries, or skipped>
+//
+// followed by a line per optional section that was requested, named as its
+// flag, such as "uts46 <checked> <discrepancies>". Code points are four to
+// six hexadecimal digits, and tabs and newlines in texts become spaces.
+func RenderPorcelain(w io.Writer, r *Report) error {
+	var buffer strings.Builder
+	line := func(fields ...any) {
+		for i, field := range fields {
+			if i > 0 {
+				buffer.WriteByte('\t')
+			}
+			buffer.WriteString(strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(fmt.Sprint(field)))
+		}
+		buffer.WriteByte('\n')
+	}
+
+	line("porcelain", PorcelainVersion)
+	line("versions", r.Version1, r.Version2)
+	for _, warning := range r.Warnings {
+		line("warning", warning)
+	}
+	for _, e := range r.Errors {
+		line("error", e.Section, e.Error)
+	}
+	if r.JoinControl != nil {
+		for _, version := range []struct {
+			name       string
+			codepoints []CodePoint
+		}{{r.Version1, r.JoinControl.CodePoints1}, {r.Version2, r.JoinControl.CodePoints2}} {
+			fields := []any{"join-control", version.name}
+			for _, entry := range version.codepoints {
+				fields = append(fields, entry.CodePoint)
+			}
+			line(fields...)
+		}
+	}
+	if !r.Skipped("A") {
+		line("context", r.Version1, r.ContextRules.ContextJ1, r.ContextRules.ContextO1)
+		line("context", r.Version2, r.ContextRules.ContextJ2, r.ContextRules.ContextO2)
+	}
+	if !r.Skipped("C") {
+		line("mn", r.Version1, r.MnCount1)
+		line("mn", r.Version2, r.MnCount2)
+	}
+	line("appendix", "A", entries(r, "A", len(r.AppendixA)))
+	line("appendix", "B", entries(r, "B", len(r.AppendixB)))
+	line("appendix", "C", entries(r, "C", len(r.AppendixC)))
+	line("appendix", "D", entries(r, "D", len(r.AppendixD)))
+	line("appendix", "E", entries(r, "E", len(r.AppendixE)))
+	line("appendix", "F", entries(r, "F", len(r.AppendixF)))
+
+	if r.Exceptions != nil {
+		line("exceptions", len(r.Exceptions.Additions), len(r.Exceptions.Removals), len(r.Exceptions.ValueChanges))
+	}
+	if r.Registry != "" {
+		counts := make(map[string]int)
+		for _, entry := range r.AppendixE {
+			counts[entry.Registry]++
+		}
+		line("iana-registry", counts[RegistryNew], counts[RegistryListed], counts[RegistryConflict])
+	}
+	if r.NFKHazards != nil {
+		line("nfk-hazards", len(r.NFKHazards.Entries))
+	}
+	if r.NFKCCaseFold != nil {
+		line("nfkc-casefold", len(r.NFKCCaseFold.Changes))
+	}
+	if r.CaseFolding != nil {
+		line("case-folding", len(r.CaseFolding.Changes))
+	}
+	if r.DecompositionTypes != nil {
+		line("decomposition-types", len(r.DecompositionTypes.Changes))
+	}
+	if r.DecompositionMappings != nil {
+		line("decomposition-mappings", len(r.DecompositionMappings.Changes))
+	}
+	if r.BidiClasses != nil {
+		line("bidi-class", len(r.BidiClasses.Changes))
+	}
+	if r.JoiningTypes != nil {
+		line("joining-type", len(r.JoiningTypes.Changes))
+	}
+	if r.ScriptChanges != nil {
+		line("script-changes", len(r.ScriptChanges.Changes))
+	}
+	if r.CombiningClasses != nil {
+		line("combining-class", len(r.CombiningClasses.Changes), r.CombiningClasses.Virama)
+	}
+	for _, group := range r.AppendixCScripts {
+		line("group-by-script", group.Script, len(group.CodePoints))
+	}
+	if r.MarkRendering != nil {
+		counts := r.MarkRendering.counts()
+		line("mark-rendering", counts[RenderingSpacing], counts[RenderingInvisible], counts[RenderingUnknown])
+	}
+	if r.CaseConsistency != nil {
+		line("case-pairs", r.CaseConsistency.Checked, len(r.CaseConsistency.Anomalies))
+	}
+	if r.GCConsistency != nil {
+		for _, version := range r.GCConsistency.Versions {
+			line("gc-consistency", version.Version, version.MissingPropertyCount, version.MissingCategoryCount)
+		}
+	}
+	if r.Frequencies != nil {
+		line("frequencies", r.Frequencies.Total1, r.Frequencies.Total2)
+	}
+	if r.NewAssignments != nil {
+		line("new-assignments", r.NewAssignments.Count, len(r.NewAssignments.Ranges))
+	}
+	if r.BidiImpact != nil {
+		line("bidi", len(r.BidiImpact.Entries))
+	}
+	if r.CrossCheck != nil {
+		differences := 0
+		for _, appendix := range r.CrossCheck.Appendices {
+			differences += len(appendix.OnlyPublished) + len(appendix.OnlyComputed)
+		}
+		line("cross-check", differences)
+	}
+	if r.UTS46 != nil {
+		line("uts46", r.UTS46.Checked, len(r.UTS46.Discrepancies))
+	}
+	if r.Homoglyphs != nil {
+		line("homoglyphs", r.Homoglyphs.Checked, len(r.Homoglyphs.Entries))
+	}
+	if r.Informational != nil {
+		line("informational", len(r.Informational.Entries))
+	}
+	if r.RootCauses != nil {
+		for _, change := range r.RootCauses.ruleChanges() {
+			line("root-cause", change.old, change.new, change.count)
+		}
+	}
+	if r.RestrictedScripts != nil {
+		line("restricted-scripts", len(r.RestrictedScripts.Entries))
+	}
+	for _, findings := range r.Findings {
+		line("detector", findings.Detector, len(findings.Findings))
+	}
+	_, err := io.WriteString(w, buffer.String())
+	return err
+}
@@ -0,0 +1,58 @@
+package idndiff
+
+// Collects the derived property values of the sorted code points in ranges
+// where the property (and the categories, if derivation is not nil) is the
+// same. Code points missing from the table end a range and are returned as
+// gaps, so that the ranges never cover them silently.
+//
+// This runs once per code point in the code space, so it works on integers
+// and only formats code points at the boundaries of the ranges. Looking up
+// a property with a key converted from a byte slice does not allocate.
+func compressRanges(codepoints []int, properties map[string]string, derivation *derivationData) ([]PropertyRange, []CodePointRange) {
+	var ranges []PropertyRange
+	var gaps []CodePointRange
+	var start, end int
+	var currentProperty string
+	var currentCategories category
+	first := true
+	next := 0
+	var key [8]byte
+
+	for _, codepointInt := range codepoints {
+		if codepointInt > next {
+			gaps = append(gaps, CodePointRange{codepointKey(next), codepointKey(codepointInt - 1)})
+		}
+		next = codepointInt + 1
+
+		property := properties[string(appendHexKey(key[:0], codepointInt))]
+		var categories category
+		if derivation != nil {
+			categories = derivation.categories(codepointInt)
+		}
+
+		if first {
+			start = codepointInt
+			end = codepointInt
+			currentProperty = property
+			currentCategories = categories
+			first = false
+		} else if property == currentProperty && categories == currentCategories && codepointInt == end+1 {
+			end = codepointInt
+		} else {
+			ranges = append(ranges, PropertyRange{codepointKey(start), codepointKey(end), currentProperty, currentCategories.names(), ""})
+			start = codepointInt
+			end = codepointInt
+			currentProperty = property
+			currentCategories = categories
+		}
+	}
+
+	// Add the last range
+	if !first {
+		ranges = append(ranges, PropertyRange{codepointKey(start), codepointKey(end), currentProperty, currentCategories.names(), ""})
+	}
+	if next < codeSpaceSize {
+		gaps = append(gaps, CodePointRange{codepointKey(next), codepointKey(codeSpaceSize - 1)})
+	}
+	return ranges, gaps
+}
@@ -0,0 +1,56 @@
+package idndiff
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCompressRanges(t *testing.T) {
+	properties := map[string]string{
+		"0002": "PVALID", "0003": "PVALID", "0004": "DISALLOWED",
+		// Same value after a gap starts a new range
+		"0006": "DISALLOWED",
+	}
+	ranges, gaps := compressRanges([]int{2, 3, 4, 6}, properties, nil)
+
+	wantRanges := []PropertyRange{{"0002", "0003", "PVALID", nil, ""}, {"0004", "0004", "DISALLOWED", nil, ""}, {"0006", "0006", "DISALLOWED", nil, ""}}
+	if fmt.Sprint(ranges) != fmt.Sprint(wantRanges) {
+		t.Errorf("ranges = %v, want %v", ranges, wantRanges)
+	}
+	wantGaps := []CodePointRange{{"0000", "0001"}, {"0005", "0005"}, {"0007", "10FFFF"}}
+	if fmt.Sprint(gaps) != fmt.Sprint(wantGaps) {
+		t.Errorf("gaps = %v, want %v", gaps, wantGaps)
+	}
+}
+
+// Returns a table of the whole code space in the given number of ranges
+func rangesTable(numRanges int) ([]int, map[string]string) {
+	values := []string{"PVALID", "DISALLOWED", "UNASSIGNED"}
+	codepoints := make([]int, codeSpaceSize)
+	properties := make(map[string]string, codeSpaceSize)
+	for codepoint := range codeSpaceSize {
+		codepoints[codepoint] = codepoint
+		properties[fmt.Sprintf("%04X", codepoint)] = values[codepoint*numRanges/codeSpaceSize%len(values)]
+	}
+	return codepoints, properties
+}
+
+// The allocations depend on the number of ranges, not on the number of code
+// points
+func TestCompressRangesAllocations(t *testing.T) {
+	codepoints, properties := rangesTable(10)
+	allocs := testing.AllocsPerRun(5, func() {
+		compressRanges(codepoints, properties, nil)
+	})
+	if allocs > 100 {
+		t.Errorf("%.0f allocations for %d code points in 10 ranges", allocs, len(codepoints))
+	}
+}
+
+func BenchmarkCompressRanges(b *testing.B) {
+	codepoints, properties := rangesTable(2000)
+	b.ReportAllocs()
+	for b.Loop() {
+		compressRanges(codepoints, properties, nil)
+	}
+}
@@ -0,0 +1,121 @@
+package idndiff
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// How a candidate for Appendix E compares with the registry published by IANA
+const (
+	RegistryNew      = "new"      // Not in the registry, or UNASSIGNED there
+	RegistryListed   = "listed"   // Already listed with the same value
+	RegistryConflict = "conflict" // Listed with another value
+)
+
+// Descriptions of the registry statuses, for the text report
+var registryStatusText = map[string]string{
+	RegistryNew:      "new",
+	RegistryListed:   "already listed (same value)",
+	RegistryConflict: "conflicts with published value",
+}
+
+// The records of the IANA registry of derived property values in XML, such
+// as https://www.iana.org/assignments/idna-tables-12.0.0/idna-tables-12.0.0.xml
+type registryXML struct {
+	Records []struct {
+		CodePoint string `xml:"codepoint"`
+		Property  string `xml:"property"`
+	} `xml:"registry>record"`
+}
+
+// Reads the derived property values published by IANA from a file or an
+// http(s) URL, in the XML or the CSV format of the registry. The CSV format
+// has a header line followed by lines like "0000-002C,DISALLOWED,NULL..COMMA".
+func readRegistry(location string) (map[string]string, error) {
+	data, err := readLocation(location)
+	if err != nil {
+		return nil, err
+	}
+
+	var records [][2]string
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")) {
+		var registry registryXML
+		if err := xml.Unmarshal(data, &registry); err != nil {
+			return nil, err
+		}
+		for _, record := range registry.Records {
+			records = append(records, [2]string{record.CodePoint, record.Property})
+		}
+	} else {
+		reader := csv.NewReader(bytes.NewReader(data))
+		reader.FieldsPerRecord = -1
+		lines, err := reader.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		for i, line := range lines {
+			if i == 0 || len(line) < 2 {
+				continue
+			}
+			records = append(records, [2]string{line[0], line[1]})
+		}
+	}
+
+	values := make(map[string]string)
+	for _, record := range records {
+		what := strings.TrimSpace(record[0])
+		first, last, isRange := strings.Cut(what, "-")
+		if !isRange {
+			last = first
+		}
+		start, err1 := strconv.ParseInt(first, 16, 32)
+		end, err2 := strconv.ParseInt(last, 16, 32)
+		if err1 != nil || err2 != nil || start > end {
+			return nil, fmt.Errorf("invalid code point range %q", what)
+		}
+		for codepoint := start; codepoint <= end; codepoint++ {
+			values[codepointKey(int(codepoint))] = strings.TrimSpace(record[1])
+		}
+	}
+	return values, nil
+}
+
+// Reads a file, or fetches it if location is an http(s) URL
+func readLocation(location string) ([]byte, error) {
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		return os.ReadFile(location)
+	}
+	resp, err := http.Get(location)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected HTTP status %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Marks each candidate in Appendix E with how it compares with the
+// derived property values in the registry
+func compareRegistry(appendixE []ExceptionCandidate, registry map[string]string) {
+	for i, entry := range appendixE {
+		published, listed := registry[entry.CodePoint]
+		switch {
+		case !listed || published == "UNASSIGNED":
+			appendixE[i].Registry = RegistryNew
+		case published == entry.Property:
+			appendixE[i].Registry = RegistryListed
+		default:
+			appendixE[i].Registry = RegistryConflict
+			appendixE[i].RegistryValue = published
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package idndiff
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// The registry is read the same from its XML and its CSV format, from a file
+// or a URL, and each candidate of Appendix E is marked with how it compares
+// with it
+func TestCompareRegistry(t *testing.T) {
+	formats := map[string]string{
+		"idna-tables.xml": `<?xml version='1.0' encoding='UTF-8'?>
+<registry xmlns="http://www.iana.org/assignments">
+  <registry id="idna-tables-properties">
+    <record><codepoint>0000-002C</codepoint><property>DISALLOWED</property></record>
+    <record><codepoint>00DF</codepoint><property>PVALID</property></record>
+    <record><codepoint>0B55</codepoint><property>UNASSIGNED</property></record>
+    <record><codepoint>19DA</codepoint><property>PVALID</property></record>
+  </registry>
+</registry>
+`,
+		"idna-tables.csv": "Codepoint,Property,Description\n0000-002C,DISALLOWED,NULL..COMMA\n00DF,PVALID,LATIN SMALL LETTER SHARP S\n0B55,UNASSIGNED,<reserved>\n19DA,PVALID,NEW TAI LUE THAM DIGIT ONE\n",
+	}
+	dir := t.TempDir()
+	server := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	defer server.Close()
+	var locations []string
+	for name, content := range formats {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		locations = append(locations, filepath.Join(dir, name), server.URL+"/"+name)
+	}
+
+	for _, location := range locations {
+		registry, err := readRegistry(location)
+		if err != nil {
+			t.Fatalf("%s: %s", location, err)
+		}
+		if len(registry) != 0x2D+3 || registry["002C"] != "DISALLOWED" {
+			t.Errorf("%s: read %d code points, U+002C %s", location, len(registry), registry["002C"])
+		}
+		candidates := []ExceptionCandidate{
+			{CodePoint: "00DF", Property: "PVALID"},
+			{CodePoint: "0B55", Property: "PVALID"},
+			{CodePoint: "19DA", Property: "DISALLOWED"},
+			{CodePoint: "1E900", Property: "PVALID"},
+		}
+		compareRegistry(candidates, registry)
+		want := []ExceptionCandidate{
+			{CodePoint: "00DF", Property: "PVALID", Registry: RegistryListed},
+			{CodePoint: "0B55", Property: "PVALID", Registry: RegistryNew},
+			{CodePoint: "19DA", Property: "DISALLOWED", Registry: RegistryConflict, RegistryValue: "PVALID"},
+			{CodePoint: "1E900", Property: "PVALID", Registry: RegistryNew},
+		}
+		if !reflect.DeepEqual(candidates, want) {
+			t.Errorf("%s: got %+v, want %+v", location, candidates, want)
+		}
+	}
+
+	for _, invalid := range []string{server.URL + "/missing.csv", filepath.Join(dir, "missing.csv")} {
+		if _, err := readRegistry(invalid); err == nil {
+			t.Errorf("%s: read without an error", invalid)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "reversed.csv"), []byte("Codepoint,Property\n002C-0000,DISALLOWED\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readRegistry(filepath.Join(dir, "reversed.csv")); err == nil {
+		t.Errorf("a reversed range was read without an error")
+	}
+}
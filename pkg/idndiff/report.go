@@ -0,0 +1,607 @@
+package idndiff
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+)
+
+// The result of comparing two versions of Unicode. It is computed once, and
+// all output formats are rendered from it. Code points are hexadecimal
+// strings like "0041".
+type Report struct {
+	Meta
+
+	// Problems with the data, such as files that are missing, and the
+	// appendices skipped because of them
+	Warnings          []string `json:"warnings,omitempty"`
+	SkippedAppendices []string `json:"skipped_appendices,omitempty"`
+	// Requested sections left out of the report because of an error, unless
+	// FailFast makes such errors fatal
+	Errors []SectionError `json:"errors,omitempty"`
+
+	// The Join_Control code points of both versions, if they are not only
+	// U+200C and U+200D
+	JoinControl *JoinControlAlert `json:"join_control,omitempty"`
+
+	// Appendix A: code points that changed derived property value, except
+	// those that were UNASSIGNED in the first version
+	AppendixA []PropertyChange `json:"appendix_a"`
+	// Number of code points per change of derived property value, including
+	// changes from UNASSIGNED
+	ChangeCounts []ChangeCount `json:"change_counts"`
+
+	// Code points with contextual rules per version, and changes to them
+	ContextRules ContextRules `json:"context_rules"`
+
+	// Appendix B: assigned code points that changed General Category
+	AppendixB []GCChange `json:"appendix_b"`
+
+	// Number of assigned code points with General Category Mn per version
+	MnCount1 int `json:"mn_count1"`
+	MnCount2 int `json:"mn_count2"`
+	// Appendix C: code points that got General Category Mn
+	AppendixC []CodePoint `json:"appendix_c"`
+	// Appendix C grouped by the script of the code points in the second
+	// version, if requested
+	AppendixCScripts []ScriptGroup `json:"appendix_c_scripts,omitempty"`
+
+	// Assigned code points with an NFK normalization that changed
+	NFKChanges []NFKChange `json:"nfk_changes"`
+	// Appendix D: new PVALID code points with NFK normalization
+	AppendixD []NFKEntry `json:"appendix_d"`
+
+	// All code points that changed derived property value, including from
+	// UNASSIGNED, in ranges with the same change
+	Delta []DeltaRange `json:"delta"`
+
+	// Appendix E: additions to Exceptions (F), sorted by code point
+	AppendixE []ExceptionCandidate `json:"appendix_e"`
+	// Candidates for Appendix E that an overrides file records the outcome of
+	// the review of, if requested
+	Resolved []ResolvedCandidate `json:"resolved,omitempty"`
+	// The IANA registry of derived property values that the candidates were
+	// compared with, if requested
+	Registry string `json:"registry,omitempty"`
+
+	// Appendix F: derived property values of the second version, with the
+	// code points in Appendix E that are not excluded from review marked
+	// UNDER REVIEW
+	AppendixF []PropertyRange `json:"appendix_f"`
+	// Ranges of code points missing from allcodepoints.txt, and so from Appendix F
+	AppendixFGaps []CodePointRange `json:"appendix_f_gaps,omitempty"`
+	// The style of Appendix F, if not the compact one: "iana"
+	AppendixFStyle string `json:"appendix_f_style,omitempty"`
+
+	// Comparison of Exceptions (F) with RFC 5892, if requested
+	Exceptions *ExceptionsComparison `json:"exceptions,omitempty"`
+
+	// Code points that are PVALID in both versions with a normalization that
+	// now includes other derived property values, if requested
+	NFKHazards *NFKHazards `json:"nfk_hazards,omitempty"`
+
+	// Assigned code points with an NFKC_Casefold mapping that changed, if
+	// requested
+	NFKCCaseFold *NFKCCaseFoldChanges `json:"nfkc_casefold,omitempty"`
+
+	// Assigned code points whose full case folding changed, if requested
+	CaseFolding *CaseFoldingChanges `json:"case_folding,omitempty"`
+
+	// Assigned code points whose decomposition type changed, if requested
+	DecompositionTypes *DecompositionChanges `json:"decomposition_types,omitempty"`
+
+	// Assigned code points whose full canonical or compatibility
+	// decomposition changed, if requested
+	DecompositionMappings *DecompositionMappingChanges `json:"decomposition_mappings,omitempty"`
+
+	// Assigned code points whose Bidi_Class changed, if requested
+	BidiClasses *BidiClassChanges `json:"bidi_classes,omitempty"`
+
+	// Assigned code points whose Joining_Type changed, if requested
+	JoiningTypes *JoiningTypeChanges `json:"joining_types,omitempty"`
+
+	// Assigned code points whose Script changed, if requested
+	ScriptChanges *ScriptChanges `json:"script_changes,omitempty"`
+
+	// Assigned code points whose Canonical_Combining_Class changed, if
+	// requested
+	CombiningClasses *CombiningClassChanges `json:"combining_classes,omitempty"`
+
+	// How the code points in Appendix C are likely rendered, if requested
+	MarkRendering *MarkRendering `json:"mark_rendering,omitempty"`
+
+	// Newly assigned case pairs with unexpected derived property values, if
+	// requested
+	CaseConsistency *CaseConsistency `json:"case_consistency,omitempty"`
+
+	// The version of Unicode that each code point in the appendices A-E was
+	// first assigned in, from DerivedAge.txt, in code point order, if
+	// requested
+	Ages []CodePointAge `json:"ages,omitempty"`
+
+	// Code points with a General_Category but no derived property value, and
+	// the other way around, if requested
+	GCConsistency *GCConsistency `json:"gc_consistency,omitempty"`
+
+	// Number of code points per derived property value, if requested
+	Frequencies *PropertyFrequencies `json:"frequencies,omitempty"`
+
+	// All newly assigned code points with their derived property values, in
+	// ranges, if requested
+	NewAssignments *NewAssignments `json:"new_assignments,omitempty"`
+
+	// Code points that became valid and matter for the Bidi Rule, if requested
+	BidiImpact *BidiImpact `json:"bidi_impact,omitempty"`
+
+	// Comparison with a published review document, if requested
+	CrossCheck *CrossCheck `json:"cross_check,omitempty"`
+
+	// Comparison with the UTS #46 IDNA Mapping Table, if requested
+	UTS46 *UTS46Comparison `json:"uts46,omitempty"`
+
+	// Code points that became PVALID and are confusable, most risky first, if
+	// requested
+	Homoglyphs *HomoglyphRisks `json:"homoglyphs,omitempty"`
+
+	// Code points whose derived property value held despite related
+	// changes, and why, if requested
+	Informational *Informational `json:"informational,omitempty"`
+
+	// Why the derived property values in Appendix A changed, by the rules of
+	// RFC 5892 and the UCD properties behind them, if requested
+	RootCauses *RootCauses `json:"root_causes,omitempty"`
+
+	// Code points that became PVALID in scripts that a policy restricts, if
+	// requested
+	RestrictedScripts *RestrictedScripts `json:"restricted_scripts,omitempty"`
+
+	// A paragraph describing the changes, as a starting point for the
+	// introduction of a review, if requested
+	Narrative *Narrative `json:"narrative,omitempty"`
+
+	// What the additional change detectors found, if requested
+	Findings []DetectorFindings `json:"findings,omitempty"`
+
+	// The properties of each code point in the appendices A-E in the second
+	// version, if requested
+	Snapshots []PropertySnapshot `json:"snapshots,omitempty"`
+
+	// The blocks of the code points in the appendices A-E, with their code
+	// charts, if Blocks.txt is there for the second version
+	ChartBlocks []ChartBlock `json:"chart_blocks,omitempty"`
+}
+
+// What was compared. The fields are at the top level of the JSON report.
+type Meta struct {
+	// The version of the JSON schema in data/report.schema.json that the
+	// report follows
+	SchemaVersion string `json:"schema_version"`
+
+	Version1 string `json:"version1"`
+	Version2 string `json:"version2"`
+}
+
+// Records that the appendices are skipped because a file is missing. Other
+// errors, and missing files that are required, are returned.
+func (r *Report) skip(err error, path string, required bool, appendices ...string) error {
+	if required || !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	r.Warnings = append(r.Warnings, fmt.Sprintf("%s is missing, Appendix %s skipped", path, strings.Join(appendices, " and ")))
+	for _, appendix := range appendices {
+		if !r.Skipped(appendix) {
+			r.SkippedAppendices = append(r.SkippedAppendices, appendix)
+		}
+	}
+	return nil
+}
+
+// A requested section of the report that could not be computed
+type SectionError struct {
+	Section string `json:"section"`
+	Error   string `json:"error"`
+}
+
+// Records that a requested section is left out of the report because of err,
+// so that the other sections are still reported. With failFast, err is
+// returned instead. Nothing is recorded if err is nil.
+func (r *Report) fail(section string, err error, failFast bool) error {
+	if err == nil {
+		return nil
+	}
+	if failFast {
+		return err
+	}
+	r.Errors = append(r.Errors, SectionError{section, err.Error()})
+	return nil
+}
+
+// Reports whether an appendix was skipped because of a missing file
+func (r *Report) Skipped(appendix string) bool {
+	return slices.Contains(r.SkippedAppendices, appendix)
+}
+
+// The Join_Control code points of each version, which the CONTEXTJ rules
+// of RFC 5892 are written for
+type JoinControlAlert struct {
+	CodePoints1 []CodePoint `json:"code_points1"`
+	CodePoints2 []CodePoint `json:"code_points2"`
+}
+
+// The narrative of the changes, and the scripts it mentions as new
+type Narrative struct {
+	NewScripts []string `json:"new_scripts"`
+	Text       string   `json:"text"`
+}
+
+// A code point and its name
+type CodePoint struct {
+	CodePoint string `json:"code_point"`
+	Name      string `json:"name"`
+}
+
+// A change of derived property value
+type PropertyChange struct {
+	CodePoint string `json:"code_point"`
+	Old       string `json:"old"`
+	New       string `json:"new"`
+	Name      string `json:"name"`
+}
+
+// The number of code points with a certain change of derived property value
+type ChangeCount struct {
+	Old   string `json:"old"`
+	New   string `json:"new"`
+	Count int    `json:"count"`
+}
+
+// A range of code points with the same change of derived property value
+type DeltaRange struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// A change of General Category
+type GCChange struct {
+	CodePoint   string `json:"code_point"`
+	Old         string `json:"old"`
+	New         string `json:"new"`
+	OldProperty string `json:"old_property"`
+	NewProperty string `json:"new_property"`
+	Name        string `json:"name"`
+}
+
+// A change of NFK normalization
+type NFKChange struct {
+	CodePoint   string `json:"code_point"`
+	Old         string `json:"old"`
+	New         string `json:"new"`
+	OldProperty string `json:"old_property"`
+	NewProperty string `json:"new_property"`
+}
+
+// A code point with its NFK normalization
+type NFKEntry struct {
+	CodePoint string `json:"code_point"`
+	NFK       string `json:"nfk"`
+	Name      string `json:"name"`
+}
+
+// A code point proposed as an addition to Exceptions (F)
+type ExceptionCandidate struct {
+	CodePoint string `json:"code_point"`
+	Name      string `json:"name"`
+	// The derived property value the code point would otherwise have
+	Property string `json:"property"`
+	// The appendix (A, C or D) that made the code point a candidate
+	Source string `json:"source"`
+	// Set when the code point is excluded from review
+	Excluded        bool   `json:"excluded,omitempty"`
+	ExclusionReason string `json:"exclusion_reason,omitempty"`
+	// How the code point compares with the registry published by IANA, if
+	// requested: new, listed or conflict, with the published value for a
+	// conflict
+	Registry      string `json:"registry,omitempty"`
+	RegistryValue string `json:"registry_value,omitempty"`
+}
+
+// A candidate for Appendix E that was already reviewed
+type ResolvedCandidate struct {
+	CodePoint string `json:"code_point"`
+	Name      string `json:"name"`
+	Property  string `json:"property"`
+	Source    string `json:"source"`
+	// The derived property value decided on in the review, and why
+	Outcome string `json:"outcome"`
+	Note    string `json:"note,omitempty"`
+}
+
+// A range of code points with the same derived property value
+type PropertyRange struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Property string `json:"property"`
+	// The RFC 5892 categories of the code points, if requested
+	Categories []string `json:"categories,omitempty"`
+	// The names of the first and last code points, in the iana style of
+	// Appendix F
+	Description string `json:"description,omitempty"`
+}
+
+// The difference between the published and the proposed Exceptions (F)
+type ExceptionsComparison struct {
+	Additions    []ExceptionValue       `json:"additions"`
+	Removals     []ExceptionValue       `json:"removals"`
+	ValueChanges []ExceptionValueChange `json:"value_changes"`
+}
+
+// An entry in Exceptions (F)
+type ExceptionValue struct {
+	CodePoint string `json:"code_point"`
+	Value     string `json:"value"`
+	Name      string `json:"name"`
+}
+
+// An entry in Exceptions (F) with a proposed value that differs from the
+// published one
+type ExceptionValueChange struct {
+	CodePoint string `json:"code_point"`
+	Published string `json:"published"`
+	Proposed  string `json:"proposed"`
+	Name      string `json:"name"`
+}
+
+// Code points with an NFKC_Casefold mapping that changed, from
+// DerivedNormalizationProps.txt. A code point that NFKC_Casefold removes has
+// an empty mapping.
+type NFKCCaseFoldChanges struct {
+	Changes []NFKChange `json:"changes"`
+}
+
+// Code points with a full case folding that changed, from CaseFolding.txt.
+// A code point without a mapping folds to itself.
+type CaseFoldingChanges struct {
+	Changes []NFKChange `json:"changes"`
+}
+
+// Code points whose decomposition type in UnicodeData.txt changed
+type DecompositionChanges struct {
+	Changes []DecompositionChange `json:"changes"`
+}
+
+// A code point whose decomposition type changed. The types are "none",
+// "canonical", or the tag of a compatibility decomposition, such as "<font>".
+type DecompositionChange struct {
+	CodePoint string `json:"code_point"`
+	Old       string `json:"old"`
+	New       string `json:"new"`
+	// The decompositions, as in UnicodeData.txt
+	OldDecomposition string `json:"old_decomposition"`
+	NewDecomposition string `json:"new_decomposition"`
+	OldProperty      string `json:"old_property"`
+	NewProperty      string `json:"new_property"`
+	Name             string `json:"name"`
+}
+
+// Code points whose full decomposition, from UnicodeData.txt, changed
+type DecompositionMappingChanges struct {
+	Changes []DecompositionMappingChange `json:"changes"`
+}
+
+// A code point whose full canonical or compatibility decomposition changed.
+// A code point that does not decompose is its own decomposition.
+type DecompositionMappingChange struct {
+	CodePoint        string `json:"code_point"`
+	OldCanonical     string `json:"old_canonical"`
+	NewCanonical     string `json:"new_canonical"`
+	OldCompatibility string `json:"old_compatibility"`
+	NewCompatibility string `json:"new_compatibility"`
+	OldProperty      string `json:"old_property"`
+	NewProperty      string `json:"new_property"`
+	Name             string `json:"name"`
+}
+
+// Code points with a normalization that newly includes code points with other
+// derived property values
+type NFKHazards struct {
+	Entries []NFKHazard `json:"entries"`
+}
+
+// A code point with a normalization that newly includes code points with
+// other derived property values
+type NFKHazard struct {
+	CodePoint string `json:"code_point"`
+	Old       string `json:"old"`
+	New       string `json:"new"`
+	// The code points in the new normalization with a derived property value
+	// not in the old normalization
+	Targets []CodePointProperty `json:"targets"`
+	Name    string              `json:"name"`
+}
+
+// A code point and its derived property value
+type CodePointProperty struct {
+	CodePoint string `json:"code_point"`
+	Property  string `json:"property"`
+}
+
+// The result of checking the newly assigned case pairs
+type CaseConsistency struct {
+	// Number of case pairs checked
+	Checked int `json:"checked"`
+	// Case pairs where the uppercase letter is not DISALLOWED or the
+	// lowercase letter is not PVALID
+	Anomalies []CasePair `json:"anomalies"`
+}
+
+// An uppercase and a lowercase letter that map to each other
+type CasePair struct {
+	Upper         string `json:"upper"`
+	UpperProperty string `json:"upper_property"`
+	UpperName     string `json:"upper_name"`
+	Lower         string `json:"lower"`
+	LowerProperty string `json:"lower_property"`
+	LowerName     string `json:"lower_name"`
+}
+
+// The result of comparing the derived property values with UTS #46
+type UTS46Comparison struct {
+	// Number of code points compared
+	Checked int `json:"checked"`
+	// Code points that are valid in IDNA2008 according to one but not the other
+	Discrepancies []UTS46Discrepancy `json:"discrepancies"`
+}
+
+// A code point where the derived property value and UTS #46 disagree
+type UTS46Discrepancy struct {
+	CodePoint      string `json:"code_point"`
+	Property       string `json:"property"`
+	Status         string `json:"status"`
+	IDNA2008Status string `json:"idna2008_status,omitempty"`
+	Name           string `json:"name"`
+}
+
+// The number of code points per derived property value in both versions
+type PropertyFrequencies struct {
+	// Number of code points with a derived property value in each version,
+	// which should be the whole code space
+	Total1 int                 `json:"total1"`
+	Total2 int                 `json:"total2"`
+	Values []PropertyFrequency `json:"values"`
+}
+
+// The number of code points with a derived property value in both versions
+type PropertyFrequency struct {
+	Property string `json:"property"`
+	Count1   int    `json:"count1"`
+	Count2   int    `json:"count2"`
+}
+
+// A range of code points
+type CodePointRange struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// The version of Unicode a code point was first assigned in, such as "13.0"
+type CodePointAge struct {
+	CodePoint string `json:"code_point"`
+	Age       string `json:"age"`
+}
+
+// The code points that became valid in IDNA2008 and are right-to-left
+// letters or digits, which matter for the Bidi Rule of RFC 5893
+type BidiImpact struct {
+	Entries []BidiEntry `json:"entries"`
+	// Number of entries per script and Bidi_Class
+	Counts []BidiCount `json:"counts"`
+}
+
+// A code point that became valid and has a Bidi_Class that matters for the
+// Bidi Rule
+type BidiEntry struct {
+	CodePoint string `json:"code_point"`
+	Old       string `json:"old"`
+	New       string `json:"new"`
+	BidiClass string `json:"bidi_class"`
+	Script    string `json:"script"`
+	Name      string `json:"name"`
+}
+
+// The number of code points of a script and Bidi_Class in the Bidi impact
+type BidiCount struct {
+	Script    string `json:"script"`
+	BidiClass string `json:"bidi_class"`
+	Count     int    `json:"count"`
+}
+
+// The number of code points with contextual rules in both versions, and the
+// code points that became or stopped being CONTEXTJ or CONTEXTO
+type ContextRules struct {
+	ContextJ1 int              `json:"contextj_count1"`
+	ContextJ2 int              `json:"contextj_count2"`
+	ContextO1 int              `json:"contexto_count1"`
+	ContextO2 int              `json:"contexto_count2"`
+	Changes   []PropertyChange `json:"changes"`
+}
+
+// The result of comparing the appendices A-E with a published review document
+type CrossCheck struct {
+	// The document that was compared with
+	Document   string               `json:"document"`
+	Appendices []CrossCheckAppendix `json:"appendices"`
+}
+
+// The differences between an appendix and the same appendix of a published
+// review document
+type CrossCheckAppendix struct {
+	Appendix      string   `json:"appendix"`
+	Published     int      `json:"published"`
+	Computed      int      `json:"computed"`
+	OnlyPublished []string `json:"only_published"`
+	OnlyComputed  []string `json:"only_computed"`
+}
+
+// The Unicode properties of a code point, so that it can be reviewed without
+// looking it up elsewhere
+type PropertySnapshot struct {
+	CodePoint       string `json:"code_point"`
+	Name            string `json:"name"`
+	GeneralCategory string `json:"general_category"`
+	Script          string `json:"script"`
+	BidiClass       string `json:"bidi_class"`
+	CombiningClass  string `json:"combining_class"`
+	// The NFK normalization, as in Appendix D
+	NFK   string `json:"nfk"`
+	Age   string `json:"age"`
+	Block string `json:"block"`
+}
+
+// What a change detector found
+type DetectorFindings struct {
+	Detector string    `json:"detector"`
+	Findings []Finding `json:"findings"`
+}
+
+// The code points that became PVALID and are confusable with other code
+// points, by UTS #39
+type HomoglyphRisks struct {
+	// Number of code points that became PVALID
+	Checked int             `json:"checked"`
+	Entries []HomoglyphRisk `json:"entries"`
+	// Scripts that are new in the second version, and whether they are
+	// whole-script confusable with existing scripts
+	NewScripts []NewScript `json:"new_scripts"`
+}
+
+// A code point that became PVALID and is confusable with a prototype
+type HomoglyphRisk struct {
+	CodePoint string `json:"code_point"`
+	Script    string `json:"script"`
+	// The code points it is confusable with, and their scripts
+	Prototype        string   `json:"prototype"`
+	PrototypeScripts []string `json:"prototype_scripts"`
+	// The sum of the scores of the flags, higher is more risky
+	Score int      `json:"score"`
+	Flags []string `json:"flags"`
+	Name  string   `json:"name"`
+}
+
+// A script with no code points assigned in the first version
+type NewScript struct {
+	Script string `json:"script"`
+	// Number of PVALID code points
+	PVALID int `json:"pvalid"`
+	// The existing scripts it is whole-script confusable with
+	ConfusableWith []ScriptConfusables `json:"confusable_with"`
+}
+
+// The code points of a new script that are confusable with an existing script
+type ScriptConfusables struct {
+	Script     string   `json:"script"`
+	CodePoints []string `json:"code_points"`
+}
@@ -0,0 +1,94 @@
+package idndiff
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Code points that became PVALID in scripts that a policy, such as the
+// exclusions of the ICANN Maximal Starting Repertoire (MSR), restricts
+type RestrictedScripts struct {
+	Policy  string                `json:"policy"`
+	Entries []RestrictedCodePoint `json:"entries"`
+}
+
+// A code point that became PVALID in a restricted script
+type RestrictedCodePoint struct {
+	CodePoint string `json:"code_point"`
+	Old       string `json:"old"`
+	Script    string `json:"script"`
+	Reason    string `json:"reason"`
+	Name      string `json:"name"`
+}
+
+// Reads a policy of restricted scripts. Each line is a script, as named in
+// Scripts.txt, followed by the reason, for example:
+//
+//	Cuneiform ; excluded from the MSR
+//	Old_Italic ; historic script
+//
+// Spaces in the names of scripts are the same as underscores.
+func readScriptPolicy(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	policy := make(map[string]string)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(strings.Split(line, "#")[0])
+		if line == "" {
+			continue
+		}
+		script, reason, _ := strings.Cut(line, ";")
+		script = strings.ReplaceAll(strings.TrimSpace(script), " ", "_")
+		if script == "" {
+			return nil, fmt.Errorf("line %d: no script", i+1)
+		}
+		policy[script] = strings.TrimSpace(reason)
+	}
+	return policy, nil
+}
+
+// Finds the code points that became PVALID and are used with a script of the
+// policy, by their Script_Extensions. A code point used with several
+// restricted scripts is listed with the first of them.
+func findRestrictedScripts(codepoints []int, properties1, properties2, codePointNames2 map[string]string, scripts2 *codePointScripts, policy map[string]string) []RestrictedCodePoint {
+	var entries []RestrictedCodePoint
+	for _, codepointInt := range codepoints {
+		codepoint := codepointKey(codepointInt)
+		if properties2[codepoint] != "PVALID" || properties1[codepoint] == "PVALID" {
+			continue
+		}
+		var script, reason string
+		restricted := false
+		for _, script = range scripts2.of(codepoint) {
+			if reason, restricted = policy[script]; restricted {
+				break
+			}
+		}
+		if !restricted {
+			continue
+		}
+		old := properties1[codepoint]
+		if old == "" {
+			old = "UNASSIGNED"
+		}
+		entries = append(entries, RestrictedCodePoint{codepoint, old, script, reason, codePointNames2[codepoint]})
+	}
+	return entries
+}
+
+// Reads the policy and the scripts of the second version, and finds the
+// code points that became PVALID in restricted scripts
+func restrictedScriptSection(loader *Loader, version2, policyPath string, codepoints []int, properties1, properties2, codePointNames2 map[string]string) (*RestrictedScripts, error) {
+	policy, err := readScriptPolicy(policyPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", policyPath, err)
+	}
+	scripts2, err := loader.Scripts(version2)
+	if err != nil {
+		return nil, err
+	}
+	return &RestrictedScripts{policyPath, findRestrictedScripts(codepoints, properties1, properties2, codePointNames2, scripts2, policy)}, nil
+}
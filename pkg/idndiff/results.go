@@ -0,0 +1,85 @@
+package idndiff
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// Precomputed change events for a sequence of versions, so that the history
+// of any range of them can be queried without the UCD files. The file is
+// gzip compressed JSON.
+type Results struct {
+	// The version of the format, as in the report
+	SchemaVersion string `json:"schema_version"`
+	// The versions that were compared, in order
+	Versions []string      `json:"versions"`
+	Events   []ChangeEvent `json:"events"`
+}
+
+// Writes the change events between consecutive versions as an artifact
+func WriteResults(path string, versions []string, events []ChangeEvent) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	compressed := gzip.NewWriter(file)
+	if err := json.NewEncoder(compressed).Encode(Results{ReportSchemaVersion, versions, events}); err != nil {
+		return err
+	}
+	if err := compressed.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// Reads an artifact written by WriteResults
+func ReadResults(path string) (*Results, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	compressed, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	var artifact Results
+	if err := json.NewDecoder(compressed).Decode(&artifact); err != nil {
+		return nil, err
+	}
+	if artifact.SchemaVersion != ReportSchemaVersion {
+		return nil, fmt.Errorf("%s has format version %q, want %q", path, artifact.SchemaVersion, ReportSchemaVersion)
+	}
+	return &artifact, nil
+}
+
+// Returns the change events from the first to the last of the versions,
+// which all have to be in the artifact in the same order. As with history,
+// they are in code point order, and then in version order.
+func (a *Results) History(versions []string) ([]ChangeEvent, error) {
+	previous := -1
+	for _, version := range versions {
+		index := slices.Index(a.Versions, version)
+		if index < 0 {
+			return nil, fmt.Errorf("version %s is not in the precomputed results", version)
+		}
+		if index <= previous {
+			return nil, fmt.Errorf("version %s is out of order", version)
+		}
+		previous = index
+	}
+	first := slices.Index(a.Versions, versions[0])
+	included := a.Versions[first+1 : previous+1]
+
+	var events []ChangeEvent
+	for _, event := range a.Events {
+		if slices.Contains(included, event.Version) {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
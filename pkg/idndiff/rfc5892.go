@@ -0,0 +1,149 @@
+package idndiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The categories of RFC 5892 section 2, as a set of bits
+type category uint16
+
+const (
+	letterDigits        category = 1 << iota // (A)
+	unstable                                 // (B)
+	ignorableProperties                      // (C)
+	ignorableBlocks                          // (D)
+	ldh                                      // (E)
+	exceptions                               // (F)
+	backwardCompatible                       // (G)
+	joinControl                              // (H)
+	oldHangulJamo                            // (I)
+	unassigned                               // (J)
+)
+
+// Names of the categories, in the order of the bits
+var categoryNames = []string{
+	"LetterDigits",
+	"Unstable",
+	"IgnorableProperties",
+	"IgnorableBlocks",
+	"LDH",
+	"Exceptions",
+	"BackwardCompatible",
+	"JoinControl",
+	"OldHangulJamo",
+	"Unassigned",
+}
+
+// Returns the names of the categories in the set
+func (c category) names() []string {
+	var names []string
+	for i, name := range categoryNames {
+		if c&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// The categories in the order that the rules of RFC 5892 section 3 test
+// them. The first category of a code point decides its derived property
+// value; Unstable (B), IgnorableProperties (C), IgnorableBlocks (D) and
+// OldHangulJamo (I) all make it DISALLOWED.
+var ruleOrder = []category{exceptions, backwardCompatible, unassigned, ldh, joinControl, unstable, ignorableProperties, ignorableBlocks, oldHangulJamo, letterDigits}
+
+// Returns the category whose rule decides the derived property value of a
+// code point, or 0 if none does and it is DISALLOWED by the last rule
+func (d *derivationData) rule(codepointInt int) category {
+	c := d.categories(codepointInt)
+	for _, r := range ruleOrder {
+		if c&r != 0 {
+			return r
+		}
+	}
+	return 0
+}
+
+// Returns the name of a single category with its letter, as in
+// "LetterDigits (A)"
+func (c category) label() string {
+	for i, name := range categoryNames {
+		if c == 1<<i {
+			return fmt.Sprintf("%s (%c)", name, 'A'+i)
+		}
+	}
+	return "none"
+}
+
+// The properties of a version of Unicode that the categories are computed from
+type derivationData struct {
+	generalCategory    map[string]string
+	unstable           map[string]bool // Changes_When_NFKC_Casefolded, unless literalUnstable
+	defaultIgnorable   map[string]bool
+	whiteSpace         map[string]bool
+	noncharacter       map[string]bool
+	joinControl        map[string]bool
+	blocks             map[string]string
+	hangulSyllableType map[string]string
+	// Whether unstable is by the literal definition of RFC 5892, see
+	// Loader.LiteralUnstable
+	literalUnstable bool
+	// Loader.Tables, with the embedded ones in place of the nil ones
+	tables RFC5892Tables
+}
+
+// The UCD files the categories are computed from
+var derivationFiles = []string{
+	"DerivedGeneralCategory.txt",
+	"DerivedNormalizationProps.txt",
+	"DerivedCoreProperties.txt",
+	"PropList.txt",
+	"Blocks.txt",
+	"HangulSyllableType.txt",
+}
+
+// Returns the categories of RFC 5892 section 2 a code point belongs to
+func (d *derivationData) categories(codepointInt int) category {
+	codepoint := codepointKey(codepointInt)
+	var c category
+
+	switch d.generalCategory[codepoint] {
+	case "Ll", "Lu", "Lo", "Nd", "Lm", "Mn", "Mc":
+		c |= letterDigits
+	}
+	if d.unstable[codepoint] {
+		c |= unstable
+	}
+	if d.defaultIgnorable[codepoint] || d.whiteSpace[codepoint] || d.noncharacter[codepoint] {
+		c |= ignorableProperties
+	}
+	if d.tables.IgnorableBlocks[d.blocks[codepoint]] {
+		c |= ignorableBlocks
+	}
+	if codepointInt == 0x002D || (codepointInt >= 0x0030 && codepointInt <= 0x0039) || (codepointInt >= 0x0061 && codepointInt <= 0x007A) {
+		c |= ldh
+	}
+	if _, ok := d.tables.Exceptions[codepoint]; ok {
+		c |= exceptions
+	}
+	if _, ok := d.tables.BackwardCompatible[codepoint]; ok {
+		c |= backwardCompatible
+	}
+	if d.joinControl[codepoint] {
+		c |= joinControl
+	}
+	switch d.hangulSyllableType[codepoint] {
+	case "L", "V", "T":
+		c |= oldHangulJamo
+	}
+	// Code points without a General Category are unassigned (Cn)
+	if gc, ok := d.generalCategory[codepoint]; (!ok || gc == "Cn") && !d.noncharacter[codepoint] {
+		c |= unassigned
+	}
+	return c
+}
+
+// Formats a set of categories as a comma separated list of names
+func formatCategories(c category) string {
+	return strings.Join(c.names(), ", ")
+}
@@ -0,0 +1,58 @@
+package idndiff
+
+import (
+	"io"
+	"regexp"
+	"strings"
+)
+
+// The version that stands for the derived property values as published in
+// Appendix B of RFC 5892, for Unicode 5.2.0, to compare with everything that
+// changed since IDNA2008 was defined. The table is read from rfc5892.txt in
+// the base directory, and the other files from the directory of 5.2.0.
+const RFC5892Baseline = "rfc5892"
+
+// The version of Unicode that RFC 5892 was published for
+const rfc5892Version = "5.2.0"
+
+// A line of the table in Appendix B of RFC 5892, such as
+// "0000..002C  ; DISALLOWED  # <control>..COMMA"
+var rfc5892LineRegex = regexp.MustCompile(`^\s*([0-9A-F]{4,6})(\.\.[0-9A-F]{4,6})?\s*;\s*([A-Z]+)`)
+
+// Returns the version whose directory has the data files of a version
+func dataVersion(version string) string {
+	if version == RFC5892Baseline {
+		return rfc5892Version
+	}
+	return version
+}
+
+// Returns the derived property values of the table in Appendix B of RFC 5892,
+// read from rfc5892.txt, which is either the RFC as published in text or
+// only the table. Code points have no names, as the table only has them in
+// comments.
+func (l *Loader) rfc5892Table() (map[string]string, map[string]string, error) {
+	value, err := l.load(".", "rfc5892.txt", func(r io.Reader) (any, error) {
+		return readRFC5892Table(r)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return value.(map[string]string), map[string]string{}, nil
+}
+
+// Reads the table in Appendix B of RFC 5892, skipping all other text, such as
+// the rest of the RFC and its page headers and footers
+func readRFC5892Table(r io.Reader) (map[string]string, error) {
+	var table strings.Builder
+	scanner := newLineScanner(r)
+	for scanner.Scan() {
+		if m := rfc5892LineRegex.FindStringSubmatch(scanner.Text()); m != nil {
+			table.WriteString(m[1] + m[2] + " ; " + m[3] + "\n")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return readPropertyFile(strings.NewReader(table.String()), nil)
+}
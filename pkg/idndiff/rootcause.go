@@ -0,0 +1,128 @@
+package idndiff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Why the derived property values in Appendix A changed: the rule of RFC
+// 5892 section 3 that decided the value in each version, and the UCD
+// properties behind the categories that changed
+type RootCauses struct {
+	Entries []RootCause `json:"entries"`
+}
+
+// Why the derived property value of a code point changed. A rule is the
+// category that decided the value, as in "Unstable (B)", or "none" for a
+// code point that is DISALLOWED because no rule applies.
+type RootCause struct {
+	CodePoint string `json:"code_point"`
+	Old       string `json:"old"`
+	New       string `json:"new"`
+	OldRule   string `json:"old_rule"`
+	NewRule   string `json:"new_rule"`
+	// The properties that changed, as in "General_Category Po to Lo"
+	Changes []string `json:"changes"`
+	// Where the derived property values compared are not those the rules
+	// give, as with an allcodepoints.txt of other origin
+	Note string `json:"note,omitempty"`
+	Name string `json:"name"`
+}
+
+// The number of code points per change of the deciding rule
+type ruleChange struct {
+	old, new string
+	count    int
+}
+
+// Returns the number of code points per change of the deciding rule, most
+// common first
+func (r *RootCauses) ruleChanges() []ruleChange {
+	counts := make(map[[2]string]int)
+	for _, entry := range r.Entries {
+		counts[[2]string{entry.OldRule, entry.NewRule}]++
+	}
+	var changes []ruleChange
+	for rules, count := range counts {
+		changes = append(changes, ruleChange{rules[0], rules[1], count})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].count != changes[j].count {
+			return changes[i].count > changes[j].count
+		}
+		return changes[i].old+changes[i].new < changes[j].old+changes[j].new
+	})
+	return changes
+}
+
+// Returns the properties of a code point that the categories are computed
+// from, that differ between two versions
+func ucdChanges(d1, d2 *derivationData, codepoint string) []string {
+	orDefault := func(value, missing string) string {
+		if value == "" {
+			return missing
+		}
+		return value
+	}
+	changes := []string{}
+	for _, p := range []struct {
+		name     string
+		old, new string
+	}{
+		{"General_Category", orDefault(d1.generalCategory[codepoint], "Cn"), orDefault(d2.generalCategory[codepoint], "Cn")},
+		{"Changes_When_NFKC_Casefolded", yesNo(d1.unstable[codepoint]), yesNo(d2.unstable[codepoint])},
+		{"Default_Ignorable_Code_Point", yesNo(d1.defaultIgnorable[codepoint]), yesNo(d2.defaultIgnorable[codepoint])},
+		{"White_Space", yesNo(d1.whiteSpace[codepoint]), yesNo(d2.whiteSpace[codepoint])},
+		{"Noncharacter_Code_Point", yesNo(d1.noncharacter[codepoint]), yesNo(d2.noncharacter[codepoint])},
+		{"Join_Control", yesNo(d1.joinControl[codepoint]), yesNo(d2.joinControl[codepoint])},
+		{"Block", orDefault(d1.blocks[codepoint], "No_Block"), orDefault(d2.blocks[codepoint], "No_Block")},
+		{"Hangul_Syllable_Type", orDefault(d1.hangulSyllableType[codepoint], "NA"), orDefault(d2.hangulSyllableType[codepoint], "NA")},
+	} {
+		if p.old != p.new {
+			changes = append(changes, fmt.Sprintf("%s %s to %s", p.name, p.old, p.new))
+		}
+	}
+	return changes
+}
+
+// Reads the UCD files of both versions that the categories are computed
+// from, and finds why the derived property values in Appendix A changed
+func rootCauseSection(loader *Loader, version1, version2 string, appendixA []PropertyChange) (*RootCauses, error) {
+	d1, err := loader.DerivationData(version1)
+	if err != nil {
+		return nil, err
+	}
+	d2, err := loader.DerivationData(version2)
+	if err != nil {
+		return nil, err
+	}
+	causes := &RootCauses{Entries: []RootCause{}}
+	for _, change := range appendixA {
+		codepointInt := hexToInt(change.CodePoint)
+		var mismatches []string
+		for _, v := range []struct {
+			version, value string
+			d              *derivationData
+		}{{version1, change.Old, d1}, {version2, change.New, d2}} {
+			if derived := v.d.property(codepointInt); derived != v.value {
+				mismatches = append(mismatches, fmt.Sprintf("the rules give %s in %s", derived, v.version))
+			}
+		}
+		var note string
+		if len(mismatches) > 0 {
+			note = "not derived by the rules: " + strings.Join(mismatches, " and ")
+		}
+		causes.Entries = append(causes.Entries, RootCause{
+			CodePoint: change.CodePoint,
+			Old:       change.Old,
+			New:       change.New,
+			OldRule:   d1.rule(codepointInt).label(),
+			NewRule:   d2.rule(codepointInt).label(),
+			Changes:   ucdChanges(d1, d2, change.CodePoint),
+			Note:      note,
+			Name:      change.Name,
+		})
+	}
+	return causes, nil
+}
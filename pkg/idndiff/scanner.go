@@ -0,0 +1,29 @@
+package idndiff
+
+import (
+	"bufio"
+	"io"
+)
+
+// The longest line allowed in the data files unless the Loader sets another.
+// Some UCD files have comment lines longer than the 64 KiB that bufio.Scanner
+// allows by default.
+const DefaultMaxLineSize = 16 << 20
+
+// A reader of a data file with its own longest line allowed, as the files a
+// Loader opens have
+type lineLimited interface {
+	maxLineSize() int
+}
+
+// Returns a scanner of the lines of a data file, allowing lines up to the
+// longest line allowed in it
+func newLineScanner(r io.Reader) *bufio.Scanner {
+	maxLineSize := DefaultMaxLineSize
+	if limited, ok := r.(lineLimited); ok {
+		maxLineSize = limited.maxLineSize()
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, min(64*1024, maxLineSize)), maxLineSize)
+	return scanner
+}
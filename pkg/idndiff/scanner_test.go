@@ -0,0 +1,153 @@
+package idndiff
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// A comment line longer than the 64 KiB bufio.Scanner allows by default
+var longComment = "# " + strings.Repeat("x", 200*1024) + "\n"
+
+func TestLongLines(t *testing.T) {
+	categories, err := readPropertyFile(strings.NewReader(longComment+"0041..0042 ; Lu # LATIN CAPITAL LETTER A..B\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if categories["0042"] != "Lu" {
+		t.Errorf("U+0042 has General Category %q, want Lu", categories["0042"])
+	}
+
+	nfk, err := readNFKData(strings.NewReader(longComment+"00BD;0031;2044;0032\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := formatNFK(nfk.mapping(0x00BD)); got != "0031 2044 0032" {
+		t.Errorf("U+00BD normalizes to %s, want 0031 2044 0032", got)
+	}
+
+	unicodeData, err := readUnicodeData(strings.NewReader(longComment + "0041;LATIN CAPITAL LETTER A;Lu;0;L;;;;;N;;;;0061;\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unicodeData["0041"].Lowercase != "0061" {
+		t.Errorf("U+0041 has lowercase %q, want 0061", unicodeData["0041"].Lowercase)
+	}
+}
+
+func TestTooLongLine(t *testing.T) {
+	files := fstest.MapFS{
+		"14.0.0/DerivedGeneralCategory.txt": {Data: []byte(longComment + "0041 ; Lu\n")},
+	}
+	for _, test := range []struct {
+		maxLineSize int
+		tooLong     bool
+	}{
+		{0, false},
+		{1024, true},
+		{len(longComment), false},
+	} {
+		loader := NewLoaderFS(files)
+		loader.MaxLineSize = test.maxLineSize
+		categories, err := loader.PropertyFile("14.0.0", "DerivedGeneralCategory.txt")
+		if !test.tooLong {
+			if err != nil || categories["0041"] != "Lu" {
+				t.Errorf("with MaxLineSize %d, read U+0041 as %q with error %v", test.maxLineSize, categories["0041"], err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("longer than %d bytes", test.maxLineSize)) {
+			t.Errorf("with MaxLineSize %d, reading a longer line gave %v, want an error with the limit", test.maxLineSize, err)
+		}
+		if !errors.Is(err, bufio.ErrTooLong) {
+			t.Errorf("error %v does not wrap bufio.ErrTooLong", err)
+		}
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	input := "0041..0042 ; Lu\n0042 ; Ll\n"
+	for _, test := range []struct{ policy, want string }{{"last", "Ll"}, {"first", "Lu"}} {
+		dups := duplicates{policy: test.policy}
+		categories, err := readPropertyFile(strings.NewReader(input), &dups)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if categories["0042"] != test.want {
+			t.Errorf("with -duplicates %s U+0042 has General Category %q, want %s", test.policy, categories["0042"], test.want)
+		}
+		if len(dups.codepoints) != 1 || dups.codepoints[0] != "0042" {
+			t.Errorf("with -duplicates %s the duplicates are %v, want [0042]", test.policy, dups.codepoints)
+		}
+	}
+
+	dups := duplicates{policy: "error"}
+	if _, err := readPropertyFile(strings.NewReader(input), &dups); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("with -duplicates error reading a duplicate gave %v, want an error for line 2", err)
+	}
+
+	// The policy is that of the Loader, so that loaders with different
+	// policies do not share it
+	fsys := fstest.MapFS{"14.0.0/Scripts.txt": {Data: []byte(input)}}
+	first := NewLoaderFS(fsys)
+	first.DuplicatePolicy = "first"
+	if scripts, err := first.PropertyFile("14.0.0", "Scripts.txt"); err != nil || scripts["0042"] != "Lu" {
+		t.Errorf("with DuplicatePolicy first U+0042 is %q (%v), want Lu", scripts["0042"], err)
+	}
+	if scripts, err := NewLoaderFS(fsys).PropertyFile("14.0.0", "Scripts.txt"); err != nil || scripts["0042"] != "Ll" {
+		t.Errorf("with the default DuplicatePolicy U+0042 is %q (%v), want Ll", scripts["0042"], err)
+	}
+}
+
+func TestCodepointPropertyRanges(t *testing.T) {
+	input := "0060;DISALLOWED;Sk;GRAVE ACCENT\n0061..007A;PVALID;Ll;LATIN SMALL LETTER\n"
+	properties, names, err := readCodepointProperties(strings.NewReader(input), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(properties) != 27 || properties["0060"] != "DISALLOWED" || properties["0061"] != "PVALID" || properties["007A"] != "PVALID" {
+		t.Errorf("read %d code points, U+0060 %s, U+0061 %s, U+007A %s", len(properties), properties["0060"], properties["0061"], properties["007A"])
+	}
+	if names["006D"] != "LATIN SMALL LETTER" {
+		t.Errorf("U+006D is named %q, want the name of its range", names["006D"])
+	}
+
+	if _, _, err := readCodepointProperties(strings.NewReader("007A..0061;PVALID;Ll;X\n"), nil); err == nil {
+		t.Errorf("reading a reversed range gave no error")
+	}
+}
+
+func TestReadCodePointList(t *testing.T) {
+	codepoints, err := ReadCodePointList(strings.NewReader("U+00DF\n# comment\n\n223 # decimal\nu+10FFFF\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{0xDF, 223, 0x10FFFF}; !slices.Equal(codepoints, want) {
+		t.Errorf("read %v, want %v", codepoints, want)
+	}
+	for _, input := range []string{"00DF\n", "U+110000\n", "-1\n"} {
+		if _, err := ReadCodePointList(strings.NewReader(input)); err == nil {
+			t.Errorf("reading %q gave no error", input)
+		}
+	}
+}
+
+func TestRFC5892Table(t *testing.T) {
+	input := `Faltstrom                    Standards Track                   [Page 30]
+
+   0000..002C  ; DISALLOWED  # <control>..COMMA
+   002D        ; PVALID      # HYPHEN-MINUS
+   00B7        ; CONTEXTO    # MIDDLE DOT
+`
+	properties, err := readRFC5892Table(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(properties) != 47 || properties["002C"] != "DISALLOWED" || properties["002D"] != "PVALID" || properties["00B7"] != "CONTEXTO" {
+		t.Errorf("read %d code points, U+002C %s, U+002D %s, U+00B7 %s", len(properties), properties["002C"], properties["002D"], properties["00B7"])
+	}
+}
@@ -0,0 +1,13 @@
+package idndiff
+
+import _ "embed"
+
+// The version of the JSON report, and of the precomputed results. It changes
+// whenever a field is removed or changes meaning, so that consumers can tell
+// which format they read. Fields may be added without changing it.
+const ReportSchemaVersion = "1"
+
+// The JSON schema of the report, published in data/report.schema.json
+//
+//go:embed data/report.schema.json
+var ReportSchema []byte
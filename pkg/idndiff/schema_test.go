@@ -0,0 +1,172 @@
+package idndiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Validates a decoded JSON value against the subset of JSON Schema used by
+// data/report.schema.json, returning the first violation
+func validateSchema(root, schema map[string]any, value any, path string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/$defs/")
+		definition, ok := root["$defs"].(map[string]any)[name].(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: unknown reference %s", path, ref)
+		}
+		return validateSchema(root, definition, value, path)
+	}
+	if constant, ok := schema["const"]; ok && value != constant {
+		return fmt.Errorf("%s: %v, want %v", path, value, constant)
+	}
+	if types, ok := schema["type"]; ok {
+		allowed, ok := types.([]any)
+		if !ok {
+			allowed = []any{types}
+		}
+		if !slices.Contains(allowed, any(jsonType(value))) {
+			return fmt.Errorf("%s: %s, want %v", path, jsonType(value), types)
+		}
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		if !regexp.MustCompile(pattern).MatchString(value.(string)) {
+			return fmt.Errorf("%s: %q does not match %s", path, value, pattern)
+		}
+	}
+	switch value := value.(type) {
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, ok := value[name.(string)]; !ok {
+				return fmt.Errorf("%s: missing %s", path, name)
+			}
+		}
+		for name, field := range value {
+			property, ok := properties[name].(map[string]any)
+			if !ok {
+				if schema["additionalProperties"] == false {
+					return fmt.Errorf("%s: unexpected %s", path, name)
+				}
+				continue
+			}
+			if err := validateSchema(root, property, field, path+"."+name); err != nil {
+				return err
+			}
+		}
+	case []any:
+		items, _ := schema["items"].(map[string]any)
+		for i, item := range value {
+			if err := validateSchema(root, items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Returns the JSON Schema type of a decoded JSON value
+func jsonType(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if value == float64(int64(value)) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	}
+	return "object"
+}
+
+// Renders a report as JSON and validates it against the embedded schema
+func validateReport(t *testing.T, name string, r *Report) {
+	t.Helper()
+	var buffer strings.Builder
+	if err := RenderJSON(&buffer, r); err != nil {
+		t.Fatalf("%s: %s", name, err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(ReportSchema, &schema); err != nil {
+		t.Fatalf("data/report.schema.json: %s", err)
+	}
+	var value any
+	if err := json.Unmarshal([]byte(buffer.String()), &value); err != nil {
+		t.Fatalf("%s: %s", name, err)
+	}
+	if err := validateSchema(schema, schema, value, "report"); err != nil {
+		t.Errorf("%s: %s", name, err)
+	}
+}
+
+// Sets every field of a value, recursively, with slices of one element, so
+// that all fields of the report are present in its JSON
+func fill(value reflect.Value) {
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString("0041")
+	case reflect.Int:
+		value.SetInt(1)
+	case reflect.Bool:
+		value.SetBool(true)
+	case reflect.Slice:
+		value.Set(reflect.MakeSlice(value.Type(), 1, 1))
+		fill(value.Index(0))
+	case reflect.Pointer:
+		value.Set(reflect.New(value.Type().Elem()))
+		fill(value.Elem())
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			fill(value.Field(i))
+		}
+	}
+}
+
+func TestReportSchema(t *testing.T) {
+	loader := NewLoader(filepath.Join("testdata", "transitions"))
+	for _, tc := range transitions {
+		report, err := Compare(loader, tc.version1, tc.version2, Options{})
+		if err != nil {
+			t.Fatalf("%s to %s: %s", tc.version1, tc.version2, err)
+		}
+		validateReport(t, tc.version1+"-"+tc.version2, report)
+	}
+
+	// All optional sections, so that fields added to the report without
+	// adding them to the schema are caught
+	var report Report
+	fill(reflect.ValueOf(&report).Elem())
+	report.SchemaVersion = ReportSchemaVersion
+	validateReport(t, "filled report", &report)
+}
+
+// Every field of the report is in the JSON report, and reads back the same
+func TestReadJSON(t *testing.T) {
+	var report Report
+	fill(reflect.ValueOf(&report).Elem())
+	report.SchemaVersion = ReportSchemaVersion
+	var buffer bytes.Buffer
+	if err := RenderJSON(&buffer, &report); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadJSON(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, &report) {
+		t.Errorf("report read back differs from the one written")
+	}
+}
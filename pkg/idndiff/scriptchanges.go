@@ -0,0 +1,61 @@
+package idndiff
+
+import "fmt"
+
+// Assigned code points whose Script changed, if requested
+type ScriptChanges struct {
+	Changes []ScriptChange `json:"changes"`
+}
+
+// A change of Script, with the derived property values of the code point
+type ScriptChange struct {
+	CodePoint   string `json:"code_point"`
+	Old         string `json:"old"`
+	New         string `json:"new"`
+	OldProperty string `json:"old_property"`
+	NewProperty string `json:"new_property"`
+	Name        string `json:"name"`
+}
+
+// Returns the Script of a code point from Scripts.txt. Code points that are
+// not listed have the default, Unknown.
+func scriptOf(scripts map[string]string, codepoint string) string {
+	if script, ok := scripts[codepoint]; ok {
+		return script
+	}
+	return "Unknown"
+}
+
+// Finds the code points assigned in both versions whose Script changed, as
+// when a code point moves from Common or Inherited to a script. The label
+// generation rules of registries and their policies on mixing scripts are
+// defined per script, so such a code point may have to move between them.
+func compareScripts(codepoints []int, properties1, properties2, codePointNames2 map[string]string, scripts1, scripts2 map[string]string) []ScriptChange {
+	var changes []ScriptChange
+	for _, codepointInt := range codepoints {
+		codepoint := codepointKey(codepointInt)
+		oldProperty, existedBefore := properties1[codepoint]
+		newProperty := properties2[codepoint]
+		if !existedBefore || oldProperty == "UNASSIGNED" || newProperty == "UNASSIGNED" {
+			continue
+		}
+		old, new := scriptOf(scripts1, codepoint), scriptOf(scripts2, codepoint)
+		if old != new {
+			changes = append(changes, ScriptChange{codepoint, old, new, oldProperty, newProperty, codePointNames2[codepoint]})
+		}
+	}
+	return changes
+}
+
+// Reads Scripts.txt of both versions and compares the Script
+func scriptChangeSection(loader *Loader, version1, version2 string, codepoints []int, properties1, properties2, codePointNames2 map[string]string) (*ScriptChanges, error) {
+	scripts1, err := loader.PropertyFile(version1, "Scripts.txt")
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version1, "Scripts.txt"), err)
+	}
+	scripts2, err := loader.PropertyFile(version2, "Scripts.txt")
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", loader.Path(version2, "Scripts.txt"), err)
+	}
+	return &ScriptChanges{compareScripts(codepoints, properties1, properties2, codePointNames2, scripts1, scripts2)}, nil
+}
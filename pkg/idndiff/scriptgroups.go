@@ -0,0 +1,45 @@
+package idndiff
+
+import "sort"
+
+// The code points of Appendix C in one script
+type ScriptGroup struct {
+	Script     string      `json:"script"`
+	CodePoints []CodePoint `json:"code_points"`
+}
+
+// Groups the entries of Appendix C by their script, the script with the most
+// code points first. A code point used with several scripts, by its
+// Script_Extensions, is in the group of each of them. Code points without a
+// script in Scripts.txt are in the script Unknown, as its default value says.
+func groupByScript(entries []CodePoint, scripts *codePointScripts) []ScriptGroup {
+	var groups []ScriptGroup
+	index := make(map[string]int)
+	for _, entry := range entries {
+		for _, script := range scripts.of(entry.CodePoint) {
+			i, ok := index[script]
+			if !ok {
+				i = len(groups)
+				index[script] = i
+				groups = append(groups, ScriptGroup{Script: script})
+			}
+			groups[i].CodePoints = append(groups[i].CodePoints, entry)
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i].CodePoints) != len(groups[j].CodePoints) {
+			return len(groups[i].CodePoints) > len(groups[j].CodePoints)
+		}
+		return groups[i].Script < groups[j].Script
+	})
+	return groups
+}
+
+// Reads the scripts of the second version, and groups Appendix C by them
+func scriptGroupSection(loader *Loader, version2 string, entries []CodePoint) ([]ScriptGroup, error) {
+	scripts2, err := loader.Scripts(version2)
+	if err != nil {
+		return nil, err
+	}
+	return groupByScript(entries, scripts2), nil
+}
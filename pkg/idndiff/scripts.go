@@ -0,0 +1,88 @@
+package idndiff
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+)
+
+// The scripts of each code point: its Script from Scripts.txt, or the
+// scripts it is used with from ScriptExtensions.txt, such as Devanagari and
+// Bengali for a combining mark that Scripts.txt gives as Inherited
+type codePointScripts struct {
+	scripts    map[string]string   // Scripts.txt
+	extensions map[string][]string // ScriptExtensions.txt, by long name
+}
+
+// Returns the scripts of a code point: its Script_Extensions if
+// ScriptExtensions.txt lists it, and otherwise its Script, which is Unknown
+// for code points missing from Scripts.txt
+func (s *codePointScripts) of(codepoint string) []string {
+	if extensions, ok := s.extensions[codepoint]; ok {
+		return extensions
+	}
+	if script := s.scripts[codepoint]; script != "" {
+		return []string{script}
+	}
+	return []string{"Unknown"}
+}
+
+// Reads the long names of scripts by their aliases from the "sc" lines of
+// PropertyValueAliases.txt, such as "sc ; Deva ; Devanagari"
+func readScriptAliases(r io.Reader) (map[string]string, error) {
+	aliases := make(map[string]string)
+	scanner := newLineScanner(r)
+	for scanner.Scan() {
+		fields := strings.Split(strings.Split(scanner.Text(), "#")[0], ";")
+		if len(fields) < 3 || strings.TrimSpace(fields[0]) != "sc" {
+			continue
+		}
+		long := strings.TrimSpace(fields[2])
+		for _, alias := range fields[1:] {
+			aliases[strings.TrimSpace(alias)] = long
+		}
+	}
+	return aliases, scanner.Err()
+}
+
+// Returns the scripts of each code point from Scripts.txt and, if it is
+// there, ScriptExtensions.txt, whose short names of scripts are looked up in
+// PropertyValueAliases.txt
+func (l *Loader) Scripts(version string) (*codePointScripts, error) {
+	value, err := l.cached(version+"#scripts", func() (any, error) {
+		scripts, err := l.PropertyFile(version, "Scripts.txt")
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", l.Path(version, "Scripts.txt"), err)
+		}
+		s := &codePointScripts{scripts: scripts}
+		extensions, err := l.PropertyFile(version, "ScriptExtensions.txt")
+		if errors.Is(err, fs.ErrNotExist) {
+			return s, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", l.Path(version, "ScriptExtensions.txt"), err)
+		}
+		aliases, err := l.load(version, "PropertyValueAliases.txt", func(r io.Reader) (any, error) {
+			return readScriptAliases(r)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("reading %s, needed for the names of the scripts in ScriptExtensions.txt: %w", l.Path(version, "PropertyValueAliases.txt"), err)
+		}
+		s.extensions = make(map[string][]string, len(extensions))
+		for codepoint, names := range extensions {
+			for _, name := range strings.Fields(names) {
+				if long, ok := aliases.(map[string]string)[name]; ok {
+					name = long
+				}
+				s.extensions[codepoint] = append(s.extensions[codepoint], name)
+			}
+		}
+		return s, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*codePointScripts), nil
+}
@@ -0,0 +1,72 @@
+package idndiff
+
+import "fmt"
+
+// The UCD files of the second version that property snapshots are taken from
+type snapshotData struct {
+	unicodeData map[string]unicodeDataEntry
+	scripts     map[string]string // Scripts.txt
+	ages        map[string]string // DerivedAge.txt
+	blocks      map[string]string // Blocks.txt
+	nfk         NFKData
+}
+
+// Reads the UCD files that property snapshots are taken from
+func (l *Loader) snapshotData(version string, nfk NFKData) (*snapshotData, error) {
+	d := &snapshotData{nfk: nfk}
+	var err error
+	if d.unicodeData, err = l.UnicodeData(version); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", l.Path(version, "UnicodeData.txt"), err)
+	}
+	for _, file := range []struct {
+		name  string
+		table *map[string]string
+	}{
+		{"Scripts.txt", &d.scripts},
+		{"DerivedAge.txt", &d.ages},
+		{"Blocks.txt", &d.blocks},
+	} {
+		if *file.table, err = l.PropertyFile(version, file.name); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", l.Path(version, file.name), err)
+		}
+	}
+	return d, nil
+}
+
+// Returns the properties of each code point listed in the appendices A-E, in
+// code point order. Code points missing from a file get the default value of
+// the property in the UCD.
+func propertySnapshots(r *Report, codePointNames2 map[string]string, d *snapshotData) []PropertySnapshot {
+	listed := listedCodePoints(r)
+
+	snapshots := make([]PropertySnapshot, 0, len(listed))
+	for _, codepoint := range listed {
+		entry, assigned := d.unicodeData[codepoint]
+		snapshot := PropertySnapshot{
+			CodePoint:       codepoint,
+			Name:            codePointNames2[codepoint],
+			GeneralCategory: entry.GeneralCategory,
+			Script:          d.scripts[codepoint],
+			BidiClass:       entry.BidiClass,
+			CombiningClass:  entry.CombiningClass,
+			NFK:             formatNFK(d.nfk.mapping(hexToInt(codepoint))),
+			Age:             d.ages[codepoint],
+			Block:           d.blocks[codepoint],
+		}
+		if !assigned {
+			snapshot.GeneralCategory = "Cn"
+			snapshot.CombiningClass = "0"
+		}
+		if snapshot.Script == "" {
+			snapshot.Script = "Unknown"
+		}
+		if snapshot.Age == "" {
+			snapshot.Age = "Unassigned"
+		}
+		if snapshot.Block == "" {
+			snapshot.Block = "No_Block"
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots
+}
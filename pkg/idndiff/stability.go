@@ -0,0 +1,57 @@
+package idndiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Violations of the Unicode stability policies that the derivation of IDNA2008
+// relies on. They indicate errors in the data, or truly exceptional actions
+// by the UTC, and need to be looked at before anything else.
+type stabilityError struct {
+	violations []string
+}
+
+func (e *stabilityError) Error() string {
+	return fmt.Sprintf("%d Unicode stability policy violations:\n%s", len(e.violations), strings.Join(e.violations, "\n"))
+}
+
+// Checks the code points that are assigned in both versions for changes of
+// General Category between letter and nonletter, and for decomposition
+// mappings that were added or changed
+func checkStability(codepoints []int, generalCategory1, generalCategory2 map[string]string, unicodeData1, unicodeData2 map[string]unicodeDataEntry) error {
+	var violations []string
+	for _, codepointInt := range codepoints {
+		codepoint := codepointKey(codepointInt)
+		oldCategory, newCategory := generalCategory1[codepoint], generalCategory2[codepoint]
+		if oldCategory == "" || oldCategory == "Cn" || newCategory == "" || newCategory == "Cn" {
+			continue
+		}
+		name := unicodeData2[codepoint].Name
+
+		oldIsLetter := strings.HasPrefix(oldCategory, "L")
+		newIsLetter := strings.HasPrefix(newCategory, "L")
+		if oldIsLetter && !newIsLetter {
+			violations = append(violations, fmt.Sprintf("U+%s %s: General Category changed from %s to %s (letter to nonletter)", codepoint, name, oldCategory, newCategory))
+		} else if !oldIsLetter && newIsLetter {
+			violations = append(violations, fmt.Sprintf("U+%s %s: General Category changed from %s to %s (nonletter to letter)", codepoint, name, oldCategory, newCategory))
+		}
+
+		oldDecomposition := unicodeData1[codepoint].Decomposition
+		newDecomposition := unicodeData2[codepoint].Decomposition
+		switch {
+		case oldDecomposition == newDecomposition:
+		case oldDecomposition == "":
+			violations = append(violations, fmt.Sprintf("U+%s %s: decomposition %s added", codepoint, name, newDecomposition))
+		case newDecomposition == "":
+			violations = append(violations, fmt.Sprintf("U+%s %s: decomposition %s removed", codepoint, name, oldDecomposition))
+		default:
+			violations = append(violations, fmt.Sprintf("U+%s %s: decomposition changed from %s to %s", codepoint, name, oldDecomposition, newDecomposition))
+		}
+	}
+
+	if len(violations) > 0 {
+		return &stabilityError{violations}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package idndiff
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// The tables of RFC 5892 that are not derived from the UCD are embedded in
+// the binary, so that it works without any adjacent files
+var (
+	//go:embed data/exceptions.txt
+	exceptionsData string
+	//go:embed data/backward_compatible.txt
+	backwardCompatibleData string
+	//go:embed data/ignorable_blocks.txt
+	ignorableBlocksData string
+)
+
+// The embedded Exceptions (F), BackwardCompatible (G) and blocks in
+// IgnorableBlocks (D)
+var embeddedTables = RFC5892Tables{
+	Exceptions:         mustReadTable(exceptionsData),
+	BackwardCompatible: mustReadTable(backwardCompatibleData),
+	IgnorableBlocks:    readBlockNames(ignorableBlocksData),
+}
+
+// The tables of RFC 5892 that the derived property values are computed with
+// besides the UCD. A nil table is the one embedded in the binary.
+type RFC5892Tables struct {
+	Exceptions         map[string]string // Exceptions (F), by code point
+	BackwardCompatible map[string]string // BackwardCompatible (G), by code point
+	IgnorableBlocks    map[string]bool   // The names of the blocks in IgnorableBlocks (D)
+}
+
+// Returns the tables with the embedded ones in place of the nil ones
+func (t RFC5892Tables) withDefaults() RFC5892Tables {
+	if t.Exceptions == nil {
+		t.Exceptions = embeddedTables.Exceptions
+	}
+	if t.BackwardCompatible == nil {
+		t.BackwardCompatible = embeddedTables.BackwardCompatible
+	}
+	if t.IgnorableBlocks == nil {
+		t.IgnorableBlocks = embeddedTables.IgnorableBlocks
+	}
+	return t
+}
+
+// Returns the part of a cache key that tells the tables apart: nothing for
+// the embedded ones, and otherwise a checksum of the tables that replace them
+func (t RFC5892Tables) cacheKey() string {
+	if t.Exceptions == nil && t.BackwardCompatible == nil && t.IgnorableBlocks == nil {
+		return ""
+	}
+	h := sha256.New()
+	for _, table := range []map[string]string{t.Exceptions, t.BackwardCompatible} {
+		if table == nil {
+			fmt.Fprintln(h, "embedded")
+		}
+		for _, codepoint := range slices.Sorted(maps.Keys(table)) {
+			fmt.Fprintf(h, "%s;%s\n", codepoint, table[codepoint])
+		}
+		fmt.Fprintln(h)
+	}
+	if t.IgnorableBlocks == nil {
+		fmt.Fprintln(h, "embedded")
+	}
+	for _, name := range slices.Sorted(maps.Keys(t.IgnorableBlocks)) {
+		fmt.Fprintf(h, "%s;%t\n", name, t.IgnorableBlocks[name])
+	}
+	return "#tables-" + hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// Reads an embedded table of derived property values
+func mustReadTable(data string) map[string]string {
+	table, err := readPropertyFile(strings.NewReader(data), nil)
+	if err != nil {
+		panic(err)
+	}
+	return table
+}
+
+// Reads block names, one per line
+func readBlockNames(data string) map[string]bool {
+	names := make(map[string]bool)
+	scanner := newLineScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		name := strings.TrimSpace(strings.Split(scanner.Text(), "#")[0])
+		if name != "" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// Reads a table in the format of Exceptions (F), with lines like
+// "00DF ; PVALID", for RFC5892Tables.Exceptions or BackwardCompatible
+func ReadValueTableFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return readPropertyFile(strings.NewReader(string(data)), nil)
+}
+
+// Reads block names, one per line, for RFC5892Tables.IgnorableBlocks
+func ReadBlockNamesFile(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return readBlockNames(string(data)), nil
+}
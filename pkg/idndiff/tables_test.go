@@ -0,0 +1,195 @@
+package idndiff
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// The embedded tables have comments with semicolons, which are not entries
+func TestEmbeddedTables(t *testing.T) {
+	hex := regexp.MustCompile(`^[0-9A-F]{4,6}$`)
+	for name, table := range map[string]map[string]string{
+		"data/exceptions.txt":          embeddedTables.Exceptions,
+		"data/backward_compatible.txt": embeddedTables.BackwardCompatible,
+	} {
+		for codepoint := range table {
+			if !hex.MatchString(codepoint) {
+				t.Errorf("%s: invalid code point %q", name, codepoint)
+			}
+		}
+	}
+	if got := embeddedTables.Exceptions["00DF"]; got != "PVALID" {
+		t.Errorf("data/exceptions.txt: U+00DF is %q, want PVALID", got)
+	}
+}
+
+// Tables replacing the embedded ones change the derived property values, and
+// one Loader keeps the values derived with each apart
+func TestLoaderTables(t *testing.T) {
+	loader := NewLoader(filepath.Join("testdata", "derivation"))
+	for _, test := range []struct {
+		tables              RFC5892Tables
+		codepoint, property string
+	}{
+		{RFC5892Tables{}, "00DF", "PVALID"},
+		{RFC5892Tables{Exceptions: map[string]string{"0041": "PVALID"}}, "0041", "PVALID"},
+		{RFC5892Tables{Exceptions: map[string]string{"0041": "PVALID"}}, "00DF", "DISALLOWED"},
+		{RFC5892Tables{BackwardCompatible: map[string]string{"0378": "CONTEXTO"}}, "0378", "CONTEXTO"},
+		{RFC5892Tables{IgnorableBlocks: map[string]bool{}}, "20D0", "PVALID"},
+		{RFC5892Tables{}, "20D0", "DISALLOWED"},
+		{RFC5892Tables{}, "0041", "DISALLOWED"},
+	} {
+		loader.Tables = test.tables
+		properties, _, err := loader.DerivedProperties("6.0.0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := properties[test.codepoint]; got != test.property {
+			t.Errorf("%+v: U+%s is %s, want %s", test.tables, test.codepoint, got, test.property)
+		}
+	}
+}
+
+// Normalizations that need the recursive decomposition, the composition
+// exclusions and the Hangul composition to be computed right
+func TestNFKC(t *testing.T) {
+	unicodeData := map[string]unicodeDataEntry{
+		"0041": {}, "0308": {CombiningClass: "230"}, "0301": {CombiningClass: "230"}, "0307": {CombiningClass: "230"},
+		"00C4": {Decomposition: "0041 0308"},
+		"00BD": {Decomposition: "<fraction> 0031 2044 0032"},
+		"0344": {Decomposition: "0308 0301"},
+		"1E9B": {Decomposition: "017F 0307"}, "017F": {Decomposition: "<compat> 0073"}, "1E61": {Decomposition: "0073 0307"},
+		"212B": {Decomposition: "00C5"}, "00C5": {Decomposition: "0041 030A"}, "030A": {CombiningClass: "230"},
+		"FB1F": {Decomposition: "05F2 05B7"}, "05B7": {CombiningClass: "17"},
+	}
+	exclusions := map[string]bool{"0344": true, "212B": true, "FB1F": true}
+	n, err := newNormalizer(unicodeData, exclusions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for codepoint, want := range map[rune]string{
+		0x00C4: "00C4",
+		0x00BD: "0031 2044 0032",
+		0x0344: "0308 0301",
+		0x1E9B: "1E61",
+		0x212B: "00C5",
+		0xFB1F: "05F2 05B7",
+		0xAC00: "AC00",
+		0xD4DB: "D4DB",
+		0x0041: "0041",
+	} {
+		if got := formatNFK(n.nfkc(codepoint)); got != want {
+			t.Errorf("U+%04X: got %s, want %s", codepoint, got, want)
+		}
+	}
+}
+
+// A changed mapping is found in the code points that decompose to it too,
+// in the compatibility decomposition only if the mapping is a compatibility one
+func TestCompareDecompositionMappings(t *testing.T) {
+	unicodeData1 := map[string]unicodeDataEntry{
+		"0073": {}, "0307": {}, "1E61": {Decomposition: "0073 0307"},
+		"017F": {Decomposition: "<compat> 0073"}, "1E9B": {Decomposition: "017F 0307"},
+	}
+	unicodeData2 := map[string]unicodeDataEntry{
+		"0073": {}, "0307": {}, "1E61": {Decomposition: "0073 0307"},
+		"017F": {Decomposition: "<font> 0073 0073"}, "1E9B": {Decomposition: "017F 0307"},
+	}
+	properties := map[string]string{"0073": "PVALID", "0307": "PVALID", "1E61": "PVALID", "017F": "PVALID", "1E9B": "PVALID"}
+	changes, err := compareDecompositionMappings([]int{0x0073, 0x017F, 0x0307, 0x1E61, 0x1E9B}, properties, properties, nil, unicodeData1, unicodeData2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DecompositionMappingChange{
+		{"017F", "017F", "017F", "0073", "0073 0073", "PVALID", "PVALID", ""},
+		{"1E9B", "017F 0307", "017F 0307", "0073 0307", "0073 0073 0307", "PVALID", "PVALID", ""},
+	}
+	if !slices.Equal(changes, want) {
+		t.Errorf("got %v, want %v", changes, want)
+	}
+}
+
+// The backends must agree on the code points in nfk.txt that are right, and
+// on those it leaves out, which map to themselves
+func TestCheckNormalizationBackends(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "14.0.0")
+	for name, content := range map[string]string{
+		"UnicodeData.txt":               "0041;LATIN CAPITAL LETTER A;Lu;0;L;;;;;N;;;;0061;\n00BD;VULGAR FRACTION ONE HALF;No;0;ON;<fraction> 0031 2044 0032;;;1/2;N;;;;;\n00C4;LATIN CAPITAL LETTER A WITH DIAERESIS;Lu;0;L;0041 0308;;;;N;;;;00E4;\n0308;COMBINING DIAERESIS;Mn;230;NSM;;;;;N;;;;;\n0344;COMBINING GREEK DIALYTIKA TONOS;Mn;230;NSM;0308 0301;;;;N;;;;;\n",
+		"DerivedNormalizationProps.txt": "0344 ; Full_Composition_Exclusion\n",
+		"nfk.txt":                       "U+00BD;0031;2044;0032\nU+0344;0308;0302\n",
+	} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check, err := CheckNormalizationBackends(NewLoader(filepath.Dir(dir)), "14.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(check.Differences) != 1 || check.Differences[0].CodePoint != "0344" {
+		t.Fatalf("got differences %v, want U+0344 only", check.Differences)
+	}
+	if got := check.Differences[0].NFKC; got[0] != "0308 0301" || got[1] != "0308 0302" {
+		t.Errorf("U+0344: got %v", got)
+	}
+	if !slices.Equal(check.Skipped, []string{"x/text"}) {
+		t.Errorf("got %v skipped, want x/text", check.Skipped)
+	}
+
+	// An nfk.txt written by WriteNFKTable is the output of the internal
+	// backend, and not compared with it
+	var generated strings.Builder
+	if err := WriteNFKTable(&generated, NewLoader(filepath.Dir(dir)), "14.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "nfk.txt"), []byte(generated.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CheckNormalizationBackends(NewLoader(filepath.Dir(dir)), "14.0.0"); err == nil {
+		t.Errorf("a generated nfk.txt was compared with the internal backend")
+	}
+}
+
+// The x/text backend normalizes with golang.org/x/text/unicode/norm, and
+// only for the version of Unicode it implements
+func TestXTextNormalizationBackend(t *testing.T) {
+	var open NormalizationBackendOpener
+	for _, backend := range normalizationBackends {
+		if backend.name == "x/text" {
+			open = backend.open
+		}
+	}
+	loader := NewLoader(t.TempDir())
+	if _, err := open(loader, "14.0.0"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("opened for 14.0.0 with %v", err)
+	}
+	backend, err := open(loader, norm.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		codepoint rune
+		nfkc      string
+	}{
+		{0x0041, "0041"},
+		{0x00BD, "0031 2044 0032"},
+		{0x0344, "0308 0301"},
+		{0xAC00, "AC00"},
+		{0xD800, "D800"},
+	} {
+		if got := formatNFK(backend.NFKC(test.codepoint)); got != test.nfkc {
+			t.Errorf("U+%04X: got %s, want %s", test.codepoint, got, test.nfkc)
+		}
+	}
+}
@@ -0,0 +1,1041 @@
+package idndiff
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+// Renders the report as text: a summary of the comparison followed by the
+// appendices
+func RenderText(w io.Writer, r *Report, opts RenderOptions) error {
+	var buffer strings.Builder
+	renderSummary(&buffer, r, opts.Glossary["text"])
+	renderAppendices(&buffer, r, opts)
+	fmt.Fprintf(&buffer, "===================\n")
+	_, err := io.WriteString(w, foldLines(buffer.String(), opts.LineWidth))
+	return err
+}
+
+// A part of the text report: the summary, or one of the appendices
+type TextSection struct {
+	Name string // "summary", or the letter of the appendix
+	Text string
+}
+
+// Renders the summary and each appendix of the text report on its own, so
+// that they can be written to separate files
+func RenderTextSections(r *Report, opts RenderOptions) []TextSection {
+	var summary strings.Builder
+	renderSummary(&summary, r, opts.Glossary["text"])
+	sections := []TextSection{{"summary", foldLines(summary.String(), opts.LineWidth)}}
+	for _, appendix := range reportAppendices(r) {
+		var buffer strings.Builder
+		appendix.text(&buffer, appendix.heading(), opts)
+		text, _ := truncateEntries(buffer.String(), appendix.letter, opts)
+		sections = append(sections, TextSection{appendix.letter, foldLines(strings.TrimLeft(text, "\n"), opts.LineWidth)})
+	}
+	return sections
+}
+
+// A line of an appendix that is an entry, for a code point or a range
+var entryLineRegex = regexp.MustCompile(`^(U\+)?[0-9A-F]{4,6}`)
+
+// Returns the text of an appendix with at most MaxEntries entries, the rest
+// replaced by a comment pointing to the overflow file, and whether any were
+// left out. Comments, such as the counts after Appendix A, are kept.
+func truncateEntries(text, letter string, opts RenderOptions) (string, bool) {
+	if opts.MaxEntries <= 0 {
+		return text, false
+	}
+	lines := strings.SplitAfter(text, "\n")
+	total := 0
+	for _, line := range lines {
+		if entryLineRegex.MatchString(line) {
+			total++
+		}
+	}
+	if total <= opts.MaxEntries {
+		return text, false
+	}
+	var truncated strings.Builder
+	listed := 0
+	for _, line := range lines {
+		if entryLineRegex.MatchString(line) {
+			listed++
+			if listed == opts.MaxEntries+1 {
+				fmt.Fprintf(&truncated, "# %d more, all %d entries are in %s\n", total-opts.MaxEntries, total, opts.OverflowFileName(letter))
+			}
+			if listed > opts.MaxEntries {
+				continue
+			}
+		}
+		truncated.WriteString(line)
+	}
+	return truncated.String(), true
+}
+
+// Returns the appendices with more entries than MaxEntries in full, to write
+// to their overflow files
+func OverflowSections(r *Report, opts RenderOptions) []TextSection {
+	var sections []TextSection
+	for _, appendix := range reportAppendices(r) {
+		var buffer strings.Builder
+		appendix.text(&buffer, appendix.heading(), opts)
+		if _, truncated := truncateEntries(buffer.String(), appendix.letter, opts); truncated {
+			sections = append(sections, TextSection{appendix.letter, foldLines(strings.TrimLeft(buffer.String(), "\n"), opts.LineWidth)})
+		}
+	}
+	return sections
+}
+
+// Folds the lines longer than width at spaces, as in RFC 5322 section 2.2.3:
+// each continuation line starts with white space, so the lines are unfolded
+// by joining a line starting with white space to the previous one. Lines
+// without a space to fold at are left as they are.
+func foldLines(text string, width int) string {
+	const indent = "   "
+	if width <= 0 {
+		return text
+	}
+	var folded strings.Builder
+	for _, line := range strings.SplitAfter(text, "\n") {
+		prefix := ""
+		for utf8.RuneCountInString(prefix+strings.TrimSuffix(line, "\n")) > width {
+			// Fold at the last space that fits, or else at the first one
+			limit := len(line)
+			for i := range line {
+				if utf8.RuneCountInString(prefix+line[:i]) > width {
+					limit = i
+					break
+				}
+			}
+			at := strings.LastIndex(line[:limit], " ")
+			if at <= 0 {
+				at = strings.Index(line, " ")
+			}
+			if at <= 0 || strings.TrimSpace(line[at:]) == "" {
+				break
+			}
+			folded.WriteString(prefix + strings.TrimRight(line[:at], " ") + "\n")
+			line = strings.TrimLeft(line[at:], " ")
+			prefix = indent
+		}
+		folded.WriteString(prefix + line)
+	}
+	return folded.String()
+}
+
+// Writes the summary of the comparison, with the identifiers in the prose
+// written with the terms of a Glossary
+func renderSummary(buffer *strings.Builder, r *Report, terms map[string]string) {
+	fmt.Fprintf(buffer, "Comparing version %s and %s\n", r.Version1, r.Version2)
+	for _, warning := range r.Warnings {
+		fmt.Fprintf(buffer, "WARNING: %s\n", warning)
+	}
+	if len(r.Errors) > 0 {
+		fmt.Fprintf(buffer, "ERRORS: requested sections left out of the report, use -fail-fast to stop instead\n")
+		for _, e := range r.Errors {
+			fmt.Fprintf(buffer, "ERROR: %s skipped: %s\n", e.Section, e.Error)
+		}
+	}
+	if r.JoinControl != nil {
+		fmt.Fprintf(buffer, "ALERT: Join_Control is not only U+200C and U+200D, which the CONTEXTJ rules of RFC 5892 assume\n")
+		for _, version := range []struct {
+			name       string
+			codepoints []CodePoint
+		}{{r.Version1, r.JoinControl.CodePoints1}, {r.Version2, r.JoinControl.CodePoints2}} {
+			var listed []string
+			for _, entry := range version.codepoints {
+				listed = append(listed, fmt.Sprintf("U+%s %s", entry.CodePoint, isolate(entry.Name)))
+			}
+			fmt.Fprintf(buffer, "Join_Control in Unicode %s: %s\n", version.name, strings.Join(listed, ", "))
+		}
+	}
+	fmt.Fprintf(buffer, "Comparing derived property values\n")
+	for _, change := range r.AppendixA {
+		fmt.Fprintf(buffer, "%s changed from %s to %s\n", change.CodePoint, prose(terms, change.Old), prose(terms, change.New))
+	}
+	fmt.Fprintf(buffer, "Count changes in derived property values\n")
+
+	fmt.Fprintf(buffer, "Count code points with CONTEXTJ and CONTEXTO\n")
+	table := newTable(buffer)
+	// Without the derived property values there is nothing to count
+	if !r.Skipped("A") {
+		fmt.Fprintf(table, "Version\tCONTEXTJ\tCONTEXTO\n")
+		fmt.Fprintf(table, "%s\t%d\t%d\n", r.Version1, r.ContextRules.ContextJ1, r.ContextRules.ContextO1)
+		fmt.Fprintf(table, "%s\t%d\t%d\n", r.Version2, r.ContextRules.ContextJ2, r.ContextRules.ContextO2)
+		table.Flush()
+	}
+	for _, change := range r.ContextRules.Changes {
+		fmt.Fprintf(buffer, "Code point U+%s changed from %s to %s: %s\n", change.CodePoint, prose(terms, change.Old), prose(terms, change.New), isolate(change.Name))
+	}
+
+	fmt.Fprintf(buffer, "Reading General Category definitions\n")
+	fmt.Fprintf(buffer, "Check changes in General Category:\n")
+	for _, change := range r.AppendixB {
+		fmt.Fprintf(buffer, "Code point U+%s changed from %s to %s (General Category: %s to %s)\n",
+			change.CodePoint, prose(terms, change.OldProperty), prose(terms, change.NewProperty), prose(terms, change.Old), prose(terms, change.New))
+	}
+
+	fmt.Fprintf(buffer, "Count code points with General_Category Mn\n")
+	table = newTable(buffer)
+	if !r.Skipped("C") {
+		fmt.Fprintf(table, "Version\tCode points with General_Category Mn\n")
+		fmt.Fprintf(table, "%s\t%d\n", r.Version1, r.MnCount1)
+		fmt.Fprintf(table, "%s\t%d\n", r.Version2, r.MnCount2)
+		fmt.Fprintf(table, "Increase\t%d\n", r.MnCount2-r.MnCount1)
+		table.Flush()
+	}
+
+	fmt.Fprintf(buffer, "\nCheck changes in NFK for all code points\n")
+	// Changed and new normalizations in code point order
+	changes, additions := r.NFKChanges, r.AppendixD
+	for len(changes) > 0 || len(additions) > 0 {
+		if len(additions) == 0 || (len(changes) > 0 && hexToInt(changes[0].CodePoint) <= hexToInt(additions[0].CodePoint)) {
+			change := changes[0]
+			fmt.Fprintf(buffer, "Changed normalization for code point %s (%s %s): %s : %s\n", change.CodePoint, prose(terms, change.OldProperty), prose(terms, change.NewProperty), change.Old, change.New)
+			changes = changes[1:]
+		} else {
+			fmt.Fprintf(buffer, "New code point to normalize %s %s\n", additions[0].CodePoint, additions[0].NFK)
+			additions = additions[1:]
+		}
+	}
+	if len(r.NFKChanges) == 0 {
+		fmt.Fprintln(buffer, "No change in NFK")
+	}
+	fmt.Fprintln(buffer, "Number of new code points with NFK normalization: ", len(r.AppendixD))
+
+	fmt.Fprintf(buffer, "\n")
+	table = newTable(buffer)
+	fmt.Fprintf(table, "Appendix\tEntries\tContents\n")
+	fmt.Fprintf(table, "A\t%s\tCode points that changed derived property values\n", entries(r, "A", len(r.AppendixA)))
+	fmt.Fprintf(table, "B\t%s\tChanges in General Category\n", entries(r, "B", len(r.AppendixB)))
+	fmt.Fprintf(table, "C\t%s\tNew code points where General Category is Mn\n", entries(r, "C", len(r.AppendixC)))
+	fmt.Fprintf(table, "D\t%s\tNew code points with NFK normalization\n", entries(r, "D", len(r.AppendixD)))
+	contentsE := "Additions to Exceptions (F)"
+	if numExcluded := countExcluded(r.AppendixE); numExcluded > 0 {
+		contentsE += fmt.Sprintf(", %d excluded from review", numExcluded)
+	}
+	if len(r.Resolved) > 0 {
+		contentsE += fmt.Sprintf(", %d more already resolved", len(r.Resolved))
+	}
+	fmt.Fprintf(table, "E\t%s\t%s\n", entries(r, "E", len(r.AppendixE)), contentsE)
+	fmt.Fprintf(table, "F\t%s\tRanges of derived property values\n", entries(r, "F", len(r.AppendixF)))
+	table.Flush()
+
+	renderSectionCounts(buffer, r)
+
+	if r.Narrative != nil {
+		fmt.Fprintf(buffer, "\nChanges in Unicode %s affecting IDNA\n\n%s\n", r.Version2, r.Narrative.Text)
+	}
+}
+
+// Writes a line with the counts of each optional section, after the table of
+// appendices
+func renderSectionCounts(buffer *strings.Builder, r *Report) {
+	if r.Registry != "" {
+		counts := make(map[string]int)
+		for _, entry := range r.AppendixE {
+			counts[entry.Registry]++
+		}
+		fmt.Fprintf(buffer, "Appendix E compared with %s: %d new, %d already listed with the same value, %d conflicting\n",
+			r.Registry, counts[RegistryNew], counts[RegistryListed], counts[RegistryConflict])
+	}
+
+	if len(r.AppendixFGaps) > 0 {
+		missing := 0
+		for _, gap := range r.AppendixFGaps {
+			missing += hexToInt(gap.End) - hexToInt(gap.Start) + 1
+		}
+		fmt.Fprintf(buffer, "WARNING: %d code points missing from allcodepoints.txt for version %s are not covered by Appendix F\n", missing, r.Version2)
+	}
+
+	if r.NFKHazards != nil {
+		fmt.Fprintf(buffer, "Number of PVALID code points with NFK normalization that now includes other derived property values: %d\n", len(r.NFKHazards.Entries))
+	}
+
+	if r.NFKCCaseFold != nil {
+		fmt.Fprintf(buffer, "Number of assigned code points with NFKC_Casefold mapping changes: %d\n", len(r.NFKCCaseFold.Changes))
+	}
+
+	if r.CaseFolding != nil {
+		fmt.Fprintf(buffer, "Number of assigned code points with case folding changes: %d\n", len(r.CaseFolding.Changes))
+	}
+
+	if r.DecompositionTypes != nil {
+		fmt.Fprintf(buffer, "Number of assigned code points with decomposition type changes: %d\n", len(r.DecompositionTypes.Changes))
+	}
+
+	if r.DecompositionMappings != nil {
+		fmt.Fprintf(buffer, "Number of assigned code points with decomposition mapping changes: %d\n", len(r.DecompositionMappings.Changes))
+	}
+
+	if r.BidiClasses != nil {
+		fmt.Fprintf(buffer, "Number of assigned code points with Bidi_Class changes: %d\n", len(r.BidiClasses.Changes))
+	}
+
+	if r.JoiningTypes != nil {
+		fmt.Fprintf(buffer, "Number of assigned code points with Joining_Type changes: %d\n", len(r.JoiningTypes.Changes))
+	}
+
+	if r.ScriptChanges != nil {
+		fmt.Fprintf(buffer, "Number of assigned code points with Script changes: %d\n", len(r.ScriptChanges.Changes))
+	}
+
+	if r.CombiningClasses != nil {
+		fmt.Fprintf(buffer, "Number of assigned code points with Canonical_Combining_Class changes: %d (to or from Virama: %d)\n", len(r.CombiningClasses.Changes), r.CombiningClasses.Virama)
+	}
+
+	if r.AppendixCScripts != nil {
+		var counts []string
+		for _, group := range r.AppendixCScripts {
+			counts = append(counts, fmt.Sprintf("%s %d", group.Script, len(group.CodePoints)))
+		}
+		fmt.Fprintf(buffer, "New code points with General Category Mn per script: %s\n", strings.Join(counts, ", "))
+	}
+
+	if r.MarkRendering != nil {
+		counts := r.MarkRendering.counts()
+		fmt.Fprintf(buffer, "New code points with General Category Mn likely rendered spacing: %d, invisible: %d, unknown: %d\n",
+			counts[RenderingSpacing], counts[RenderingInvisible], counts[RenderingUnknown])
+	}
+
+	if r.CaseConsistency != nil {
+		fmt.Fprintf(buffer, "Newly assigned case pairs checked: %d, with unexpected derived property values: %d\n",
+			r.CaseConsistency.Checked, len(r.CaseConsistency.Anomalies))
+	}
+
+	if r.GCConsistency != nil {
+		for _, version := range r.GCConsistency.Versions {
+			fmt.Fprintf(buffer, "Code points in version %s with a General_Category but no derived property value: %d, with a derived property value but no General_Category: %d\n",
+				version.Version, version.MissingPropertyCount, version.MissingCategoryCount)
+		}
+	}
+
+	if r.Frequencies != nil {
+		for _, total := range []struct {
+			version string
+			count   int
+		}{{r.Version1, r.Frequencies.Total1}, {r.Version2, r.Frequencies.Total2}} {
+			fmt.Fprintf(buffer, "Number of code points with a derived property value in version %s: %d\n", total.version, total.count)
+			if total.count != codeSpaceSize {
+				fmt.Fprintf(buffer, "WARNING: expected %d code points in version %s\n", codeSpaceSize, total.version)
+			}
+		}
+	}
+
+	if r.NewAssignments != nil {
+		fmt.Fprintf(buffer, "Newly assigned code points: %d, in %d ranges\n", r.NewAssignments.Count, len(r.NewAssignments.Ranges))
+	}
+
+	if r.BidiImpact != nil {
+		fmt.Fprintf(buffer, "Number of code points that became valid and matter for the Bidi Rule: %d\n", len(r.BidiImpact.Entries))
+	}
+
+	if r.CrossCheck != nil {
+		differences := 0
+		for _, appendix := range r.CrossCheck.Appendices {
+			differences += len(appendix.OnlyPublished) + len(appendix.OnlyComputed)
+		}
+		fmt.Fprintf(buffer, "Differences from the appendices of %s: %d\n", r.CrossCheck.Document, differences)
+	}
+
+	if r.UTS46 != nil {
+		fmt.Fprintf(buffer, "Code points compared with UTS #46: %d, with discrepancies: %d\n", r.UTS46.Checked, len(r.UTS46.Discrepancies))
+	}
+
+	if r.Homoglyphs != nil {
+		fmt.Fprintf(buffer, "Code points that became PVALID: %d, confusable: %d\n", r.Homoglyphs.Checked, len(r.Homoglyphs.Entries))
+	}
+
+	if r.Informational != nil {
+		fmt.Fprintf(buffer, "Code points whose derived property value held despite related changes: %d\n", len(r.Informational.Entries))
+	}
+
+	if r.RootCauses != nil {
+		var counts []string
+		for _, change := range r.RootCauses.ruleChanges() {
+			counts = append(counts, fmt.Sprintf("%s to %s %d", change.old, change.new, change.count))
+		}
+		fmt.Fprintf(buffer, "Rules of RFC 5892 that decided the changes in Appendix A: %s\n", strings.Join(counts, ", "))
+	}
+
+	if r.RestrictedScripts != nil {
+		fmt.Fprintf(buffer, "Code points that became PVALID in scripts restricted by %s: %d\n", r.RestrictedScripts.Policy, len(r.RestrictedScripts.Entries))
+	}
+
+	for _, findings := range r.Findings {
+		fmt.Fprintf(buffer, "Code points found by the detector %s: %d\n", findings.Detector, len(findings.Findings))
+	}
+
+	if r.Exceptions != nil {
+		fmt.Fprintf(buffer, "Comparing Exceptions (F) with RFC 5892\n")
+		fmt.Fprintf(buffer, "Exceptions compared with RFC 5892 for Unicode %s: %d additions, %d removals, %d value changes\n",
+			r.Version2, len(r.Exceptions.Additions), len(r.Exceptions.Removals), len(r.Exceptions.ValueChanges))
+	}
+}
+
+// Returns the number of entries in an appendix for the table of appendices,
+// or that the appendix was skipped
+func entries(r *Report, appendix string, count int) string {
+	if r.Skipped(appendix) {
+		return "skipped"
+	}
+	return strconv.Itoa(count)
+}
+
+// Returns a writer that aligns the tab separated columns of a table
+func newTable(buffer *strings.Builder) *tabwriter.Writer {
+	return tabwriter.NewWriter(buffer, 0, 0, 2, ' ', 0)
+}
+
+// Returns how a candidate for Appendix E compares with the IANA registry, to
+// append to its name, if it was compared
+func registryNote(entry ExceptionCandidate) string {
+	switch entry.Registry {
+	case "":
+		return ""
+	case RegistryConflict:
+		return fmt.Sprintf(" (IANA: %s %s)", registryStatusText[entry.Registry], entry.RegistryValue)
+	}
+	return fmt.Sprintf(" (IANA: %s)", registryStatusText[entry.Registry])
+}
+
+// Returns the number of candidates that are excluded from review
+func countExcluded(appendixE []ExceptionCandidate) int {
+	numExcluded := 0
+	for _, entry := range appendixE {
+		if entry.Excluded {
+			numExcluded++
+		}
+	}
+	return numExcluded
+}
+
+// Returns a code point as the first column of an entry of the appendices
+// A-E, followed by its age if the report has the ages
+func entryLabel(r *Report, opts RenderOptions, codepoint string) string {
+	return strings.Join(ageValues(r, codepoint, opts.codePointLabel(codepoint)), "; ")
+}
+
+// Returns the first column of the header of the appendices A-E, followed by
+// the age if the report has the ages
+func entryHeader(r *Report) string {
+	return strings.Join(ageColumns(r, "Code point"), "; ")
+}
+
+// Writes the appendices
+func renderAppendices(buffer *strings.Builder, r *Report, opts RenderOptions) {
+	for _, appendix := range reportAppendices(r) {
+		var text strings.Builder
+		appendix.text(&text, appendix.heading(), opts)
+		truncated, _ := truncateEntries(text.String(), appendix.letter, opts)
+		buffer.WriteString(truncated)
+	}
+}
+
+// Writes Appendix A, and the number of code points per change of derived
+// property value
+func renderAppendixA(buffer *strings.Builder, heading string, r *Report, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	for i, change := range r.AppendixA {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# %s; Old; New; Name\n", entryHeader(r))
+		}
+		fmt.Fprintf(buffer, "%s; %s; %s; %s\n", entryLabel(r, opts, change.CodePoint), change.Old, change.New, isolate(change.Name))
+	}
+	if r.Skipped("A") {
+		fmt.Fprintf(buffer, "# Skipped, see the warnings in the summary\n")
+		return
+	}
+	if len(r.AppendixA) == 0 {
+		fmt.Fprintf(buffer, "# No change in derived property value except from UNASSIGED\n")
+	}
+
+	// Summary of changes
+	if len(r.ChangeCounts) > 0 {
+		var totalCount int
+		var sortedChanges []string
+		for _, change := range r.ChangeCounts {
+			totalCount += change.Count
+			theWord := "points"
+			if change.Count == 1 {
+				theWord = "point"
+			}
+			sortedChanges = append(sortedChanges, fmt.Sprintf("# %d code %s changed from %s to %s", change.Count, theWord, change.Old, change.New))
+		}
+		sort.Strings(sortedChanges)
+		for _, change := range sortedChanges {
+			fmt.Fprintln(buffer, change)
+		}
+		theWord := "points"
+		if totalCount == 1 {
+			theWord = "point"
+		}
+		fmt.Fprintf(buffer, "# %d code %s changed in total\n", totalCount, theWord)
+	} else {
+		fmt.Fprintf(buffer, "# No derived property changes detected.\n")
+	}
+}
+
+// Writes Appendix B
+func renderAppendixB(buffer *strings.Builder, heading string, r *Report, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n\n%s\n\n", heading)
+	for i, change := range r.AppendixB {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# %s; Old GC; New GC; Name\n\n", entryHeader(r))
+		}
+		fmt.Fprintf(buffer, "%s; %s; %s; %s\n", entryLabel(r, opts, change.CodePoint), change.Old, change.New, isolate(change.Name))
+	}
+	if r.Skipped("B") {
+		fmt.Fprintf(buffer, "# Skipped, see the warnings in the summary\n")
+	} else if len(r.AppendixB) == 0 {
+		fmt.Fprintf(buffer, "# No changes in General Category detected\n")
+	}
+}
+
+// Writes Appendix C
+func renderAppendixC(buffer *strings.Builder, heading string, r *Report, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n\n%s\n\n", heading)
+	if r.AppendixCScripts != nil {
+		fmt.Fprintf(buffer, "# %s; Name\n", entryHeader(r))
+		for _, group := range r.AppendixCScripts {
+			count := fmt.Sprintf("%d code points", len(group.CodePoints))
+			if len(group.CodePoints) == 1 {
+				count = "1 code point"
+			}
+			fmt.Fprintf(buffer, "\n# %s: %s\n", group.Script, count)
+			for _, entry := range group.CodePoints {
+				fmt.Fprintf(buffer, "%s; %s\n", entryLabel(r, opts, entry.CodePoint), isolate(entry.Name))
+			}
+		}
+		return
+	}
+	for i, entry := range r.AppendixC {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# %s; Name\n", entryHeader(r))
+		}
+		fmt.Fprintf(buffer, "%s; %s\n", entryLabel(r, opts, entry.CodePoint), isolate(entry.Name))
+	}
+	if r.Skipped("C") {
+		fmt.Fprintf(buffer, "# Skipped, see the warnings in the summary\n")
+	} else if len(r.AppendixC) == 0 {
+		fmt.Fprintf(buffer, "# No new code points with General Category Mn\n")
+	}
+}
+
+// Writes Appendix D
+func renderAppendixD(buffer *strings.Builder, heading string, r *Report, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n\n%s\n\n", heading)
+	for _, entry := range r.AppendixD {
+		fmt.Fprintf(buffer, "%s; %s; %s\n", entryLabel(r, opts, entry.CodePoint), entry.NFK, isolate(entry.Name))
+	}
+	if r.Skipped("D") {
+		fmt.Fprintf(buffer, "# Skipped, see the warnings in the summary\n")
+	} else if len(r.AppendixD) == 0 {
+		fmt.Fprintf(buffer, "# No new code points with NFK normalization\n")
+	}
+}
+
+// Writes Appendix E, and the candidates that were already resolved
+func renderAppendixE(buffer *strings.Builder, heading string, r *Report, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	for _, entry := range r.AppendixE {
+		if entry.Excluded {
+			fmt.Fprintf(buffer, "%s; EXCLUDED FROM REVIEW (%s) # %s\n", entryLabel(r, opts, entry.CodePoint), entry.ExclusionReason, isolate(entry.Name))
+		} else {
+			fmt.Fprintf(buffer, "%s; UNDER REVIEW # %s%s\n", entryLabel(r, opts, entry.CodePoint), isolate(entry.Name), registryNote(entry))
+		}
+	}
+	if r.Skipped("E") {
+		fmt.Fprintf(buffer, "# Skipped, see the warnings in the summary\n")
+	} else if len(r.AppendixE) == 0 {
+		fmt.Fprintf(buffer, "# No additional code points to become UNDER REVIEW\n")
+	}
+	if len(r.Resolved) > 0 {
+		fmt.Fprintf(buffer, "\nAlready resolved\n\n")
+		for _, entry := range r.Resolved {
+			fmt.Fprintf(buffer, "%s; %s", entryLabel(r, opts, entry.CodePoint), entry.Outcome)
+			if entry.Note != "" {
+				fmt.Fprintf(buffer, " (%s)", entry.Note)
+			}
+			fmt.Fprintf(buffer, " # %s\n", isolate(entry.Name))
+		}
+	}
+}
+
+// Writes Appendix F, and the ranges missing from it
+func renderAppendixF(buffer *strings.Builder, heading string, r *Report) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	if r.Skipped("F") {
+		fmt.Fprintf(buffer, "# Skipped, see the warnings in the summary\n")
+	}
+	if r.AppendixFStyle == "iana" {
+		renderIANARanges(buffer, r)
+		return
+	}
+	for _, entry := range r.AppendixF {
+		if entry.Start == entry.End {
+			fmt.Fprintf(buffer, "U+%s; %s", entry.Start, entry.Property)
+		} else {
+			fmt.Fprintf(buffer, "U+%s..U+%s; %s", entry.Start, entry.End, entry.Property)
+		}
+		if len(entry.Categories) > 0 {
+			fmt.Fprintf(buffer, " # %s", strings.Join(entry.Categories, ", "))
+		}
+		fmt.Fprintf(buffer, "\n")
+	}
+	for _, gap := range r.AppendixFGaps {
+		if gap.Start == gap.End {
+			fmt.Fprintf(buffer, "# U+%s; missing from allcodepoints.txt\n", gap.Start)
+		} else {
+			fmt.Fprintf(buffer, "# U+%s..U+%s; missing from allcodepoints.txt\n", gap.Start, gap.End)
+		}
+	}
+}
+
+// Writes Appendix F in the CSV format of the IANA registry, followed by the
+// ranges missing from it
+func renderIANARanges(buffer *strings.Builder, r *Report) {
+	writer := csv.NewWriter(buffer)
+	writer.Write(ianaHeader)
+	for _, entry := range r.AppendixF {
+		writer.Write(ianaRecord(entry))
+	}
+	writer.Flush()
+	for _, gap := range r.AppendixFGaps {
+		fmt.Fprintf(buffer, "# %s-%s missing from allcodepoints.txt\n", gap.Start, gap.End)
+	}
+}
+
+// Writes the comparison of Exceptions (F) with RFC 5892
+func renderExceptions(buffer *strings.Builder, heading string, comparison *ExceptionsComparison, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+
+	fmt.Fprintf(buffer, "# Additions\n")
+	for _, entry := range comparison.Additions {
+		fmt.Fprintf(buffer, "%s; %s # %s\n", opts.codePointLabel(entry.CodePoint), entry.Value, isolate(entry.Name))
+	}
+	if len(comparison.Additions) == 0 {
+		fmt.Fprintf(buffer, "# No additions\n")
+	}
+
+	fmt.Fprintf(buffer, "# Removals\n")
+	for _, entry := range comparison.Removals {
+		fmt.Fprintf(buffer, "%s; %s # %s\n", opts.codePointLabel(entry.CodePoint), entry.Value, isolate(entry.Name))
+	}
+	if len(comparison.Removals) == 0 {
+		fmt.Fprintf(buffer, "# No removals\n")
+	}
+
+	fmt.Fprintf(buffer, "# Value changes\n")
+	for i, entry := range comparison.ValueChanges {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# Code point; Published; Proposed; Name\n")
+		}
+		fmt.Fprintf(buffer, "%s; %s; %s; %s\n", opts.codePointLabel(entry.CodePoint), entry.Published, entry.Proposed, isolate(entry.Name))
+	}
+	if len(comparison.ValueChanges) == 0 {
+		fmt.Fprintf(buffer, "# No value changes\n")
+	}
+}
+
+// Writes the code points with a normalization that now includes other derived
+// property values
+func renderNFKHazards(buffer *strings.Builder, heading string, hazards *NFKHazards, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	for i, entry := range hazards.Entries {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# Code point; Old NFK; New NFK; Name # Code points in new NFK\n")
+		}
+		var targets []string
+		for _, target := range entry.Targets {
+			targets = append(targets, fmt.Sprintf("U+%s %s", target.CodePoint, target.Property))
+		}
+		fmt.Fprintf(buffer, "%s; %s; %s; %s # %s\n", opts.codePointLabel(entry.CodePoint), entry.Old, entry.New, isolate(entry.Name), strings.Join(targets, ", "))
+	}
+	if len(hazards.Entries) == 0 {
+		fmt.Fprintf(buffer, "# No PVALID code points with NFK normalization that now includes other derived property values\n")
+	}
+}
+
+// Writes the code points with NFKC_Casefold mapping changes
+func renderNFKCCaseFold(buffer *strings.Builder, heading string, caseFold *NFKCCaseFoldChanges, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	for i, change := range caseFold.Changes {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# Code point; Old NFKC_CF; New NFKC_CF; Old derived property value; New derived property value\n")
+		}
+		fmt.Fprintf(buffer, "%s; %s; %s; %s; %s\n", opts.codePointLabel(change.CodePoint), caseFoldMapping(change.Old), caseFoldMapping(change.New), change.OldProperty, change.NewProperty)
+	}
+	if len(caseFold.Changes) == 0 {
+		fmt.Fprintf(buffer, "# No NFKC_Casefold mapping changes\n")
+	}
+}
+
+// Writes the code points with full case folding changes
+func renderCaseFolding(buffer *strings.Builder, heading string, caseFolding *CaseFoldingChanges, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	for i, change := range caseFolding.Changes {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# Code point; Old case folding; New case folding; Old derived property value; New derived property value\n")
+		}
+		fmt.Fprintf(buffer, "%s; %s; %s; %s; %s\n", opts.codePointLabel(change.CodePoint), change.Old, change.New, change.OldProperty, change.NewProperty)
+	}
+	if len(caseFolding.Changes) == 0 {
+		fmt.Fprintf(buffer, "# No case folding changes\n")
+	}
+}
+
+// Writes the code points whose decomposition type changed
+func renderDecompositionTypes(buffer *strings.Builder, heading string, decompositions *DecompositionChanges, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	for i, change := range decompositions.Changes {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# Code point; Old type; New type; Old decomposition; New decomposition; Old derived property value; New derived property value; Name\n")
+		}
+		fmt.Fprintf(buffer, "%s; %s; %s; %s; %s; %s; %s; %s\n", opts.codePointLabel(change.CodePoint), change.Old, change.New, change.OldDecomposition, change.NewDecomposition, change.OldProperty, change.NewProperty, isolate(change.Name))
+	}
+	if len(decompositions.Changes) == 0 {
+		fmt.Fprintf(buffer, "# No decomposition type changes\n")
+	}
+}
+
+// Writes the code points whose full decomposition changed
+func renderDecompositionMappings(buffer *strings.Builder, heading string, decompositions *DecompositionMappingChanges, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	for i, change := range decompositions.Changes {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# Code point; Old canonical; New canonical; Old compatibility; New compatibility; Old derived property value; New derived property value; Name\n")
+		}
+		fmt.Fprintf(buffer, "%s; %s; %s; %s; %s; %s; %s; %s\n", opts.codePointLabel(change.CodePoint), change.OldCanonical, change.NewCanonical, change.OldCompatibility, change.NewCompatibility, change.OldProperty, change.NewProperty, isolate(change.Name))
+	}
+	if len(decompositions.Changes) == 0 {
+		fmt.Fprintf(buffer, "# No decomposition mapping changes\n")
+	}
+}
+
+// Writes the assigned code points whose Bidi_Class changed
+func renderBidiClasses(buffer *strings.Builder, heading string, bidiClasses *BidiClassChanges, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	for i, change := range bidiClasses.Changes {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# Code point; Old Bidi_Class; New Bidi_Class; Old derived property value; New derived property value; Name\n")
+		}
+		fmt.Fprintf(buffer, "%s; %s; %s; %s; %s; %s\n", opts.codePointLabel(change.CodePoint), change.Old, change.New, change.OldProperty, change.NewProperty, isolate(change.Name))
+	}
+	if len(bidiClasses.Changes) == 0 {
+		fmt.Fprintf(buffer, "# No changes in Bidi_Class\n")
+	}
+}
+
+// Writes the assigned code points whose Joining_Type changed
+func renderJoiningTypes(buffer *strings.Builder, heading string, joiningTypes *JoiningTypeChanges, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	for i, change := range joiningTypes.Changes {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# Code point; Old Joining_Type; New Joining_Type; Old derived property value; New derived property value; Name\n")
+		}
+		fmt.Fprintf(buffer, "%s; %s; %s; %s; %s; %s\n", opts.codePointLabel(change.CodePoint), change.Old, change.New, change.OldProperty, change.NewProperty, isolate(change.Name))
+	}
+	if len(joiningTypes.Changes) == 0 {
+		fmt.Fprintf(buffer, "# No changes in Joining_Type\n")
+	}
+}
+
+// Writes the assigned code points whose Script changed
+func renderScriptChanges(buffer *strings.Builder, heading string, scriptChanges *ScriptChanges, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	for i, change := range scriptChanges.Changes {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# Code point; Old Script; New Script; Old derived property value; New derived property value; Name\n")
+		}
+		fmt.Fprintf(buffer, "%s; %s; %s; %s; %s; %s\n", opts.codePointLabel(change.CodePoint), change.Old, change.New, change.OldProperty, change.NewProperty, isolate(change.Name))
+	}
+	if len(scriptChanges.Changes) == 0 {
+		fmt.Fprintf(buffer, "# No changes in Script\n")
+	}
+}
+
+// Writes the assigned code points whose Canonical_Combining_Class changed,
+// flagging the changes to or from Virama
+func renderCombiningClasses(buffer *strings.Builder, heading string, combiningClasses *CombiningClassChanges, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	for i, change := range combiningClasses.Changes {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# Code point; Old ccc; New ccc; Old derived property value; New derived property value; Name\n")
+		}
+		fmt.Fprintf(buffer, "%s; %s; %s; %s; %s; %s", opts.codePointLabel(change.CodePoint), change.Old, change.New, change.OldProperty, change.NewProperty, isolate(change.Name))
+		if change.Virama {
+			fmt.Fprintf(buffer, " # VIRAMA, affects the CONTEXTJ rules of ZWNJ and ZWJ")
+		}
+		fmt.Fprintf(buffer, "\n")
+	}
+	if len(combiningClasses.Changes) == 0 {
+		fmt.Fprintf(buffer, "# No changes in Canonical_Combining_Class\n")
+	}
+}
+
+// Formats an NFKC_Casefold mapping, which is empty for code points that
+// NFKC_Casefold removes
+func caseFoldMapping(mapping string) string {
+	if mapping == "" {
+		return "<removed>"
+	}
+	return mapping
+}
+
+// Writes the newly assigned case pairs with unexpected derived property values
+func renderCaseConsistency(buffer *strings.Builder, heading string, consistency *CaseConsistency) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	for i, pair := range consistency.Anomalies {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# Uppercase; Property; Lowercase; Property # Names\n")
+		}
+		fmt.Fprintf(buffer, "U+%s; %s; U+%s; %s # %s / %s\n",
+			pair.Upper, pair.UpperProperty, pair.Lower, pair.LowerProperty, isolate(pair.UpperName), isolate(pair.LowerName))
+	}
+	if len(consistency.Anomalies) == 0 {
+		fmt.Fprintf(buffer, "# All %d newly assigned case pairs are DISALLOWED (uppercase) and PVALID (lowercase)\n", consistency.Checked)
+	}
+}
+
+// Writes the ranges of code points listed in only one of allcodepoints.txt
+// and DerivedGeneralCategory.txt
+func renderGCConsistency(buffer *strings.Builder, heading string, consistency *GCConsistency) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	formatRange := func(r CodePointRange) string {
+		if r.Start == r.End {
+			return "U+" + r.Start
+		}
+		return "U+" + r.Start + "..U+" + r.End
+	}
+	for _, version := range consistency.Versions {
+		fmt.Fprintf(buffer, "# Version %s\n", version.Version)
+		for _, missing := range version.MissingProperty {
+			comment := "no derived property value"
+			if version.Synthesized {
+				comment += ", compared as UNASSIGNED"
+			}
+			fmt.Fprintf(buffer, "%s # %s\n", formatRange(missing), comment)
+		}
+		for _, missing := range version.MissingCategory {
+			fmt.Fprintf(buffer, "%s # no General_Category\n", formatRange(missing))
+		}
+		if len(version.MissingProperty) == 0 && len(version.MissingCategory) == 0 {
+			fmt.Fprintf(buffer, "# allcodepoints.txt and DerivedGeneralCategory.txt list the same code points\n")
+		}
+	}
+}
+
+// Writes the code points whose derived property value held despite related
+// changes
+func renderInformational(buffer *strings.Builder, heading string, informational *Informational, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	for i, entry := range informational.Entries {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# Code point; Change; Old; New; Derived property value # Reason # Name\n")
+		}
+		fmt.Fprintf(buffer, "%s; %s; %s; %s; %s # %s # %s\n", opts.codePointLabel(entry.CodePoint), entry.Change, entry.Old, entry.New, entry.Property, entry.Reason, isolate(entry.Name))
+	}
+	if len(informational.Entries) == 0 {
+		fmt.Fprintf(buffer, "# No code points with related changes that kept their derived property value\n")
+	}
+}
+
+// Writes why the derived property values in Appendix A changed
+func renderRootCauses(buffer *strings.Builder, heading string, causes *RootCauses, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	for i, entry := range causes.Entries {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# Code point; Old; New; Old rule; New rule # Property changes # Name\n")
+		}
+		changes := strings.Join(entry.Changes, ", ")
+		if changes == "" {
+			changes = "none in the UCD files"
+		}
+		if entry.Note != "" {
+			changes += ", " + entry.Note
+		}
+		fmt.Fprintf(buffer, "%s; %s; %s; %s; %s # %s # %s\n", opts.codePointLabel(entry.CodePoint), entry.Old, entry.New, entry.OldRule, entry.NewRule, changes, isolate(entry.Name))
+	}
+	if len(causes.Entries) == 0 {
+		fmt.Fprintf(buffer, "# No changes in Appendix A\n")
+	}
+}
+
+// Writes how the code points in Appendix C are likely rendered
+func renderMarkRendering(buffer *strings.Builder, heading string, rendering *MarkRendering, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	for i, entry := range rendering.Entries {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# Code point; Rendering; Indic_Syllabic_Category; Indic_Positional_Category; Canonical_Combining_Class; Script; Script_Extensions # Name\n")
+		}
+		// Script_Extensions is only listed where it is not just the script
+		extensions := entry.ScriptExtensions
+		if extensions == "" {
+			extensions = "-"
+		}
+		fmt.Fprintf(buffer, "%s; %s; %s; %s; %s; %s; %s # %s\n", opts.codePointLabel(entry.CodePoint), entry.Rendering, entry.SyllabicCategory, entry.PositionalCategory, entry.CombiningClass, entry.Script, extensions, isolate(entry.Name))
+	}
+	if len(rendering.Entries) == 0 {
+		fmt.Fprintf(buffer, "# No new code points with General Category Mn\n")
+	}
+}
+
+// Writes the code points that became PVALID in restricted scripts
+func renderRestrictedScripts(buffer *strings.Builder, heading string, restricted *RestrictedScripts, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	for i, entry := range restricted.Entries {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# Code point; Old; Script # Reason # Name\n")
+		}
+		fmt.Fprintf(buffer, "%s; %s; %s # %s # %s\n", opts.codePointLabel(entry.CodePoint), entry.Old, entry.Script, entry.Reason, isolate(entry.Name))
+	}
+	if len(restricted.Entries) == 0 {
+		fmt.Fprintf(buffer, "# No code points became PVALID in the restricted scripts\n")
+	}
+}
+
+// Writes the code points where the derived property value and UTS #46 disagree
+func renderUTS46(buffer *strings.Builder, heading string, comparison *UTS46Comparison, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	for i, entry := range comparison.Discrepancies {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# Code point; Derived property; UTS #46 status; Name\n")
+		}
+		status := entry.Status
+		if entry.IDNA2008Status != "" {
+			status += " " + entry.IDNA2008Status
+		}
+		fmt.Fprintf(buffer, "%s; %s; %s; %s\n", opts.codePointLabel(entry.CodePoint), entry.Property, status, isolate(entry.Name))
+	}
+	if len(comparison.Discrepancies) == 0 {
+		fmt.Fprintf(buffer, "# No differences in %d code points\n", comparison.Checked)
+	}
+}
+
+// Writes the number of code points per derived property value in both versions
+func renderFrequencies(buffer *strings.Builder, heading string, r *Report) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	fmt.Fprintf(buffer, "# Property; %s; %s; Change\n", r.Version1, r.Version2)
+	for _, value := range r.Frequencies.Values {
+		fmt.Fprintf(buffer, "%s; %d; %d; %+d\n", value.Property, value.Count1, value.Count2, value.Count2-value.Count1)
+	}
+	fmt.Fprintf(buffer, "Total; %d; %d; %+d\n", r.Frequencies.Total1, r.Frequencies.Total2, r.Frequencies.Total2-r.Frequencies.Total1)
+}
+
+// Writes the newly assigned code points, a line per range
+func renderNewAssignments(buffer *strings.Builder, heading string, assignments *NewAssignments, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	for i, r := range assignments.Ranges {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# Code point; Derived property; Name\n")
+		}
+		if r.Start == r.End {
+			fmt.Fprintf(buffer, "%s; %s; %s\n", opts.codePointLabel(r.Start), r.Property, isolate(r.Name))
+		} else {
+			fmt.Fprintf(buffer, "U+%s..U+%s; %s; %s\n", r.Start, r.End, r.Property, isolate(r.Name))
+		}
+	}
+	if len(assignments.Ranges) == 0 {
+		fmt.Fprintf(buffer, "# No newly assigned code points\n")
+	}
+}
+
+// Writes the code points that became valid and matter for the Bidi Rule,
+// followed by their number per script and Bidi_Class
+func renderBidiImpact(buffer *strings.Builder, heading string, impact *BidiImpact, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	for i, entry := range impact.Entries {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# Code point; Old; New; Bidi_Class; Script; Name\n")
+		}
+		old := entry.Old
+		if old == "" {
+			old = "UNASSIGNED"
+		}
+		fmt.Fprintf(buffer, "%s; %s; %s; %s; %s; %s\n", opts.codePointLabel(entry.CodePoint), old, entry.New, entry.BidiClass, entry.Script, isolate(entry.Name))
+	}
+	if len(impact.Entries) == 0 {
+		fmt.Fprintf(buffer, "# No new valid right-to-left letters or digits\n")
+		return
+	}
+
+	fmt.Fprintf(buffer, "\n")
+	table := newTable(buffer)
+	fmt.Fprintf(table, "# Script\tBidi_Class\tCode points\n")
+	for _, count := range impact.Counts {
+		fmt.Fprintf(table, "# %s\t%s (%s)\t%d\n", count.Script, count.BidiClass, bidiRuleClasses[count.BidiClass], count.Count)
+	}
+	table.Flush()
+}
+
+// Writes the differences between the appendices A-E and those of a published
+// review document
+func renderCrossCheck(buffer *strings.Builder, heading string, check *CrossCheck) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	table := newTable(buffer)
+	fmt.Fprintf(table, "# Appendix\tPublished\tComputed\tOnly published\tOnly computed\n")
+	for _, appendix := range check.Appendices {
+		fmt.Fprintf(table, "# %s\t%d\t%d\t%d\t%d\n", appendix.Appendix, appendix.Published, appendix.Computed, len(appendix.OnlyPublished), len(appendix.OnlyComputed))
+	}
+	table.Flush()
+	for _, appendix := range check.Appendices {
+		for _, codepoint := range appendix.OnlyPublished {
+			fmt.Fprintf(buffer, "U+%s; only in the published Appendix %s\n", codepoint, appendix.Appendix)
+		}
+		for _, codepoint := range appendix.OnlyComputed {
+			fmt.Fprintf(buffer, "U+%s; only in the computed Appendix %s\n", codepoint, appendix.Appendix)
+		}
+	}
+}
+
+// Writes the code points that became PVALID and are confusable, most risky
+// first
+func renderHomoglyphs(buffer *strings.Builder, heading string, risks *HomoglyphRisks, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	for i, entry := range risks.Entries {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# Code point; Score; Script; Prototype; Prototype scripts; Name # Flags\n")
+		}
+		fmt.Fprintf(buffer, "%s; %d; %s; %s; %s; %s # %s\n", opts.codePointLabel(entry.CodePoint), entry.Score, entry.Script, entry.Prototype,
+			strings.Join(entry.PrototypeScripts, " "), isolate(entry.Name), strings.Join(entry.Flags, ", "))
+	}
+	if len(risks.Entries) == 0 {
+		fmt.Fprintf(buffer, "# None of the %d code points that became PVALID are confusable\n", risks.Checked)
+	}
+
+	for _, script := range risks.NewScripts {
+		fmt.Fprintf(buffer, "\n# New script %s with %d PVALID code points", script.Script, script.PVALID)
+		if len(script.ConfusableWith) == 0 {
+			fmt.Fprintf(buffer, ", not whole-script confusable\n")
+			continue
+		}
+		fmt.Fprintf(buffer, ", whole-script confusable with:\n")
+		for _, confusables := range script.ConfusableWith {
+			fmt.Fprintf(buffer, "# %s (%d): U+%s\n", confusables.Script, len(confusables.CodePoints), strings.Join(confusables.CodePoints, " U+"))
+		}
+	}
+}
+
+// Writes what an additional change detector found
+func renderFindings(buffer *strings.Builder, heading string, findings DetectorFindings, opts RenderOptions) {
+	fmt.Fprintf(buffer, "\n%s\n\n", heading)
+	for i, finding := range findings.Findings {
+		if i == 0 {
+			fmt.Fprintf(buffer, "# Code point; Old; New; Old property; New property; Name\n")
+		}
+		fmt.Fprintf(buffer, "%s; %s; %s; %s; %s; %s\n", opts.codePointLabel(finding.CodePoint), finding.Old, finding.New, finding.OldProperty, finding.NewProperty, isolate(finding.Name))
+	}
+	if len(findings.Findings) == 0 {
+		fmt.Fprintf(buffer, "# Nothing found\n")
+	}
+}
@@ -0,0 +1,220 @@
+package idndiff
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// A stub of an issue for the review of a code point in Appendix E, or of a
+// range of consecutive code points with the same evidence
+type ReviewTicket struct {
+	Title string `json:"title"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// Derived property values in the first and the second version
+	Old        string      `json:"old"`
+	New        string      `json:"new"`
+	CodePoints []CodePoint `json:"code_points"`
+	// The appendices (A, C or D) that made the code points candidates
+	Sources     []string `json:"sources"`
+	Evidence    []string `json:"evidence"`
+	Disposition string   `json:"disposition"`
+	// The properties of the code points, with -snapshots
+	Snapshots []PropertySnapshot `json:"snapshots,omitempty"`
+}
+
+// Returns a ticket per code point in Appendix E that is not excluded from
+// review, with consecutive code points with the same sources and derived
+// property values in one ticket
+func ReviewTickets(r *Report) []ReviewTicket {
+	return reviewTickets(r, true)
+}
+
+// Returns a ticket per code point in Appendix E that is not excluded from
+// review, for issue trackers where each candidate is decided on by itself
+func CandidateTickets(r *Report) []ReviewTicket {
+	return reviewTickets(r, false)
+}
+
+// Returns the tickets for the code points in Appendix E that are not
+// excluded from review, with ranges of them in one ticket if merge is set
+func reviewTickets(r *Report, merge bool) []ReviewTicket {
+	type candidate struct {
+		codepoint, name, old, new string
+		sources                   []string
+	}
+	// Appendix E lists a code point once per appendix it was found in
+	var candidates []*candidate
+	byCodepoint := make(map[string]*candidate)
+	for _, entry := range r.AppendixE {
+		if entry.Excluded {
+			continue
+		}
+		if c, ok := byCodepoint[entry.CodePoint]; ok {
+			c.sources = append(c.sources, entry.Source)
+			continue
+		}
+		c := &candidate{entry.CodePoint, entry.Name, entry.Property, entry.Property, []string{entry.Source}}
+		candidates = append(candidates, c)
+		byCodepoint[entry.CodePoint] = c
+	}
+	for _, delta := range r.Delta {
+		for codepoint := hexToInt(delta.Start); codepoint <= hexToInt(delta.End); codepoint++ {
+			if c, ok := byCodepoint[codepointKey(codepoint)]; ok {
+				c.old = delta.Old
+			}
+		}
+	}
+	nfk := make(map[string]string)
+	for _, entry := range r.AppendixD {
+		nfk[entry.CodePoint] = entry.NFK
+	}
+	snapshots := make(map[string]PropertySnapshot)
+	for _, snapshot := range r.Snapshots {
+		snapshots[snapshot.CodePoint] = snapshot
+	}
+
+	var tickets []ReviewTicket
+	for i, c := range candidates {
+		if merge && i > 0 {
+			previous := candidates[i-1]
+			last := &tickets[len(tickets)-1]
+			if hexToInt(c.codepoint) == hexToInt(previous.codepoint)+1 && c.old == previous.old && c.new == previous.new && slices.Equal(c.sources, previous.sources) {
+				last.End = c.codepoint
+				last.CodePoints = append(last.CodePoints, CodePoint{c.codepoint, c.name})
+				continue
+			}
+		}
+		ticket := ReviewTicket{
+			Start:      c.codepoint,
+			End:        c.codepoint,
+			Old:        c.old,
+			New:        c.new,
+			CodePoints: []CodePoint{{c.codepoint, c.name}},
+			Sources:    c.sources,
+		}
+		for _, source := range c.sources {
+			ticket.Evidence = append(ticket.Evidence, candidateReasons[source])
+		}
+		ticket.Disposition = suggestDisposition(ticket)
+		tickets = append(tickets, ticket)
+	}
+
+	for i := range tickets {
+		ticket := &tickets[i]
+		if ticket.Start == ticket.End {
+			ticket.Title = fmt.Sprintf("Unicode %s: review U+%s %s", r.Version2, ticket.Start, ticket.CodePoints[0].Name)
+		} else {
+			ticket.Title = fmt.Sprintf("Unicode %s: review U+%s..U+%s (%d code points)", r.Version2, ticket.Start, ticket.End, len(ticket.CodePoints))
+		}
+		if ticket.Old != ticket.New {
+			ticket.Title += fmt.Sprintf(", %s to %s", ticket.Old, ticket.New)
+		}
+		for _, codepoint := range ticket.CodePoints {
+			if normalization, ok := nfk[codepoint.CodePoint]; ok {
+				ticket.Evidence = append(ticket.Evidence, fmt.Sprintf("U+%s has the NFK normalization %s", codepoint.CodePoint, normalization))
+			}
+			if snapshot, ok := snapshots[codepoint.CodePoint]; ok {
+				ticket.Snapshots = append(ticket.Snapshots, snapshot)
+			}
+		}
+	}
+	return tickets
+}
+
+// Returns the disposition to suggest for a ticket, as a starting point for
+// the review
+func suggestDisposition(ticket ReviewTicket) string {
+	switch {
+	case slices.Contains(ticket.Sources, "D"):
+		return "DISALLOWED, like other code points with an NFK normalization, unless the code points are needed as they are in labels"
+	case slices.Contains(ticket.Sources, "A") && ticket.New == "DISALLOWED":
+		return fmt.Sprintf("Accept DISALLOWED, or keep %s with BackwardCompatible (G) if labels with the code points are in use", ticket.Old)
+	case slices.Contains(ticket.Sources, "A"):
+		return fmt.Sprintf("Accept %s, unless the change makes existing labels ambiguous", ticket.New)
+	}
+	return "PVALID, unless the combining marks are only used in ways that do not belong in labels"
+}
+
+// Returns the name of the file of a ticket, without extension
+func ticketFileName(ticket ReviewTicket) string {
+	if ticket.Start == ticket.End {
+		return "U+" + ticket.Start
+	}
+	return "U+" + ticket.Start + "..U+" + ticket.End
+}
+
+// Writes a ticket as Markdown
+func WriteTicketMarkdown(w io.Writer, r *Report, ticket ReviewTicket, opts RenderOptions) error {
+	buffered := bufio.NewWriter(w)
+	fmt.Fprintf(buffered, "# %s\n\n", isolate(ticket.Title))
+	fmt.Fprintf(buffered, "Unicode %s compared with Unicode %s. Derived property value: %s, was %s.\n\n", r.Version2, r.Version1, ticket.New, ticket.Old)
+	for _, codepoint := range ticket.CodePoints {
+		fmt.Fprintf(buffered, "- %s %s", escapeMarkdown(opts.codePointLabel(codepoint.CodePoint)), isolate(codepoint.Name))
+		if chart := ChartURL(r, codepoint.CodePoint); chart != "" {
+			fmt.Fprintf(buffered, " ([chart](%s))", chart)
+		}
+		fmt.Fprintf(buffered, "\n")
+	}
+	fmt.Fprintf(buffered, "\n## Evidence\n\n")
+	for _, evidence := range ticket.Evidence {
+		fmt.Fprintf(buffered, "- %s\n", evidence)
+	}
+	if len(ticket.Snapshots) > 0 {
+		fmt.Fprintf(buffered, "\n## Properties\n\n")
+		fmt.Fprintf(buffered, "| Code point | General Category | Script | Bidi_Class | Combining class | NFK | Age | Block |\n")
+		fmt.Fprintf(buffered, "|---|---|---|---|---|---|---|---|\n")
+		for _, s := range ticket.Snapshots {
+			fmt.Fprintf(buffered, "| %s | %s | %s | %s | %s | %s | %s | %s |\n", escapeMarkdown(opts.codePointLabel(s.CodePoint)), s.GeneralCategory, s.Script, s.BidiClass, s.CombiningClass, s.NFK, s.Age, s.Block)
+		}
+	}
+	fmt.Fprintf(buffered, "\n## Suggested disposition\n\n%s\n", ticket.Disposition)
+	return buffered.Flush()
+}
+
+// Escapes the characters that Markdown would take as markup, as shown with
+// -glyphs
+func escapeMarkdown(s string) string {
+	var escaped strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune("\\`*_[]|<>#", r) {
+			escaped.WriteRune('\\')
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
+
+// Writes a ticket as indented JSON
+func WriteTicketJSON(w io.Writer, r *Report, ticket ReviewTicket) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(ticket)
+}
+
+// Writes each ticket to a file of its own in a directory
+func WriteTickets(dir, extension string, write func(io.Writer, *Report, ReviewTicket) error, r *Report, tickets []ReviewTicket) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, ticket := range tickets {
+		file, err := os.Create(filepath.Join(dir, ticketFileName(ticket)+extension))
+		if err != nil {
+			return err
+		}
+		if err := write(file, r, ticket); err != nil {
+			file.Close()
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
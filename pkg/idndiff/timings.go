@@ -0,0 +1,34 @@
+package idndiff
+
+import "time"
+
+// How long each phase of a comparison took, in the order they ran. Set
+// Options.Timings to a Timings to record them.
+type Timings struct {
+	Phases []PhaseTiming
+	last   time.Time
+}
+
+// A phase of a comparison, such as reading the files of a version or
+// computing an appendix
+type PhaseTiming struct {
+	Phase    string
+	Duration time.Duration
+}
+
+// Starts timing the first phase. Nothing is recorded with a nil Timings.
+func (t *Timings) start() {
+	if t != nil {
+		t.last = time.Now()
+	}
+}
+
+// Records that a phase ended, and starts timing the next one
+func (t *Timings) mark(phase string) {
+	if t == nil {
+		return
+	}
+	now := time.Now()
+	t.Phases = append(t.Phases, PhaseTiming{phase, now.Sub(t.last)})
+	t.last = now
+}
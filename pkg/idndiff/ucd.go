@@ -0,0 +1,113 @@
+package idndiff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The fields of a line in UnicodeData.txt that are used
+type unicodeDataEntry struct {
+	Name            string
+	GeneralCategory string
+	CombiningClass  string
+	BidiClass       string
+	Decomposition   string
+	Uppercase       string // Simple uppercase mapping, empty if none
+	Lowercase       string // Simple lowercase mapping, empty if none
+	Titlecase       string // Simple titlecase mapping, empty if none
+}
+
+// Reads UnicodeData.txt. Ranges given as "<..., First>" and "<..., Last>" lines
+// are expanded to all code points in the range.
+func readUnicodeData(r io.Reader) (map[string]unicodeDataEntry, error) {
+	entries := make(map[string]unicodeDataEntry)
+
+	scanner := newLineScanner(r)
+	lineNumber := 0
+	rangeStart := -1
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ";")
+		if len(fields) < 15 {
+			return nil, fmt.Errorf("line %d: expected 15 fields, found %d", lineNumber, len(fields))
+		}
+		codepoint, err := parseCodepoint(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		entry := unicodeDataEntry{
+			Name:            fields[1],
+			GeneralCategory: fields[2],
+			CombiningClass:  fields[3],
+			BidiClass:       fields[4],
+			Decomposition:   fields[5],
+			Uppercase:       fields[12],
+			Lowercase:       fields[13],
+			Titlecase:       strings.TrimSpace(fields[14]),
+		}
+
+		switch {
+		case strings.HasSuffix(entry.Name, ", First>"):
+			rangeStart = int(codepoint)
+			continue
+		case strings.HasSuffix(entry.Name, ", Last>") && rangeStart >= 0:
+			// Name the code points in the range after the range, e.g. "CJK Ideograph"
+			entry.Name = "<" + strings.TrimSuffix(strings.TrimPrefix(entry.Name, "<"), ", Last>") + ">"
+			for i := rangeStart; i <= int(codepoint); i++ {
+				entries[codepointKey(i)] = entry
+			}
+			rangeStart = -1
+		default:
+			entries[codepointKey(int(codepoint))] = entry
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Reads the code points that have a binary property from a UCD file with
+// lines on the form "0041..005A ; Property # comment", such as PropList.txt.
+// A property given as "Property=Value" matches lines on the form
+// "00A0 ; Property; Value # comment" instead.
+func readBinaryProperty(r io.Reader, property string) (map[string]bool, error) {
+	codepoints := make(map[string]bool)
+	property, value, hasValue := strings.Cut(property, "=")
+
+	scanner := newLineScanner(r)
+	for scanner.Scan() {
+		fields := strings.Split(strings.Split(scanner.Text(), "#")[0], ";")
+		if len(fields) < 2 || strings.TrimSpace(fields[1]) != property {
+			continue
+		}
+		if hasValue && (len(fields) < 3 || strings.TrimSpace(fields[2]) != value) {
+			continue
+		}
+		first, last, isRange := strings.Cut(strings.TrimSpace(fields[0]), "..")
+		if !isRange {
+			last = first
+		}
+		start, err1 := parseCodepoint(first)
+		end, err2 := parseCodepoint(last)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		for i := start; i <= end; i++ {
+			codepoints[codepointKey(int(i))] = true
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return codepoints, nil
+}
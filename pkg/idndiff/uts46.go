@@ -0,0 +1,98 @@
+package idndiff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The status of a code point in the UTS #46 IDNA Mapping Table
+type uts46Entry struct {
+	Status         string // valid, mapped, deviation, ignored, disallowed, ...
+	IDNA2008Status string // NV8 or XV8 for code points not valid in IDNA2008
+}
+
+// Reads IdnaMappingTable.txt, the UTS #46 data that ICU's uidna is built
+// from, with lines like "0041 ; mapped ; 0061 # LATIN CAPITAL LETTER A" and
+// "00A1..00A7 ; valid ; ; NV8 # INVERTED EXCLAMATION MARK..SECTION SIGN"
+func readIdnaMappingTable(r io.Reader) (map[string]uts46Entry, error) {
+	entries := make(map[string]uts46Entry)
+
+	scanner := newLineScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		fields := strings.Split(strings.Split(scanner.Text(), "#")[0], ";")
+		if len(fields) < 2 {
+			continue
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		entry := uts46Entry{Status: fields[1]}
+		if len(fields) > 3 {
+			entry.IDNA2008Status = fields[3]
+		}
+
+		first, last, isRange := strings.Cut(fields[0], "..")
+		if !isRange {
+			last = first
+		}
+		start, err1 := parseCodepoint(first)
+		end, err2 := parseCodepoint(last)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("line %d: invalid code point range %q", lineNumber, fields[0])
+		}
+		for i := start; i <= end; i++ {
+			entries[codepointKey(int(i))] = entry
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Reports whether UTS #46 treats a code point as valid in IDNA2008, i.e. as
+// PVALID, CONTEXTJ or CONTEXTO
+func (e uts46Entry) validInIDNA2008() bool {
+	switch e.Status {
+	case "valid":
+		return e.IDNA2008Status == ""
+	case "deviation":
+		// U+00DF, U+03C2, U+200C and U+200D
+		return true
+	}
+	return false
+}
+
+// Compares the derived property values with the status of each code point in
+// UTS #46, returning the code points where they disagree about whether the
+// code point is valid in IDNA2008
+func compareUTS46(codepoints []int, properties2, codePointNames2 map[string]string, table map[string]uts46Entry) *UTS46Comparison {
+	comparison := &UTS46Comparison{}
+	for _, codepointInt := range codepoints {
+		codepoint := codepointKey(codepointInt)
+		property := properties2[codepoint]
+		entry, ok := table[codepoint]
+		if !ok || codepoint == "002E" {
+			// UTS #46 lists FULL STOP as valid since it separates labels
+			continue
+		}
+		comparison.Checked++
+
+		valid := property == "PVALID" || property == "CONTEXTJ" || property == "CONTEXTO"
+		if valid != entry.validInIDNA2008() {
+			comparison.Discrepancies = append(comparison.Discrepancies, UTS46Discrepancy{
+				CodePoint:      codepoint,
+				Property:       property,
+				Status:         entry.Status,
+				IDNA2008Status: entry.IDNA2008Status,
+				Name:           codePointNames2[codepoint],
+			})
+		}
+	}
+	return comparison
+}
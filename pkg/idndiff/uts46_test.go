@@ -0,0 +1,46 @@
+package idndiff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// The code points that UTS #46 treats as valid in IDNA2008 are those that are
+// PVALID, CONTEXTJ or CONTEXTO, and any others are listed
+func TestCompareUTS46(t *testing.T) {
+	table, err := readIdnaMappingTable(strings.NewReader(`# IdnaMappingTable.txt
+002D..002E    ; valid                                  # 1.1  HYPHEN-MINUS..FULL STOP
+0041          ; mapped                 ; 0061          # 1.1  LATIN CAPITAL LETTER A
+0061          ; valid                                  # 1.1  LATIN SMALL LETTER A
+00A1..00A2    ; valid                  ;      ; NV8    # 1.1  INVERTED EXCLAMATION MARK..CENT SIGN
+00AD          ; ignored                                # 1.1  SOFT HYPHEN
+00DF          ; deviation              ; 0073 0073     # 1.1  LATIN SMALL LETTER SHARP S
+0B55          ; valid                                  # 13.0 ORIYA SIGN OVERLINE
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	properties := map[string]string{"002D": "PVALID", "002E": "DISALLOWED", "0041": "DISALLOWED", "0061": "PVALID", "00A1": "DISALLOWED", "00A2": "PVALID", "00AD": "DISALLOWED", "00DF": "PVALID", "0B55": "DISALLOWED", "0B56": "PVALID"}
+	names := map[string]string{"00A2": "CENT SIGN", "0B55": "ORIYA SIGN OVERLINE"}
+	codepoints := []int{0x2D, 0x2E, 0x41, 0x61, 0xA1, 0xA2, 0xAD, 0xDF, 0xB55, 0xB56}
+	comparison := compareUTS46(codepoints, properties, names, table)
+	want := &UTS46Comparison{
+		// Not FULL STOP, which separates labels, nor U+0B56, which the
+		// table does not list
+		Checked: 8,
+		Discrepancies: []UTS46Discrepancy{
+			{"00A2", "PVALID", "valid", "NV8", "CENT SIGN"},
+			{"0B55", "DISALLOWED", "valid", "", "ORIYA SIGN OVERLINE"},
+		},
+	}
+	if !reflect.DeepEqual(comparison, want) {
+		t.Errorf("got %+v, want %+v", comparison, want)
+	}
+
+	for _, invalid := range []string{"00G1 ; valid\n", "0041..U+XYZ ; mapped ; 0061\n"} {
+		if _, err := readIdnaMappingTable(strings.NewReader(invalid)); err == nil {
+			t.Errorf("%q: read without an error", invalid)
+		}
+	}
+}
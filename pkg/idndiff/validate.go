@@ -0,0 +1,97 @@
+package idndiff
+
+import (
+	"errors"
+	"io/fs"
+)
+
+// How a data file of a version was found by Validate
+type FileCheck struct {
+	Name     string
+	Required bool   // Needed by every comparison, rather than by some sections
+	Status   string // "ok", "missing" or "invalid"
+	Error    string // Why the file is invalid
+}
+
+// The data files of a version that Validate reads, and how
+var validatedFiles = []struct {
+	name     string
+	required bool
+	read     func(l *Loader, version string) error
+}{
+	{"allcodepoints.txt", true, func(l *Loader, version string) error {
+		_, _, err := l.CodepointProperties(version)
+		return err
+	}},
+	{"DerivedGeneralCategory.txt", true, func(l *Loader, version string) error {
+		_, err := l.PropertyFile(version, "DerivedGeneralCategory.txt")
+		return err
+	}},
+	{"nfk.txt", true, func(l *Loader, version string) error {
+		_, err := l.NFKData(version)
+		return err
+	}},
+	{"UnicodeData.txt", false, func(l *Loader, version string) error {
+		_, err := l.UnicodeData(version)
+		return err
+	}},
+	{"DerivedNormalizationProps.txt", false, func(l *Loader, version string) error {
+		_, err := l.NFKCCaseFold(version)
+		return err
+	}},
+	{"CaseFolding.txt", false, func(l *Loader, version string) error {
+		_, err := l.CaseFolding(version)
+		return err
+	}},
+	{"DerivedCoreProperties.txt", false, func(l *Loader, version string) error {
+		_, err := l.BinaryProperty(version, "DerivedCoreProperties.txt", "Default_Ignorable_Code_Point")
+		return err
+	}},
+	{"PropList.txt", false, func(l *Loader, version string) error {
+		_, err := l.BinaryProperty(version, "PropList.txt", "White_Space")
+		return err
+	}},
+	{"Blocks.txt", false, func(l *Loader, version string) error {
+		_, err := l.PropertyFile(version, "Blocks.txt")
+		return err
+	}},
+	{"HangulSyllableType.txt", false, func(l *Loader, version string) error {
+		_, err := l.PropertyFile(version, "HangulSyllableType.txt")
+		return err
+	}},
+	{"Scripts.txt", false, func(l *Loader, version string) error {
+		_, err := l.PropertyFile(version, "Scripts.txt")
+		return err
+	}},
+	{"NameAliases.txt", false, func(l *Loader, version string) error {
+		_, err := l.NameAliases(version)
+		return err
+	}},
+	{"IdnaMappingTable.txt", false, func(l *Loader, version string) error {
+		_, err := l.IdnaMappingTable(version)
+		return err
+	}},
+	{"confusables.txt", false, func(l *Loader, version string) error {
+		_, err := l.Confusables(version)
+		return err
+	}},
+}
+
+// Reads each data file of a version that the comparison can use, and returns
+// whether it is there and can be parsed. Code points listed more than once
+// are an error with Loader.DuplicatePolicy "error", and are otherwise in
+// Loader.DuplicateWarnings.
+func Validate(loader *Loader, version string) []FileCheck {
+	var checks []FileCheck
+	for _, file := range validatedFiles {
+		check := FileCheck{Name: file.name, Required: file.required, Status: "ok"}
+		if err := file.read(loader, version); errors.Is(err, fs.ErrNotExist) {
+			check.Status = "missing"
+		} else if err != nil {
+			check.Status = "invalid"
+			check.Error = err.Error()
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
@@ -0,0 +1,87 @@
+package idndiff
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Renders the appendices as xml2rfc v3 (RFC 7991) sections, each with its
+// tables in a <sourcecode> element, inside a <back> element, so that they can
+// be dropped into an Internet-Draft as they are. Each row is a line with the
+// cells separated by "; ", starting with the code point, which is what
+// -cross-check finds the code points of the appendices by, as it finds the
+// appendices by their titles.
+func RenderXML2RFC(w io.Writer, r *Report, opts RenderOptions) error {
+	var buffer strings.Builder
+	fmt.Fprintf(&buffer, "<!-- Unicode %s compared with Unicode %s for IDNA2008 -->\n", r.Version2, r.Version1)
+	fmt.Fprintf(&buffer, "<back>\n")
+	for _, appendix := range reportAppendices(r) {
+		var body strings.Builder
+		if r.Skipped(appendix.letter) {
+			fmt.Fprintf(&body, "# Skipped, see the warnings in the summary\n")
+		} else {
+			for _, table := range appendix.tables() {
+				if len(table.rows) == 0 && table.empty == "" {
+					continue
+				}
+				if body.Len() > 0 {
+					fmt.Fprintf(&body, "\n")
+				}
+				writeSourceTable(&body, appendix.letter, table, opts)
+			}
+		}
+
+		fmt.Fprintf(&buffer, "  <section anchor=\"appendix-%s\">\n", strings.ToLower(appendix.letter))
+		fmt.Fprintf(&buffer, "    <name>%s</name>\n", escapeXML(appendix.title))
+		fmt.Fprintf(&buffer, "    <sourcecode><![CDATA[\n%s]]></sourcecode>\n", strings.ReplaceAll(foldLines(body.String(), opts.LineWidth), "]]>", "]]]]><![CDATA[>"))
+		fmt.Fprintf(&buffer, "  </section>\n")
+	}
+	fmt.Fprintf(&buffer, "</back>\n")
+	_, err := io.WriteString(w, buffer.String())
+	return err
+}
+
+// Writes a table of an appendix as lines of source code: the caption and the
+// columns as comments, and a line per row, with at most MaxEntries rows of
+// entries
+func writeSourceTable(buffer *strings.Builder, letter string, table appendixTable, opts RenderOptions) {
+	if table.caption != "" {
+		fmt.Fprintf(buffer, "# %s\n", table.caption)
+	}
+	if len(table.rows) == 0 {
+		fmt.Fprintf(buffer, "# %s\n", table.empty)
+		return
+	}
+	fmt.Fprintf(buffer, "# %s\n", strings.Join(table.columns, "; "))
+	for i, row := range table.rows {
+		if !table.summary && opts.MaxEntries > 0 && i == opts.MaxEntries {
+			fmt.Fprintf(buffer, "# %d more, all %d entries are in %s\n", len(table.rows)-opts.MaxEntries, len(table.rows), opts.OverflowFileName(letter))
+			break
+		}
+		cells := make([]string, len(row.cells))
+		for i, cell := range row.cells {
+			if isNameColumn(table.columns[i]) {
+				cell = isolate(cell)
+			}
+			cells[i] = cell
+		}
+		if row.codepoint != "" {
+			cells[0] = opts.codePointLabel(row.codepoint)
+		}
+		// Without the empty cells at the end, such as the categories of a
+		// range of Appendix F without any
+		for len(cells) > 1 && cells[len(cells)-1] == "" {
+			cells = cells[:len(cells)-1]
+		}
+		fmt.Fprintf(buffer, "%s\n", strings.Join(cells, "; "))
+	}
+}
+
+// Escapes text for XML
+func escapeXML(s string) string {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(s))
+	return escaped.String()
+}
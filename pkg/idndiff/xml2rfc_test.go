@@ -0,0 +1,68 @@
+package idndiff
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+// The xml2rfc report is well-formed XML with a section per appendix, whose
+// <sourcecode> has the rows as they are, even those with "]]>" in them, folded
+// to LineWidth
+func TestRenderXML2RFC(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		report Report
+		opts   RenderOptions
+		want   map[string]string // The source code of appendices, by anchor
+	}{
+		{
+			name:   "entries",
+			report: Report{AppendixA: []PropertyChange{{"0B55", "DISALLOWED", "PVALID", "ORIYA SIGN OVERLINE"}}},
+			want: map[string]string{
+				"appendix-a": "\n# Code point; Old; New; Name\nU+0B55; DISALLOWED; PVALID; ORIYA SIGN OVERLINE\n",
+				"appendix-c": "\n# No new code points with General Category Mn\n",
+			},
+		},
+		{
+			name:   "end of CDATA in a name",
+			report: Report{AppendixC: []CodePoint{{"0B55", "A NAME WITH ]]> IN IT"}}},
+			want:   map[string]string{"appendix-c": "\n# Code point; Name\nU+0B55; A NAME WITH ]]> IN IT\n"},
+		},
+		{
+			name:   "skipped",
+			report: Report{AppendixC: []CodePoint{{"0B55", "ORIYA SIGN OVERLINE"}}, SkippedAppendices: []string{"C"}},
+			want:   map[string]string{"appendix-c": "\n# Skipped, see the warnings in the summary\n"},
+		},
+		{
+			name:   "folded",
+			report: Report{AppendixC: []CodePoint{{"0B55", "ORIYA SIGN OVERLINE"}}},
+			opts:   RenderOptions{LineWidth: 16},
+			want:   map[string]string{"appendix-c": "\n# Code point;\n   Name\nU+0B55; ORIYA\n   SIGN OVERLINE\n"},
+		},
+	} {
+		var buffer strings.Builder
+		if err := RenderXML2RFC(&buffer, &test.report, test.opts); err != nil {
+			t.Fatal(err)
+		}
+		var back struct {
+			Sections []struct {
+				Anchor     string `xml:"anchor,attr"`
+				Name       string `xml:"name"`
+				SourceCode string `xml:"sourcecode"`
+			} `xml:"section"`
+		}
+		if err := xml.Unmarshal([]byte(buffer.String()), &back); err != nil {
+			t.Errorf("%s: %s in %s", test.name, err, buffer.String())
+			continue
+		}
+		if len(back.Sections) != len(reportAppendices(&test.report)) {
+			t.Errorf("%s: %d sections, want one per appendix", test.name, len(back.Sections))
+		}
+		for _, section := range back.Sections {
+			if want, ok := test.want[section.Anchor]; ok && section.SourceCode != want {
+				t.Errorf("%s: %s is %q, want %q", test.name, section.Anchor, section.SourceCode, want)
+			}
+		}
+	}
+}